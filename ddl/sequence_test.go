@@ -20,11 +20,16 @@ import (
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/ddl"
 	mysql "github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/model"
+	parser_mysql "github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/testkit"
 )
 
@@ -544,11 +549,13 @@ func (s *testSequenceSuite) TestSequenceFunction(c *C) {
 	tk.MustQuery("select lastval(seq)").Check(testkit.Rows("1"))
 	tk.MustQuery("select next value for seq").Check(testkit.Rows("2"))
 	tk.MustQuery("select lastval(seq)").Check(testkit.Rows("2"))
-	// setval won't change the last value.
+	// setval changes the last value only if it advances the sequence.
 	tk.MustQuery("select setval(seq, -1)").Check(testkit.Rows("<nil>"))
 	tk.MustQuery("select lastval(seq)").Check(testkit.Rows("2"))
 	tk.MustQuery("select setval(seq, 5)").Check(testkit.Rows("5"))
-	tk.MustQuery("select lastval(seq)").Check(testkit.Rows("2"))
+	tk.MustQuery("select lastval(seq)").Check(testkit.Rows("5"))
+	tk.MustQuery("select setval(seq, 4)").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("select lastval(seq)").Check(testkit.Rows("5"))
 	tk.MustQuery("select nextval(seq)").Check(testkit.Rows("6"))
 	tk.MustQuery("select nextval(seq)").Check(testkit.Rows("7"))
 
@@ -565,7 +572,7 @@ func (s *testSequenceSuite) TestSequenceFunction(c *C) {
 	c.Assert(round, Equals, int64(0))
 	// invalidate the current sequence cache.
 	tk.MustQuery("select setval(seq, 10)").Check(testkit.Rows("10"))
-	tk.MustQuery("select lastval(seq)").Check(testkit.Rows("3"))
+	tk.MustQuery("select lastval(seq)").Check(testkit.Rows("10"))
 	// trigger the next sequence cache.
 	tk.MustQuery("select nextval(seq)").Check(testkit.Rows("12"))
 	sequenceTable = testGetTableByName(c, tk.Se, "test", "seq")
@@ -576,7 +583,7 @@ func (s *testSequenceSuite) TestSequenceFunction(c *C) {
 	c.Assert(round, Equals, int64(0))
 	// invalidate the current sequence cache.
 	tk.MustQuery("select setval(seq, 13)").Check(testkit.Rows("13"))
-	tk.MustQuery("select lastval(seq)").Check(testkit.Rows("12"))
+	tk.MustQuery("select lastval(seq)").Check(testkit.Rows("13"))
 	// trigger the next sequence cache.
 	tk.MustQuery("select nextval(seq)").Check(testkit.Rows("1"))
 	sequenceTable = testGetTableByName(c, tk.Se, "test", "seq")
@@ -600,7 +607,9 @@ func (s *testSequenceSuite) TestSequenceFunction(c *C) {
 	c.Assert(round, Equals, int64(0))
 	// invalidate the current sequence cache.
 	tk.MustQuery("select setval(seq, -8)").Check(testkit.Rows("-8"))
-	tk.MustQuery("select lastval(seq)").Check(testkit.Rows("-2"))
+	tk.MustQuery("select lastval(seq)").Check(testkit.Rows("-8"))
+	tk.MustQuery("select setval(seq, -5)").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("select lastval(seq)").Check(testkit.Rows("-8"))
 	tk.MustQuery("select nextval(seq)").Check(testkit.Rows("10"))
 	sequenceTable = testGetTableByName(c, tk.Se, "test", "seq")
 	tc, ok = sequenceTable.(*tables.TableCommon)
@@ -704,6 +713,54 @@ func (s *testSequenceSuite) TestSequenceFunction(c *C) {
 	tk1.MustQuery("select setval(seq, -102)").Check(testkit.Rows("-102"))
 	tk.MustExec("drop sequence seq")
 
+	// test nextval_n reserves consecutive values from a single sequence cache.
+	tk.MustExec("create sequence seq increment 2 cache 5")
+	tk.MustQuery("select nextval_n(seq, 5)").Check(testkit.Rows("1"))
+	tk.MustQuery("select lastval(seq)").Check(testkit.Rows("9"))
+	tk.MustQuery("select nextval(seq)").Check(testkit.Rows("11"))
+	tk.MustQuery("select nextval_n(test.seq, 1)").Check(testkit.Rows("13"))
+	tk.MustQuery("select lastval(seq)").Check(testkit.Rows("13"))
+	// The rest of the cache (15, 17, 19) is not enough, so the block is reserved from the next cache.
+	tk.MustQuery("select nextval_n(seq, 4)").Check(testkit.Rows("21"))
+	tk.MustQuery("select lastval(seq)").Check(testkit.Rows("27"))
+	tk.MustQuery("select nextval_n(seq, null)").Check(testkit.Rows("<nil>"))
+	err = tk.QueryToErr("select nextval_n(seq, 0)")
+	c.Assert(err.Error(), Equals, "[expression:1210]Incorrect arguments to nextval_n")
+	err = tk.QueryToErr("select nextval_n(seq, -1)")
+	c.Assert(err.Error(), Equals, "[expression:1210]Incorrect arguments to nextval_n")
+	// The block larger than the cache is reserved from a dedicated batch.
+	tk.MustQuery("select nextval_n(seq, 6)").Check(testkit.Rows("31"))
+	tk.MustQuery("select lastval(seq)").Check(testkit.Rows("41"))
+	tk.MustQuery("select nextval(seq)").Check(testkit.Rows("43"))
+	tk.MustExec("drop sequence seq")
+	tk.MustExec("create sequence seq nocache")
+	tk.MustQuery("select nextval_n(seq, 2)").Check(testkit.Rows("1"))
+	tk.MustQuery("select nextval_n(seq, 1)").Check(testkit.Rows("3"))
+	tk.MustQuery("select nextval(seq)").Check(testkit.Rows("4"))
+	tk.MustExec("drop sequence seq")
+	tk.MustExec("create sequence seq")
+	tk.MustQuery("select nextval(seq)").Check(testkit.Rows("1"))
+	tk.MustQuery("select nextval_n(seq, 2000)").Check(testkit.Rows("1001"))
+	tk.MustQuery("select lastval(seq)").Check(testkit.Rows("3000"))
+	tk.MustQuery("select nextval(seq)").Check(testkit.Rows("3001"))
+	tk.MustExec("drop sequence seq")
+	// The values are not lost when the sequence runs out.
+	tk.MustExec("create sequence seq maxvalue 3")
+	err = tk.QueryToErr("select nextval_n(seq, 4)")
+	c.Assert(err.Error(), Equals, "[table:4135]Sequence 'test.seq' has run out")
+	tk.MustQuery("select nextval(seq)").Check(testkit.Rows("1"))
+	err = tk.QueryToErr("select nextval_n(seq, 3)")
+	c.Assert(err.Error(), Equals, "[table:4135]Sequence 'test.seq' has run out")
+	tk.MustQuery("select nextval_n(seq, 2)").Check(testkit.Rows("2"))
+	tk.MustExec("drop sequence seq")
+	// The block never cycles.
+	tk.MustExec("create sequence seq maxvalue 5 cycle cache 2")
+	tk.MustQuery("select nextval(seq), nextval(seq), nextval(seq)").Check(testkit.Rows("1 2 3"))
+	err = tk.QueryToErr("select nextval_n(seq, 2)")
+	c.Assert(err.Error(), Equals, "[table:4135]Sequence 'test.seq' has run out")
+	tk.MustQuery("select nextval(seq)").Check(testkit.Rows("4"))
+	tk.MustExec("drop sequence seq")
+
 	// test the sequence name preprocess.
 	tk.MustExec("drop table if exists t")
 	tk.MustExec("create sequence seq")
@@ -766,7 +823,7 @@ func (s *testSequenceSuite) TestInsertSequence(c *C) {
 	tk.MustExec("delete from t")
 	tk.MustQuery("select setval(seq, 100)").Check(testkit.Rows("100"))
 	tk.MustExec("insert into t values(lastval(seq)),(-1),(nextval(seq))")
-	tk.MustQuery("select * from t").Check(testkit.Rows("14", "-1", "101"))
+	tk.MustQuery("select * from t").Check(testkit.Rows("100", "-1", "101"))
 
 	// test insert with generated column.
 	tk.MustExec("drop sequence if exists seq")
@@ -876,6 +933,97 @@ func (s *testSequenceSuite) BenchmarkInsertCacheDefaultExpr(c *C) {
 	}
 }
 
+func (s *testSequenceSuite) TestLastValWithConstantAndNonConstantName(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop sequence if exists seq, seq1")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create sequence seq")
+	tk.MustExec("create sequence seq1 start 10")
+	tk.MustExec("create table t (id int)")
+	tk.MustExec("insert into t values(1), (2), (3), (4)")
+
+	tk.MustQuery("select nextval(seq), nextval(seq1)").Check(testkit.Rows("1 10"))
+	// The constant sequence name is resolved once in the statement.
+	tk.MustQuery("select id, lastval(seq) from t order by id").Check(testkit.Rows("1 1", "2 1", "3 1", "4 1"))
+	tk.MustQuery("select id, lastval(seq), nextval(seq) from t order by id").Check(testkit.Rows("1 1 2", "2 2 3", "3 3 4", "4 4 5"))
+
+	// The non-constant sequence name is resolved for each row.
+	lastVal, err := expression.NewFunction(tk.Se, ast.LastVal, types.NewFieldType(parser_mysql.TypeLonglong),
+		&expression.Column{Index: 0, RetType: types.NewFieldType(parser_mysql.TypeVarchar)})
+	c.Assert(err, IsNil)
+	c.Assert(lastVal.ConstItem(tk.Se.GetSessionVars().StmtCtx), IsFalse)
+	names := []interface{}{"seq", "seq1", "test.seq", nil}
+	expects := []interface{}{int64(5), int64(10), int64(5), nil}
+	for i, name := range names {
+		d, err := lastVal.Eval(chunk.MutRowFromValues(name).ToRow())
+		c.Assert(err, IsNil)
+		c.Assert(d.GetValue(), Equals, expects[i])
+	}
+	_, err = lastVal.Eval(chunk.MutRowFromValues("seq2").ToRow())
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "[schema:1146]Table 'test.seq2' doesn't exist")
+
+	// The cached sequence should not be used by the following statements, whose privileges may be changed.
+	tk.MustExec("drop user if exists myuser@localhost")
+	tk.MustExec("create user myuser@localhost")
+	tk.MustExec("grant select on test.seq to 'myuser'@'localhost'")
+	tk.MustExec("grant select on test.t to 'myuser'@'localhost'")
+	tk1 := testkit.NewTestKit(c, s.store)
+	se, err := session.CreateSession4Test(s.store)
+	c.Assert(err, IsNil)
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "myuser", Hostname: "localhost"}, nil, nil), IsTrue)
+	tk1.Se = se
+	tk1.MustExec("use test")
+	tk1.MustExec("prepare stmt from 'select lastval(seq) from t'")
+	tk1.MustQuery("execute stmt").Check(testkit.Rows("<nil>", "<nil>", "<nil>", "<nil>"))
+	tk.MustExec("revoke select on test.seq from 'myuser'@'localhost'")
+	err = tk1.QueryToErr("execute stmt")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "[expression:1142]SELECT command denied to user 'myuser'@'localhost' for table 'seq'")
+	tk.MustExec("drop user myuser@localhost")
+
+	tk.MustExec("drop table t")
+	tk.MustExec("drop sequence seq, seq1")
+}
+
+func (s *testSequenceSuite) TestSequenceFunctionWithQuotedName(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop sequence if exists `my.seq`, seq")
+	tk.MustExec("create sequence `my.seq`")
+	tk.MustExec("create sequence seq start 100")
+	tk.MustQuery("select nextval(`my.seq`), nextval(test.`my.seq`)").Check(testkit.Rows("1 2"))
+	tk.MustQuery("select lastval(`my.seq`), lastval(test.`my.seq`), lastval(seq)").Check(testkit.Rows("2 2 <nil>"))
+	tk.MustQuery("select setval(`test`.`my.seq`, 10), nextval_n(`my.seq`, 2)").Check(testkit.Rows("10 11"))
+	tk.MustQuery("select lastval(`my.seq`)").Check(testkit.Rows("12"))
+	tk.MustExec("drop sequence `my.seq`, seq")
+}
+
+// Notice: use go test -check.b BenchmarkLastValWithConstantName to test it.
+func (s *testSequenceSuite) BenchmarkLastValWithConstantName(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop sequence if exists seq")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create sequence seq")
+	tk.MustExec("create table t(a int)")
+	sql := "insert into t values "
+	for i := 0; i < 1000; i++ {
+		if i == 0 {
+			sql += "(1)"
+		} else {
+			sql += ",(1)"
+		}
+	}
+	tk.MustExec(sql)
+	tk.MustExec("select nextval(seq)")
+	c.ResetTimer()
+	for i := 0; i < c.N; i++ {
+		tk.MustQuery("select lastval(seq) from t")
+	}
+}
+
 func (s *testSequenceSuite) TestSequenceFunctionPrivilege(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -907,6 +1055,11 @@ func (s *testSequenceSuite) TestSequenceFunctionPrivilege(c *C) {
 	c.Assert(err, NotNil)
 	c.Assert(err.Error(), Equals, "[expression:1142]INSERT command denied to user 'myuser'@'localhost' for table 'seq'")
 
+	// INSERT privilege required to use nextval_n.
+	err = tk1.QueryToErr("select nextval_n(seq, 2)")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "[expression:1142]INSERT command denied to user 'myuser'@'localhost' for table 'seq'")
+
 	// SELECT privilege required to use lastval.
 	err = tk1.QueryToErr("select lastval(seq)")
 	c.Assert(err, NotNil)
@@ -924,6 +1077,7 @@ func (s *testSequenceSuite) TestSequenceFunctionPrivilege(c *C) {
 	tk1.MustQuery("select nextval(seq)").Check(testkit.Rows("1"))
 	tk1.MustQuery("select lastval(seq)").Check(testkit.Rows("1"))
 	tk1.MustQuery("select setval(seq, 10)").Check(testkit.Rows("10"))
+	tk1.MustQuery("select nextval_n(seq, 2)").Check(testkit.Rows("11"))
 	tk1.MustExec("insert into t values()")
 
 	tk.MustExec("drop table t")
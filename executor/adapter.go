@@ -152,7 +152,13 @@ func (a *recordSet) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 	numRows := req.NumRows()
 	if numRows == 0 {
 		if a.stmt != nil {
-			a.stmt.Ctx.GetSessionVars().LastFoundRows = a.stmt.Ctx.GetSessionVars().StmtCtx.FoundRows()
+			sessVars := a.stmt.Ctx.GetSessionVars()
+			if foundRows, ok := sessVars.StmtCtx.FoundRowsWithoutLimit(); ok {
+				// The statement carries SQL_CALC_FOUND_ROWS, use the rows found without the limit.
+				sessVars.LastFoundRows = foundRows
+			} else {
+				sessVars.LastFoundRows = sessVars.StmtCtx.FoundRows()
+			}
 		}
 		return nil
 	}
@@ -383,6 +389,9 @@ func (a *ExecStmt) Exec(ctx context.Context) (_ sqlexec.RecordSet, err error) {
 	}
 	// ExecuteExec will rewrite `a.Plan`, so set plan label should be executed after `a.buildExecutor`.
 	ctx = a.setPlanLabelForTopSQL(ctx)
+	// The plan digest is cached in the statement context before the executor is opened, so TIDB_PLAN_DIGEST() can
+	// read it from there, even if it's evaluated by several workers at the same time.
+	getPlanDigest(a.Ctx, a.Plan)
 
 	if err = e.Open(ctx); err != nil {
 		terror.Call(e.Close)
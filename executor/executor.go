@@ -1077,13 +1077,20 @@ type LimitExec struct {
 
 	// columnIdxsUsedByChild keep column indexes of child executor used for inline projection
 	columnIdxsUsedByChild []int
+
+	// calcFoundRows indicates that all the rows of the child are counted for SQL_CALC_FOUND_ROWS.
+	calcFoundRows bool
+	// foundRows is the number of rows read from the child so far.
+	foundRows uint64
+	// childDrained represents whether all the rows of the child have been counted.
+	childDrained bool
 }
 
 // Next implements the Executor Next interface.
 func (e *LimitExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	req.Reset()
 	if e.cursor >= e.end {
-		return nil
+		return e.drainChild(ctx)
 	}
 	for !e.meetFirstBatch {
 		// transfer req's requiredRows to childResult and then adjust it in childResult
@@ -1095,8 +1102,10 @@ func (e *LimitExec) Next(ctx context.Context, req *chunk.Chunk) error {
 		batchSize := uint64(e.childResult.NumRows())
 		// no more data.
 		if batchSize == 0 {
+			e.setFoundRows()
 			return nil
 		}
+		e.foundRows += batchSize
 		if newCursor := e.cursor + batchSize; newCursor >= e.begin {
 			e.meetFirstBatch = true
 			begin, end := e.begin-e.cursor, batchSize
@@ -1126,8 +1135,10 @@ func (e *LimitExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	batchSize := uint64(e.childResult.NumRows())
 	// no more data.
 	if batchSize == 0 {
+		e.setFoundRows()
 		return nil
 	}
+	e.foundRows += batchSize
 	if e.cursor+batchSize > e.end {
 		e.childResult.TruncateTo(int(e.end - e.cursor))
 		batchSize = e.end - e.cursor
@@ -1154,9 +1165,40 @@ func (e *LimitExec) Open(ctx context.Context) error {
 	e.childResult = newFirstChunk(e.children[0])
 	e.cursor = 0
 	e.meetFirstBatch = e.begin == 0
+	e.foundRows = 0
+	e.childDrained = false
+	return nil
+}
+
+// drainChild reads and counts the remaining rows of the child after the limit is reached,
+// so that FOUND_ROWS() can return the number of rows found without the limit.
+func (e *LimitExec) drainChild(ctx context.Context) error {
+	if !e.calcFoundRows || e.childDrained {
+		return nil
+	}
+	chk := newFirstChunk(e.children[0])
+	for {
+		if err := Next(ctx, e.children[0], chk); err != nil {
+			return err
+		}
+		if chk.NumRows() == 0 {
+			break
+		}
+		e.foundRows += uint64(chk.NumRows())
+	}
+	e.setFoundRows()
 	return nil
 }
 
+// setFoundRows records the number of rows found without the limit into the statement context.
+func (e *LimitExec) setFoundRows() {
+	if !e.calcFoundRows || e.childDrained {
+		return
+	}
+	e.childDrained = true
+	e.ctx.GetSessionVars().StmtCtx.SetFoundRowsWithoutLimit(e.foundRows)
+}
+
 // Close implements the Executor Close interface.
 func (e *LimitExec) Close() error {
 	e.childResult = nil
@@ -1696,6 +1738,8 @@ func ResetContextOfStmt(ctx sessionctx.Context, s ast.StmtNode) (err error) {
 	sc.LockTableIDs = make(map[int64]struct{})
 	sc.EnableOptimizeTrace = false
 	sc.LogicalOptimizeTrace = nil
+	sc.PhysicalOptimizeTrace = nil
+	sc.PlanCacheParamTrace = nil
 	sc.OptimizerCETrace = nil
 
 	sc.InitMemTracker(memory.LabelForSQLText, vars.MemQuotaQuery)
@@ -6931,6 +6931,16 @@ func (s *testClusterTableSuite) TestFunctionDecodeSQLDigests(c *C) {
 	tk.MustQuery("select tidb_decode_sql_digests(?, ?)", digests, len(norm2)).Check(testkit.Rows(
 		"[\"begin\",\"select @@tidb_current_ts\",\"select `id` , `v` from `...\"]"))
 
+	// Multiple rows with repeated digests, the texts retrieved by the former rows are reused.
+	tk.MustExec("create table test_func_decode_sql_digests_rows(id int primary key, digests varchar(1024))")
+	tk.MustExec("insert into test_func_decode_sql_digests_rows values (1, ?), (2, ?), (3, ?), (4, ?)",
+		digests, fmt.Sprintf(`["%s"]`, digest3), fmt.Sprintf(`["%s","%s"]`, digest2, digest1), digests)
+	tk.MustQuery("select id, tidb_decode_sql_digests(digests) from test_func_decode_sql_digests_rows order by id").Check(testkit.Rows(
+		"1 "+decoded,
+		fmt.Sprintf(`2 ["%s"]`, norm3),
+		fmt.Sprintf(`3 ["%s","%s"]`, norm2, norm1),
+		"4 "+decoded))
+
 	// Empty array.
 	tk.MustQuery("select tidb_decode_sql_digests('[]')").Check(testkit.Rows("[]"))
 
@@ -6947,8 +6957,21 @@ func (s *testClusterTableSuite) TestFunctionDecodeSQLDigests(c *C) {
 	tk.MustQuery(`select tidb_decode_sql_digests('aabbccdd')`).Check(testkit.Rows("<nil>"))
 	tk.MustQuery(`show warnings`).Check(testkit.Rows(`Warning 1210 The argument can't be unmarshalled as JSON array: 'aabbccdd'`))
 
+	// Strict mode returns an error if any digest can't be resolved, and JSON null items are kept as null.
+	tk.MustQuery("select tidb_decode_sql_digests(?, 0, 1)", digests).Check(testkit.Rows(decoded))
+	tk.MustQuery("select tidb_decode_sql_digests(?, 0, 1)", fmt.Sprintf(`["%s",null]`, digest1)).
+		Check(testkit.Rows(fmt.Sprintf(`["%s",null]`, norm1)))
+	tk.MustQuery("select tidb_decode_sql_digests(?, 0, 0)", fmt.Sprintf(`["%s","abcde"]`, digest1)).
+		Check(testkit.Rows(fmt.Sprintf(`["%s",null]`, norm1)))
+	tk.MustQuery("select tidb_decode_sql_digests(?, 0, null)", fmt.Sprintf(`["%s","abcde"]`, digest1)).
+		Check(testkit.Rows(fmt.Sprintf(`["%s",null]`, norm1)))
+	err = tk.QueryToErr("select tidb_decode_sql_digests(?, 0, 1)", fmt.Sprintf(`["%s","abcde"]`, digest1))
+	c.Assert(err, ErrorMatches, `.*The SQL digest "abcde" can't be resolved`)
+	err = tk.QueryToErr(`select tidb_decode_sql_digests('["abc",1]', 0, 1)`)
+	c.Assert(err, ErrorMatches, `.*The SQL digest "abc" can't be resolved`)
+
 	// Invalid argument count.
-	tk.MustGetErrCode("select tidb_decode_sql_digests('a', 1, 2)", 1582)
+	tk.MustGetErrCode("select tidb_decode_sql_digests('a', 1, 1, 2)", 1582)
 	tk.MustGetErrCode("select tidb_decode_sql_digests()", 1582)
 }
 
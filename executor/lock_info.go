@@ -0,0 +1,110 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/store/helper"
+)
+
+// GetLockInfoByKey reads the lock on the key from TiKV. It's installed into the session as the lock
+// provider of TIDB_DECODE_LOCK_KEY(), a nil LockInfo is returned if the key is not locked.
+func GetLockInfoByKey(ctx context.Context, sctx sessionctx.Context, key []byte) (*expression.LockInfo, error) {
+	tikvStore, ok := sctx.GetStore().(helper.Storage)
+	if !ok {
+		return nil, errors.New("the store doesn't support reading locks")
+	}
+	resp, err := helper.NewHelper(tikvStore).GetMvccByEncodedKeyWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Error) > 0 {
+		return nil, errors.New(resp.Error)
+	}
+	lock := resp.GetInfo().GetLock()
+	if lock == nil {
+		return nil, nil
+	}
+	return &expression.LockInfo{
+		LockType:   lock.Type.String(),
+		Primary:    lock.Primary,
+		TxnStartTS: lock.StartTs,
+		TTL:        lock.Ttl,
+	}, nil
+}
+
+// GetMVCCInfoByKey reads the MVCC information of the key from TiKV. It's installed into the session as the
+// MVCC information provider of TIDB_MVCC_INFO().
+func GetMVCCInfoByKey(ctx context.Context, sctx sessionctx.Context, key []byte) (*expression.MVCCInfo, error) {
+	tikvStore, ok := sctx.GetStore().(helper.Storage)
+	if !ok {
+		return nil, errors.New("the store doesn't support reading MVCC information")
+	}
+	resp, err := helper.NewHelper(tikvStore).GetMvccByEncodedKeyWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Error) > 0 {
+		return nil, errors.New(resp.Error)
+	}
+	mvccInfo := resp.GetInfo()
+	info := &expression.MVCCInfo{
+		Writes: make([]expression.MVCCWrite, 0, len(mvccInfo.GetWrites())),
+		Values: make([]expression.MVCCValue, 0, len(mvccInfo.GetValues())),
+	}
+	if lock := mvccInfo.GetLock(); lock != nil {
+		info.Lock = &expression.LockInfo{
+			LockType:   lock.Type.String(),
+			Primary:    lock.Primary,
+			TxnStartTS: lock.StartTs,
+			TTL:        lock.Ttl,
+		}
+	}
+	for _, w := range mvccInfo.GetWrites() {
+		info.Writes = append(info.Writes, expression.MVCCWrite{
+			Type:       w.Type.String(),
+			StartTS:    w.StartTs,
+			CommitTS:   w.CommitTs,
+			ShortValue: w.ShortValue,
+		})
+	}
+	for _, v := range mvccInfo.GetValues() {
+		info.Values = append(info.Values, expression.MVCCValue{
+			StartTS: v.StartTs,
+			Value:   v.Value,
+		})
+	}
+	return info, nil
+}
+
+// KeyspaceStorage is implemented by the storage which belongs to a keyspace of a multi-tenant cluster.
+type KeyspaceStorage interface {
+	// GetKeyspaceID returns the id of the keyspace of the storage.
+	GetKeyspaceID() uint32
+}
+
+// GetKeyspaceID returns the keyspace id of the storage of the session. It's installed into the session as the keyspace
+// provider of TIDB_KEYSPACE_ID(), the bool indicates whether the storage belongs to a keyspace.
+func GetKeyspaceID(sctx sessionctx.Context) (uint32, bool) {
+	keyspaceStore, ok := sctx.GetStore().(KeyspaceStorage)
+	if !ok {
+		return 0, false
+	}
+	return keyspaceStore.GetKeyspaceID(), true
+}
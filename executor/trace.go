@@ -171,6 +171,36 @@ func (e *TraceExec) nextOptimizerPlanTrace(ctx context.Context, se sessionctx.Co
 	if err != nil {
 		return errors.AddStack(err)
 	}
+
+	physicalZW, err := zw.Create("physical_trace.json")
+	if err != nil {
+		return errors.AddStack(err)
+	}
+	writer.Reset()
+	err = jsonEncoder.Encode(se.GetSessionVars().StmtCtx.PhysicalOptimizeTrace)
+	if err != nil {
+		return errors.AddStack(err)
+	}
+	_, err = physicalZW.Write([]byte(writer.String()))
+	if err != nil {
+		return errors.AddStack(err)
+	}
+
+	if paramTrace := se.GetSessionVars().StmtCtx.PlanCacheParamTrace; paramTrace != nil {
+		paramZW, err := zw.Create("plan_cache_params.json")
+		if err != nil {
+			return errors.AddStack(err)
+		}
+		writer.Reset()
+		err = jsonEncoder.Encode(paramTrace)
+		if err != nil {
+			return errors.AddStack(err)
+		}
+		_, err = paramZW.Write([]byte(writer.String()))
+		if err != nil {
+			return errors.AddStack(err)
+		}
+	}
 	req.AppendString(0, fileName)
 	e.exhausted = true
 	return nil
@@ -15,8 +15,14 @@
 package executor_test
 
 import (
+	"archive/zip"
+	"encoding/json"
+	"path/filepath"
+
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/util/testkit"
+	"github.com/pingcap/tidb/util/tracing"
 )
 
 func (s *testSuite1) TestTraceExec(c *C) {
@@ -76,3 +82,36 @@ func (s *testSuite1) TestTracePlanStmt(c *C) {
 	c.Assert(rows[0], HasLen, 1)
 	c.Assert(rows[0][0].(string), Matches, ".*zip")
 }
+
+func (s *testSuite1) TestTracePlanExecuteStmtParams(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("create table tp124(a int, b varchar(10), c double);")
+	tk.MustExec("prepare stmt from 'select * from tp124 where a > ? and b = ? and c < ?'")
+	tk.MustExec("set @a = 1, @b = 'x', @c = 1.5")
+	rows := tk.MustQuery("trace plan execute stmt using @a, @b, @c").Rows()
+	c.Assert(rows, HasLen, 1)
+	fileName := rows[0][0].(string)
+	zr, err := zip.OpenReader(filepath.Join(domain.GetOptimizerTraceDirName(), fileName))
+	c.Assert(err, IsNil)
+	defer func() {
+		c.Assert(zr.Close(), IsNil)
+	}()
+	var paramTrace *tracing.PlanCacheParamTracer
+	for _, f := range zr.File {
+		if f.Name != "plan_cache_params.json" {
+			continue
+		}
+		r, err := f.Open()
+		c.Assert(err, IsNil)
+		paramTrace = &tracing.PlanCacheParamTracer{}
+		c.Assert(json.NewDecoder(r).Decode(paramTrace), IsNil)
+		c.Assert(r.Close(), IsNil)
+	}
+	c.Assert(paramTrace, NotNil)
+	c.Assert(paramTrace.Params, DeepEquals, []tracing.PlanCacheParamTrace{
+		{Order: 0, Offset: 30, Type: "bigint", Value: "1"},
+		{Order: 1, Offset: 40, Type: "var_string", Value: "x"},
+		{Order: 2, Offset: 50, Type: "decimal", Value: "1.5"},
+	})
+}
@@ -674,8 +674,12 @@ var funcs = map[string]functionClass{
 	ast.YearWeek:         &yearWeekFunctionClass{baseFunctionClass{ast.YearWeek, 1, 2}},
 	ast.LastDay:          &lastDayFunctionClass{baseFunctionClass{ast.LastDay, 1, 1}},
 	// TSO functions
-	ast.TiDBBoundedStaleness: &tidbBoundedStalenessFunctionClass{baseFunctionClass{ast.TiDBBoundedStaleness, 2, 2}},
-	ast.TiDBParseTso:         &tidbParseTsoFunctionClass{baseFunctionClass{ast.TiDBParseTso, 1, 1}},
+	ast.TiDBBoundedStaleness:      &tidbBoundedStalenessFunctionClass{baseFunctionClass{ast.TiDBBoundedStaleness, 2, 2}},
+	ast.TiDBParseTso:              &tidbParseTsoFunctionClass{baseFunctionClass{ast.TiDBParseTso, 1, 1}},
+	ast.TiDBParseTsoLogical:       &tidbParseTsoLogicalFunctionClass{baseFunctionClass{ast.TiDBParseTsoLogical, 1, 1}},
+	ast.TiDBDecodeTimeFromRowID:   &tidbDecodeTimeFromRowIDFunctionClass{baseFunctionClass{ast.TiDBDecodeTimeFromRowID, 2, 2}},
+	ast.TiDBWaitTxnTS:             &tidbWaitTxnTSFunctionClass{baseFunctionClass{ast.TiDBWaitTxnTS, 2, 2}},
+	ast.TiDBDecodeTimestampColumn: &tidbDecodeTimestampColumnFunctionClass{baseFunctionClass{ast.TiDBDecodeTimestampColumn, 1, 1}},
 
 	// string functions
 	ast.ASCII:           &asciiFunctionClass{baseFunctionClass{ast.ASCII, 1, 1}},
@@ -731,10 +735,11 @@ var funcs = map[string]functionClass{
 	ast.WeightString:    &weightStringFunctionClass{baseFunctionClass{ast.WeightString, 1, 3}},
 
 	// information functions
-	ast.ConnectionID: &connectionIDFunctionClass{baseFunctionClass{ast.ConnectionID, 0, 0}},
-	ast.CurrentUser:  &currentUserFunctionClass{baseFunctionClass{ast.CurrentUser, 0, 0}},
-	ast.CurrentRole:  &currentRoleFunctionClass{baseFunctionClass{ast.CurrentRole, 0, 0}},
-	ast.Database:     &databaseFunctionClass{baseFunctionClass{ast.Database, 0, 0}},
+	ast.ConnectionID:    &connectionIDFunctionClass{baseFunctionClass{ast.ConnectionID, 0, 0}},
+	ast.CurrentUser:     &currentUserFunctionClass{baseFunctionClass{ast.CurrentUser, 0, 0}},
+	ast.CurrentRole:     &currentRoleFunctionClass{baseFunctionClass{ast.CurrentRole, 0, 0}},
+	ast.CurrentRoleJSON: &currentRoleJSONFunctionClass{baseFunctionClass{ast.CurrentRoleJSON, 0, 0}},
+	ast.Database:        &databaseFunctionClass{baseFunctionClass{ast.Database, 0, 0}},
 	// This function is a synonym for DATABASE().
 	// See http://dev.mysql.com/doc/refman/5.7/en/information-functions.html#function_schema
 	ast.Schema:       &databaseFunctionClass{baseFunctionClass{ast.Schema, 0, 0}},
@@ -749,10 +754,14 @@ var funcs = map[string]functionClass{
 	ast.RowCount:     &rowCountFunctionClass{baseFunctionClass{ast.RowCount, 0, 0}},
 	ast.SessionUser:  &userFunctionClass{baseFunctionClass{ast.SessionUser, 0, 0}},
 	ast.SystemUser:   &userFunctionClass{baseFunctionClass{ast.SystemUser, 0, 0}},
+	// VERSION_COMPARE compares version strings like the ones returned by VERSION().
+	ast.VersionCompare: &versionCompareFunctionClass{baseFunctionClass{ast.VersionCompare, 2, 2}},
 
 	// See https://dev.mysql.com/doc/refman/8.0/en/performance-schema-functions.html
-	ast.FormatBytes:    &formatBytesFunctionClass{baseFunctionClass{ast.FormatBytes, 1, 1}},
+	ast.FormatBytes:    &formatBytesFunctionClass{baseFunctionClass{ast.FormatBytes, 1, 2}},
 	ast.FormatNanoTime: &formatNanoTimeFunctionClass{baseFunctionClass{ast.FormatNanoTime, 1, 1}},
+	ast.ParseBytes:     &parseBytesFunctionClass{baseFunctionClass{ast.ParseBytes, 1, 1}},
+	ast.ParseNanoTime:  &parseNanoTimeFunctionClass{baseFunctionClass{ast.ParseNanoTime, 1, 1}},
 
 	// control functions
 	ast.If:     &ifFunctionClass{baseFunctionClass{ast.If, 3, 3}},
@@ -869,18 +878,52 @@ var funcs = map[string]functionClass{
 	ast.JSONKeys:          &jsonKeysFunctionClass{baseFunctionClass{ast.JSONKeys, 1, 2}},
 	ast.JSONLength:        &jsonLengthFunctionClass{baseFunctionClass{ast.JSONLength, 1, 2}},
 
+	ast.TiDBJSONValidSchema: &tidbJSONValidSchemaFunctionClass{baseFunctionClass{ast.TiDBJSONValidSchema, 2, 2}},
+
 	// TiDB internal function.
-	ast.TiDBDecodeKey: &tidbDecodeKeyFunctionClass{baseFunctionClass{ast.TiDBDecodeKey, 1, 1}},
+	ast.TiDBDecodeKey:     &tidbDecodeKeyFunctionClass{baseFunctionClass{ast.TiDBDecodeKey, 1, 1}},
+	ast.TiDBDecodeKeyJSON: &tidbDecodeKeyJSONFunctionClass{baseFunctionClass{ast.TiDBDecodeKeyJSON, 1, 1}},
 	// This function is used to show tidb-server version info.
-	ast.TiDBVersion:          &tidbVersionFunctionClass{baseFunctionClass{ast.TiDBVersion, 0, 0}},
-	ast.TiDBIsDDLOwner:       &tidbIsDDLOwnerFunctionClass{baseFunctionClass{ast.TiDBIsDDLOwner, 0, 0}},
-	ast.TiDBDecodePlan:       &tidbDecodePlanFunctionClass{baseFunctionClass{ast.TiDBDecodePlan, 1, 1}},
-	ast.TiDBDecodeSQLDigests: &tidbDecodeSQLDigestsFunctionClass{baseFunctionClass{ast.TiDBDecodeSQLDigests, 1, 2}},
+	ast.TiDBVersion:                  &tidbVersionFunctionClass{baseFunctionClass{ast.TiDBVersion, 0, 0}},
+	ast.TiDBVersionJSON:              &tidbVersionJSONFunctionClass{baseFunctionClass{ast.TiDBVersionJSON, 0, 0}},
+	ast.TiDBVersionComment:           &tidbVersionCommentFunctionClass{baseFunctionClass{ast.TiDBVersionComment, 0, 0}},
+	ast.TiDBIsDDLOwner:               &tidbIsDDLOwnerFunctionClass{baseFunctionClass{ast.TiDBIsDDLOwner, 0, 0}},
+	ast.TiDBDDLOwner:                 &tidbDDLOwnerFunctionClass{baseFunctionClass{ast.TiDBDDLOwner, 0, 0}},
+	ast.TiDBDecodePlan:               &tidbDecodePlanFunctionClass{baseFunctionClass{ast.TiDBDecodePlan, 1, 1}},
+	ast.TiDBDecodePlanStrict:         &tidbDecodePlanStrictFunctionClass{baseFunctionClass{ast.TiDBDecodePlanStrict, 1, 1}},
+	ast.TiDBDecodeSQLDigests:         &tidbDecodeSQLDigestsFunctionClass{baseFunctionClass{ast.TiDBDecodeSQLDigests, 1, 3}},
+	ast.TiDBEncodeSQLDigest:          &tidbEncodeSQLDigestFunctionClass{baseFunctionClass{ast.TiDBEncodeSQLDigest, 1, 1}},
+	ast.TiDBParseAndExplain:          &tidbParseAndExplainFunctionClass{baseFunctionClass{ast.TiDBParseAndExplain, 1, 1}},
+	ast.TiDBEncodeTimeRangeKeys:      &tidbEncodeTimeRangeKeysFunctionClass{baseFunctionClass{ast.TiDBEncodeTimeRangeKeys, 5, 5}},
+	ast.TiDBDecodeLockKey:            &tidbDecodeLockKeyFunctionClass{baseFunctionClass{ast.TiDBDecodeLockKey, 1, 1}},
+	ast.TiDBMVCCInfo:                 &tidbMVCCInfoFunctionClass{baseFunctionClass{ast.TiDBMVCCInfo, 1, 1}},
+	ast.TiDBKeyspaceID:               &tidbKeyspaceIDFunctionClass{baseFunctionClass{ast.TiDBKeyspaceID, 0, 0}},
+	ast.TiDBDecodeRow:                &tidbDecodeRowFunctionClass{baseFunctionClass{ast.TiDBDecodeRow, 2, 2}},
+	ast.TiDBCurrentStmtType:          &tidbCurrentStmtTypeFunctionClass{baseFunctionClass{ast.TiDBCurrentStmtType, 0, 0}},
+	ast.TiDBDecodeIndexValue:         &tidbDecodeIndexValueFunctionClass{baseFunctionClass{ast.TiDBDecodeIndexValue, 3, 3}},
+	ast.TiDBTraceID:                  &tidbTraceIDFunctionClass{baseFunctionClass{ast.TiDBTraceID, 0, 0}},
+	ast.TiDBDecodeKeyRange:           &tidbDecodeKeyRangeFunctionClass{baseFunctionClass{ast.TiDBDecodeKeyRange, 2, 2}},
+	ast.TiDBEstimateIndexSelectivity: &tidbEstimateIndexSelectivityFunctionClass{baseFunctionClass{ast.TiDBEstimateIndexSelectivity, 3, 3}},
+	ast.TiDBCurrentBatchSize:         &tidbCurrentBatchSizeFunctionClass{baseFunctionClass{ast.TiDBCurrentBatchSize, 0, 0}},
+	ast.TiDBDecodeBase64Row:          &tidbDecodeBase64RowFunctionClass{baseFunctionClass{ast.TiDBDecodeBase64Row, 2, 2}},
+	ast.TiDBCurrentSQLDigestText:     &tidbCurrentSQLDigestTextFunctionClass{baseFunctionClass{ast.TiDBCurrentSQLDigestText, 0, 0}},
+	ast.TiDBPlanDigest:               &tidbPlanDigestFunctionClass{baseFunctionClass{ast.TiDBPlanDigest, 0, 0}},
+	ast.TiDBDecodeMetaKey:            &tidbDecodeMetaKeyFunctionClass{baseFunctionClass{ast.TiDBDecodeMetaKey, 1, 1}},
+	ast.TiDBCurrentIsolationLevel:    &tidbCurrentIsolationLevelFunctionClass{baseFunctionClass{ast.TiDBCurrentIsolationLevel, 0, 0}},
+	ast.TiDBCurrentTxnID:             &tidbCurrentTxnIDFunctionClass{baseFunctionClass{ast.TiDBCurrentTxnID, 0, 0}},
+	ast.TiDBEstimateCost:             &tidbEstimateCostFunctionClass{baseFunctionClass{ast.TiDBEstimateCost, 1, 1}},
+	ast.TiDBDecodeAutoRandom:         &tidbDecodeAutoRandomFunctionClass{baseFunctionClass{ast.TiDBDecodeAutoRandom, 2, 2}},
+	ast.TiDBSessionAlive:             &tidbSessionAliveFunctionClass{baseFunctionClass{ast.TiDBSessionAlive, 1, 1}},
+	ast.TiDBBenchmark:                &tidbBenchmarkFunctionClass{baseFunctionClass{ast.TiDBBenchmark, 2, 2}},
 
 	// TiDB Sequence function.
-	ast.NextVal: &nextValFunctionClass{baseFunctionClass{ast.NextVal, 1, 1}},
-	ast.LastVal: &lastValFunctionClass{baseFunctionClass{ast.LastVal, 1, 1}},
-	ast.SetVal:  &setValFunctionClass{baseFunctionClass{ast.SetVal, 2, 2}},
+	ast.NextVal:  &nextValFunctionClass{baseFunctionClass{ast.NextVal, 1, 1}},
+	ast.NextValN: &nextValNFunctionClass{baseFunctionClass{ast.NextValN, 2, 2}},
+	ast.LastVal:  &lastValFunctionClass{baseFunctionClass{ast.LastVal, 1, 1}},
+	ast.SetVal:   &setValFunctionClass{baseFunctionClass{ast.SetVal, 2, 2}},
+
+	// TiDB sequence-like function without a sequence object.
+	ast.TiDBGenerateSeries: &tidbGenerateSeriesFunctionClass{baseFunctionClass{ast.TiDBGenerateSeries, 2, 3}},
 }
 
 // IsFunctionSupported check if given function name is a builtin sql function.
@@ -20,19 +20,38 @@ package expression
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"regexp"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/coreos/go-semver/semver"
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/meta/autoid"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
+	tjson "github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/plancodec"
 	"github.com/pingcap/tidb/util/printer"
 	"github.com/pingcap/tipb/go-tipb"
@@ -43,25 +62,59 @@ var (
 	_ functionClass = &foundRowsFunctionClass{}
 	_ functionClass = &currentUserFunctionClass{}
 	_ functionClass = &currentRoleFunctionClass{}
+	_ functionClass = &currentRoleJSONFunctionClass{}
 	_ functionClass = &userFunctionClass{}
 	_ functionClass = &connectionIDFunctionClass{}
 	_ functionClass = &lastInsertIDFunctionClass{}
 	_ functionClass = &versionFunctionClass{}
+	_ functionClass = &versionCompareFunctionClass{}
 	_ functionClass = &benchmarkFunctionClass{}
+	_ functionClass = &tidbBenchmarkFunctionClass{}
 	_ functionClass = &charsetFunctionClass{}
 	_ functionClass = &coercibilityFunctionClass{}
 	_ functionClass = &collationFunctionClass{}
 	_ functionClass = &rowCountFunctionClass{}
 	_ functionClass = &tidbVersionFunctionClass{}
+	_ functionClass = &tidbVersionJSONFunctionClass{}
+	_ functionClass = &tidbVersionCommentFunctionClass{}
 	_ functionClass = &tidbIsDDLOwnerFunctionClass{}
+	_ functionClass = &tidbDDLOwnerFunctionClass{}
 	_ functionClass = &tidbDecodePlanFunctionClass{}
+	_ functionClass = &tidbDecodePlanStrictFunctionClass{}
 	_ functionClass = &tidbDecodeKeyFunctionClass{}
+	_ functionClass = &tidbDecodeKeyJSONFunctionClass{}
 	_ functionClass = &tidbDecodeSQLDigestsFunctionClass{}
+	_ functionClass = &tidbEncodeSQLDigestFunctionClass{}
+	_ functionClass = &tidbParseAndExplainFunctionClass{}
+	_ functionClass = &tidbDecodeLockKeyFunctionClass{}
+	_ functionClass = &tidbMVCCInfoFunctionClass{}
+	_ functionClass = &tidbKeyspaceIDFunctionClass{}
+	_ functionClass = &tidbDecodeRowFunctionClass{}
+	_ functionClass = &tidbDecodeBase64RowFunctionClass{}
+	_ functionClass = &tidbCurrentStmtTypeFunctionClass{}
+	_ functionClass = &tidbTraceIDFunctionClass{}
+	_ functionClass = &tidbCurrentBatchSizeFunctionClass{}
+	_ functionClass = &tidbCurrentSQLDigestTextFunctionClass{}
+	_ functionClass = &tidbPlanDigestFunctionClass{}
+	_ functionClass = &tidbDecodeMetaKeyFunctionClass{}
+	_ functionClass = &tidbCurrentIsolationLevelFunctionClass{}
+	_ functionClass = &tidbCurrentTxnIDFunctionClass{}
+	_ functionClass = &tidbEstimateCostFunctionClass{}
+	_ functionClass = &tidbDecodeAutoRandomFunctionClass{}
+	_ functionClass = &tidbSessionAliveFunctionClass{}
+	_ functionClass = &tidbDecodeKeyRangeFunctionClass{}
+	_ functionClass = &tidbEstimateIndexSelectivityFunctionClass{}
+	_ functionClass = &tidbDecodeIndexValueFunctionClass{}
+	_ functionClass = &tidbEncodeTimeRangeKeysFunctionClass{}
 	_ functionClass = &nextValFunctionClass{}
+	_ functionClass = &nextValNFunctionClass{}
 	_ functionClass = &lastValFunctionClass{}
 	_ functionClass = &setValFunctionClass{}
+	_ functionClass = &tidbGenerateSeriesFunctionClass{}
 	_ functionClass = &formatBytesFunctionClass{}
 	_ functionClass = &formatNanoTimeFunctionClass{}
+	_ functionClass = &parseBytesFunctionClass{}
+	_ functionClass = &parseNanoTimeFunctionClass{}
 )
 
 var (
@@ -69,19 +122,53 @@ var (
 	_ builtinFunc = &builtinFoundRowsSig{}
 	_ builtinFunc = &builtinCurrentUserSig{}
 	_ builtinFunc = &builtinUserSig{}
+	_ builtinFunc = &builtinCurrentRoleJSONSig{}
 	_ builtinFunc = &builtinConnectionIDSig{}
 	_ builtinFunc = &builtinLastInsertIDSig{}
 	_ builtinFunc = &builtinLastInsertIDWithIDSig{}
 	_ builtinFunc = &builtinVersionSig{}
+	_ builtinFunc = &builtinVersionCompareSig{}
 	_ builtinFunc = &builtinTiDBVersionSig{}
+	_ builtinFunc = &builtinTiDBVersionJSONSig{}
+	_ builtinFunc = &builtinTiDBVersionCommentSig{}
+	_ builtinFunc = &builtinTiDBDDLOwnerSig{}
 	_ builtinFunc = &builtinRowCountSig{}
 	_ builtinFunc = &builtinTiDBDecodeKeySig{}
+	_ builtinFunc = &builtinTiDBDecodeKeyJSONSig{}
 	_ builtinFunc = &builtinTiDBDecodeSQLDigestsSig{}
+	_ builtinFunc = &builtinTiDBEncodeSQLDigestSig{}
+	_ builtinFunc = &builtinTiDBParseAndExplainSig{}
+	_ builtinFunc = &builtinTiDBDecodeLockKeySig{}
+	_ builtinFunc = &builtinTiDBMVCCInfoSig{}
+	_ builtinFunc = &builtinTiDBKeyspaceIDSig{}
+	_ builtinFunc = &builtinTiDBDecodeRowSig{}
+	_ builtinFunc = &builtinTiDBDecodeBase64RowSig{}
+	_ builtinFunc = &builtinTiDBCurrentStmtTypeSig{}
+	_ builtinFunc = &builtinTiDBTraceIDSig{}
+	_ builtinFunc = &builtinTiDBCurrentBatchSizeSig{}
+	_ builtinFunc = &builtinTiDBCurrentSQLDigestTextSig{}
+	_ builtinFunc = &builtinTiDBPlanDigestSig{}
+	_ builtinFunc = &builtinTiDBDecodeMetaKeySig{}
+	_ builtinFunc = &builtinTiDBCurrentIsolationLevelSig{}
+	_ builtinFunc = &builtinTiDBCurrentTxnIDSig{}
+	_ builtinFunc = &builtinTiDBEstimateCostSig{}
+	_ builtinFunc = &builtinTiDBDecodeAutoRandomSig{}
+	_ builtinFunc = &builtinTiDBSessionAliveSig{}
+	_ builtinFunc = &builtinTiDBBenchmarkSig{}
+	_ builtinFunc = &builtinTiDBDecodeKeyRangeSig{}
+	_ builtinFunc = &builtinTiDBEstimateIndexSelectivitySig{}
+	_ builtinFunc = &builtinTiDBDecodeIndexValueSig{}
+	_ builtinFunc = &builtinTiDBEncodeTimeRangeKeysSig{}
 	_ builtinFunc = &builtinNextValSig{}
+	_ builtinFunc = &builtinNextValNSig{}
 	_ builtinFunc = &builtinLastValSig{}
 	_ builtinFunc = &builtinSetValSig{}
+	_ builtinFunc = &builtinTiDBGenerateSeriesSig{}
 	_ builtinFunc = &builtinFormatBytesSig{}
+	_ builtinFunc = &builtinFormatBytesWithPrecisionSig{}
 	_ builtinFunc = &builtinFormatNanoTimeSig{}
+	_ builtinFunc = &builtinParseBytesSig{}
+	_ builtinFunc = &builtinParseNanoTimeSig{}
 )
 
 type databaseFunctionClass struct {
@@ -147,7 +234,7 @@ func (b *builtinFoundRowsSig) Clone() builtinFunc {
 
 // evalInt evals a builtinFoundRowsSig.
 // See https://dev.mysql.com/doc/refman/5.7/en/information-functions.html#function_found-rows
-// TODO: SQL_CALC_FOUND_ROWS and LIMIT not support for now, We will finish in another PR.
+// If the last statement carries SQL_CALC_FOUND_ROWS, it returns the number of rows found without the LIMIT.
 func (b *builtinFoundRowsSig) evalInt(row chunk.Row) (int64, bool, error) {
 	data := b.ctx.GetSessionVars()
 	if data == nil {
@@ -227,21 +314,82 @@ func (b *builtinCurrentRoleSig) evalString(row chunk.Row) (res string, isNull bo
 	if data == nil || data.ActiveRoles == nil {
 		return "", true, errors.Errorf("Missing session variable when eval builtin")
 	}
-	if len(data.ActiveRoles) == 0 {
-		return "NONE", false, nil
+	return joinActiveRoles(data.ActiveRoles), false, nil
+}
+
+// joinActiveRoles sorts the active roles by their string forms and joins them with ",".
+// Duplicated roles are only shown once, and "NONE" is returned if there is no active role.
+func joinActiveRoles(roles []*auth.RoleIdentity) string {
+	if len(roles) == 0 {
+		return "NONE"
+	}
+	return strings.Join(sortActiveRoles(roles), ",")
+}
+
+// sortActiveRoles returns the string forms of the active roles in order, duplicated roles are only kept once.
+func sortActiveRoles(roles []*auth.RoleIdentity) []string {
+	if len(roles) == 0 {
+		return nil
 	}
-	sortedRes := make([]string, 0, 10)
-	for _, r := range data.ActiveRoles {
+	sortedRes := make([]string, 0, len(roles))
+	for _, r := range roles {
 		sortedRes = append(sortedRes, r.String())
 	}
 	sort.Strings(sortedRes)
-	for i, r := range sortedRes {
-		res += r
-		if i != len(data.ActiveRoles)-1 {
-			res += ","
+	deduped := sortedRes[:1]
+	for _, r := range sortedRes[1:] {
+		if r != deduped[len(deduped)-1] {
+			deduped = append(deduped, r)
 		}
 	}
-	return res, false, nil
+	return deduped
+}
+
+type currentRoleJSONFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *currentRoleJSONFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETJson)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinCurrentRoleJSONSig{bf}
+	return sig, nil
+}
+
+type builtinCurrentRoleJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCurrentRoleJSONSig) Clone() builtinFunc {
+	newSig := &builtinCurrentRoleJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinCurrentRoleJSONSig.
+// It returns the same roles as CURRENT_ROLE() in a JSON array, e.g. ["`r_1`@`%`", "`r_2`@`localhost`"],
+// so the roles can be told apart even if their names contain commas. It returns [] if there is no active role.
+func (b *builtinCurrentRoleJSONSig) evalJSON(_ chunk.Row) (tjson.BinaryJSON, bool, error) {
+	data := b.ctx.GetSessionVars()
+	if data == nil || data.ActiveRoles == nil {
+		return tjson.BinaryJSON{}, true, errors.Errorf("Missing session variable when eval builtin")
+	}
+	return activeRolesToJSON(data.ActiveRoles), false, nil
+}
+
+// activeRolesToJSON converts the sorted active roles to a JSON array.
+func activeRolesToJSON(roles []*auth.RoleIdentity) tjson.BinaryJSON {
+	sortedRoles := sortActiveRoles(roles)
+	res := make([]interface{}, 0, len(sortedRoles))
+	for _, r := range sortedRoles {
+		res = append(res, r)
+	}
+	return tjson.CreateBinary(res)
 }
 
 type userFunctionClass struct {
@@ -380,10 +528,21 @@ func (b *builtinLastInsertIDWithIDSig) evalInt(row chunk.Row) (res int64, isNull
 		return res, isNull, err
 	}
 
+	res = b.clampLastInsertID(res)
 	b.ctx.GetSessionVars().SetLastInsertID(uint64(res))
 	return res, false, nil
 }
 
+// clampLastInsertID clamps the argument of LAST_INSERT_ID(expr) into the range of BIGINT UNSIGNED.
+// A negative signed argument is out of the range, so it's clamped to 0 with a warning.
+func (b *builtinLastInsertIDWithIDSig) clampLastInsertID(id int64) int64 {
+	if id >= 0 || mysql.HasUnsignedFlag(b.args[0].GetType().Flag) {
+		return id
+	}
+	b.ctx.GetSessionVars().StmtCtx.AppendWarning(types.ErrOverflow.GenWithStackByArgs("BIGINT UNSIGNED", fmt.Sprintf("last_insert_id(%d)", id)))
+	return 0
+}
+
 type versionFunctionClass struct {
 	baseFunctionClass
 }
@@ -417,6 +576,75 @@ func (b *builtinVersionSig) evalString(row chunk.Row) (string, bool, error) {
 	return mysql.ServerVersion, false, nil
 }
 
+type versionCompareFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *versionCompareFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = 2
+	sig := &builtinVersionCompareSig{bf}
+	return sig, nil
+}
+
+type builtinVersionCompareSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinVersionCompareSig) Clone() builtinFunc {
+	newSig := &builtinVersionCompareSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinVersionCompareSig.
+// It returns -1, 0 or 1 when the first version is older than, equal to or newer than the second one,
+// and NULL when either of them can't be parsed.
+func (b *builtinVersionCompareSig) evalInt(row chunk.Row) (int64, bool, error) {
+	v1, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	v2, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	ver1, ok := parseTiDBVersion(v1)
+	if !ok {
+		return 0, true, nil
+	}
+	ver2, ok := parseTiDBVersion(v2)
+	if !ok {
+		return 0, true, nil
+	}
+	return int64(ver1.Compare(*ver2)), false, nil
+}
+
+// gitDescribeSuffix matches the "-<commits>-g<hash>" part appended by `git describe --tags`.
+var gitDescribeSuffix = regexp.MustCompile(`-[0-9]+-g[0-9a-f]{7,}$`)
+
+// parseTiDBVersion parses the semantic version out of a version string.
+// For a server version like "5.7.25-TiDB-v6.1.0-alpha-211-g09beefbe0-dirty", only the TiDB part "6.1.0-alpha" is used.
+func parseTiDBVersion(s string) (*semver.Version, bool) {
+	if idx := strings.Index(s, "-TiDB-"); idx >= 0 {
+		s = s[idx+len("-TiDB-"):]
+	}
+	s = strings.TrimSuffix(s, "-dirty")
+	s = gitDescribeSuffix.ReplaceAllLiteralString(s, "")
+	s = strings.TrimPrefix(s, "v")
+	ver, err := semver.NewVersion(s)
+	if err != nil {
+		return nil, false
+	}
+	return ver, true
+}
+
 type tidbVersionFunctionClass struct {
 	baseFunctionClass
 }
@@ -450,6 +678,76 @@ func (b *builtinTiDBVersionSig) evalString(_ chunk.Row) (string, bool, error) {
 	return printer.GetTiDBInfo(), false, nil
 }
 
+type tidbVersionJSONFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbVersionJSONFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETJson)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinTiDBVersionJSONSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBVersionJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBVersionJSONSig) Clone() builtinFunc {
+	newSig := &builtinTiDBVersionJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinTiDBVersionJSONSig.
+// It shows the same information as TIDB_VERSION() in a JSON object, e.g. {"git_hash": "...", ...}.
+func (b *builtinTiDBVersionJSONSig) evalJSON(_ chunk.Row) (tjson.BinaryJSON, bool, error) {
+	return tjson.CreateBinary(printer.GetTiDBInfoMap()), false, nil
+}
+
+type tidbVersionCommentFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbVersionCommentFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = len(getVersionComment())
+	sig := &builtinTiDBVersionCommentSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBVersionCommentSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBVersionCommentSig) Clone() builtinFunc {
+	newSig := &builtinTiDBVersionCommentSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinTiDBVersionCommentSig.
+// It shows the same value as the version_comment system variable.
+func (b *builtinTiDBVersionCommentSig) evalString(_ chunk.Row) (string, bool, error) {
+	return getVersionComment(), false, nil
+}
+
+// getVersionComment reads the version comment from the version_comment system variable, so they never diverge.
+func getVersionComment() string {
+	return variable.GetSysVar(variable.VersionComment).Value
+}
+
 type tidbIsDDLOwnerFunctionClass struct {
 	baseFunctionClass
 }
@@ -486,6 +784,59 @@ func (b *builtinTiDBIsDDLOwnerSig) evalInt(_ chunk.Row) (res int64, isNull bool,
 	return res, false, nil
 }
 
+// getDDLOwnerIDTimeout is the timeout to get the DDL owner ID for TIDB_DDL_OWNER().
+const getDDLOwnerIDTimeout = 3 * time.Second
+
+type tidbDDLOwnerFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbDDLOwnerFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = 64
+	sig := &builtinTiDBDDLOwnerSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBDDLOwnerSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBDDLOwnerSig) Clone() builtinFunc {
+	newSig := &builtinTiDBDDLOwnerSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinTiDBDDLOwnerSig.
+// It returns the server ID of the current DDL owner, or NULL if the owner is unknown.
+func (b *builtinTiDBDDLOwnerSig) evalString(_ chunk.Row) (string, bool, error) {
+	ownerID, isNull := getDDLOwnerID(b.ctx)
+	return ownerID, isNull, nil
+}
+
+// getDDLOwnerID gets the ID of the DDL owner, a warning is appended if it fails to get the owner.
+func getDDLOwnerID(ctx sessionctx.Context) (string, bool) {
+	ddlOwnerChecker := ctx.DDLOwnerChecker()
+	if ddlOwnerChecker == nil {
+		return "", true
+	}
+	goCtx, cancel := context.WithTimeout(context.Background(), getDDLOwnerIDTimeout)
+	ownerID, err := ddlOwnerChecker.GetOwnerID(goCtx)
+	cancel()
+	if err != nil {
+		ctx.GetSessionVars().StmtCtx.AppendWarning(errUnknown.GenWithStack("get the DDL owner failed with error: %v", err))
+		return "", true
+	}
+	return ownerID, len(ownerID) == 0
+}
+
 type benchmarkFunctionClass struct {
 	baseFunctionClass
 }
@@ -547,68 +898,170 @@ func (b *builtinBenchmarkSig) evalInt(row chunk.Row) (int64, bool, error) {
 	if loopCount < 0 {
 		return 0, true, nil
 	}
+	checkBenchmarkLoopCount(b.ctx, loopCount)
 
 	// Eval loop count times based on arg type.
 	// BENCHMARK() will pass-through the eval error,
 	// behavior observed on MySQL 5.7.24.
+	if isNull, err = evalBenchmarkLoop(b.ctx, b.args[1], row, loopCount); err != nil {
+		return 0, isNull, err
+	}
+
+	// Return value of BENCHMARK() is always 0.
+	return 0, false, nil
+}
+
+// checkBenchmarkLoopCount appends a warning if the loop count of BENCHMARK() exceeds tidb_benchmark_warn_threshold,
+// so that an accidental huge benchmark can be noticed.
+func checkBenchmarkLoopCount(ctx sessionctx.Context, loopCount int64) {
+	sessVars := ctx.GetSessionVars()
+	if threshold := sessVars.BenchmarkWarnThreshold; threshold > 0 && loopCount > threshold {
+		sessVars.StmtCtx.AppendWarning(errUnknown.GenWithStack("BENCHMARK() loop count %d exceeds %s(%d)", loopCount, variable.TiDBBenchmarkWarnThreshold, threshold))
+	}
+}
+
+// evalBenchmarkLoop evaluates arg loopCount times based on its eval type, the results are discarded.
+// It passes through the eval error like BENCHMARK() of MySQL.
+func evalBenchmarkLoop(ctx sessionctx.Context, arg Expression, row chunk.Row, loopCount int64) (bool, error) {
 	var i int64
-	arg, ctx := b.args[1], b.ctx
+	var isNull bool
+	var err error
 	switch evalType := arg.GetType().EvalType(); evalType {
 	case types.ETInt:
 		for ; i < loopCount; i++ {
+			if err = checkBenchmarkKilled(ctx, i); err != nil {
+				return true, err
+			}
 			_, isNull, err = arg.EvalInt(ctx, row)
 			if err != nil {
-				return 0, isNull, err
+				return isNull, err
 			}
 		}
 	case types.ETReal:
 		for ; i < loopCount; i++ {
+			if err = checkBenchmarkKilled(ctx, i); err != nil {
+				return true, err
+			}
 			_, isNull, err = arg.EvalReal(ctx, row)
 			if err != nil {
-				return 0, isNull, err
+				return isNull, err
 			}
 		}
 	case types.ETDecimal:
 		for ; i < loopCount; i++ {
+			if err = checkBenchmarkKilled(ctx, i); err != nil {
+				return true, err
+			}
 			_, isNull, err = arg.EvalDecimal(ctx, row)
 			if err != nil {
-				return 0, isNull, err
+				return isNull, err
 			}
 		}
 	case types.ETString:
 		for ; i < loopCount; i++ {
+			if err = checkBenchmarkKilled(ctx, i); err != nil {
+				return true, err
+			}
 			_, isNull, err = arg.EvalString(ctx, row)
 			if err != nil {
-				return 0, isNull, err
+				return isNull, err
 			}
 		}
 	case types.ETDatetime, types.ETTimestamp:
 		for ; i < loopCount; i++ {
+			if err = checkBenchmarkKilled(ctx, i); err != nil {
+				return true, err
+			}
 			_, isNull, err = arg.EvalTime(ctx, row)
 			if err != nil {
-				return 0, isNull, err
+				return isNull, err
 			}
 		}
 	case types.ETDuration:
 		for ; i < loopCount; i++ {
+			if err = checkBenchmarkKilled(ctx, i); err != nil {
+				return true, err
+			}
 			_, isNull, err = arg.EvalDuration(ctx, row)
 			if err != nil {
-				return 0, isNull, err
+				return isNull, err
 			}
 		}
 	case types.ETJson:
 		for ; i < loopCount; i++ {
+			if err = checkBenchmarkKilled(ctx, i); err != nil {
+				return true, err
+			}
 			_, isNull, err = arg.EvalJSON(ctx, row)
 			if err != nil {
-				return 0, isNull, err
+				return isNull, err
 			}
 		}
 	default: // Should never go into here.
-		return 0, true, errors.Errorf("EvalType %v not implemented for builtin BENCHMARK()", evalType)
+		return true, errors.Errorf("EvalType %v not implemented for builtin BENCHMARK()", evalType)
 	}
 
-	// Return value of BENCHMARK() is always 0.
-	return 0, false, nil
+	return false, nil
+}
+
+// benchmarkKillCheckInterval is the number of iterations between two checks of whether the query running
+// BENCHMARK() is killed, which keeps the loop tight while a long-running BENCHMARK() can still be interrupted.
+const benchmarkKillCheckInterval = 2048
+
+// checkBenchmarkKilled returns ErrQueryInterrupted if the query is killed, which is checked every
+// benchmarkKillCheckInterval iterations. The flag is also set when the query exceeds max_execution_time.
+func checkBenchmarkKilled(ctx sessionctx.Context, i int64) error {
+	if i%benchmarkKillCheckInterval == 0 && atomic.LoadUint32(&ctx.GetSessionVars().Killed) == 1 {
+		return ErrQueryInterrupted
+	}
+	return nil
+}
+
+type tidbBenchmarkFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbBenchmarkFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+
+	// Syntax: TIDB_BENCHMARK(loop_count, expression)
+	// Define with same eval type of input arg to avoid unnecessary cast function.
+	sameEvalType := args[1].GetType().EvalType()
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, types.ETInt, sameEvalType)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinTiDBBenchmarkSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBBenchmarkSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBBenchmarkSig) Clone() builtinFunc {
+	newSig := &builtinTiDBBenchmarkSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinTiDBBenchmarkSig. It executes the expression repeatedly like BENCHMARK(),
+// but returns the elapsed nanoseconds instead of 0.
+func (b *builtinTiDBBenchmarkSig) evalInt(row chunk.Row) (int64, bool, error) {
+	loopCount, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if loopCount < 0 {
+		return 0, true, nil
+	}
+	start := time.Now()
+	if isNull, err = evalBenchmarkLoop(b.ctx, b.args[1], row, loopCount); err != nil {
+		return 0, isNull, err
+	}
+	return int64(time.Since(start)), false, nil
 }
 
 type charsetFunctionClass struct {
@@ -627,11 +1080,15 @@ func (c *coercibilityFunctionClass) getFunction(ctx sessionctx.Context, args []E
 	if err := c.verifyArgs(args); err != nil {
 		return nil, err
 	}
+	// Derive the coercibility of the argument before it's used, the nested expressions derive theirs
+	// from the arguments by the collation inference.
+	args[0].Coercibility()
 	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, args[0].GetType().EvalType())
 	if err != nil {
 		return nil, err
 	}
 	sig := &builtinCoercibilitySig{bf}
+	// COERCIBILITY() is never pushed down, there is no pb code for it.
 	sig.setPbCode(tipb.ScalarFuncSig_Unspecified)
 	return sig, nil
 }
@@ -668,6 +1125,8 @@ func (c *collationFunctionClass) getFunction(ctx sessionctx.Context, args []Expr
 	}
 	bf.tp.Charset, bf.tp.Collate = ctx.GetSessionVars().GetCharsetInfo()
 	bf.tp.Flen = 64
+	// The collation name is a system constant like the one returned by USER().
+	bf.SetCoercibility(CoercibilitySysconst)
 	sig := &builtinCollationSig{bf}
 	return sig, nil
 }
@@ -770,56 +1229,1656 @@ func (k TiDBDecodeKeyFunctionKeyType) String() string {
 // TiDBDecodeKeyFunctionKey is used to identify the decoder function in context.
 const TiDBDecodeKeyFunctionKey TiDBDecodeKeyFunctionKeyType = 0
 
-type tidbDecodeSQLDigestsFunctionClass struct {
+type tidbDecodeKeyJSONFunctionClass struct {
 	baseFunctionClass
 }
 
-func (c *tidbDecodeSQLDigestsFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+func (c *tidbDecodeKeyJSONFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
 	if err := c.verifyArgs(args); err != nil {
 		return nil, err
 	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETJson, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinTiDBDecodeKeyJSONSig{bf}
+	return sig, nil
+}
 
-	pm := privilege.GetPrivilegeManager(ctx)
-	if pm != nil && !pm.RequestVerification(ctx.GetSessionVars().ActiveRoles, "", "", "", mysql.ProcessPriv) {
-		return nil, errSpecificAccessDenied.GenWithStackByArgs("PROCESS")
+type builtinTiDBDecodeKeyJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBDecodeKeyJSONSig) Clone() builtinFunc {
+	newSig := &builtinTiDBDecodeKeyJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinTiDBDecodeKeyJSONSig.
+// It returns the components of the key decoded by TIDB_DECODE_KEY() as JSON, i.e. the numeric table_id, and
+// the handle of a record key or the index_id and the index_values of an index key. It returns NULL if the
+// key can't be decoded.
+func (b *builtinTiDBDecodeKeyJSONSig) evalJSON(row chunk.Row) (tjson.BinaryJSON, bool, error) {
+	s, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return tjson.BinaryJSON{}, isNull, err
 	}
+	j, ok := b.getDecoder()(b.ctx, s)
+	return j, !ok, nil
+}
 
-	var argTps []types.EvalType
-	if len(args) > 1 {
-		argTps = []types.EvalType{types.ETString, types.ETInt}
-	} else {
-		argTps = []types.EvalType{types.ETString}
+func (b *builtinTiDBDecodeKeyJSONSig) getDecoder() func(ctx sessionctx.Context, s string) (tjson.BinaryJSON, bool) {
+	if fn := b.ctx.Value(TiDBDecodeKeyJSONFunctionKey); fn != nil {
+		return fn.(func(ctx sessionctx.Context, s string) (tjson.BinaryJSON, bool))
 	}
-	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, argTps...)
+	return func(ctx sessionctx.Context, s string) (tjson.BinaryJSON, bool) { return tjson.BinaryJSON{}, false }
+}
+
+// TiDBDecodeKeyJSONFunctionKeyType is used to identify the JSON decoder function in context.
+type TiDBDecodeKeyJSONFunctionKeyType int
+
+// String() implements Stringer.
+func (k TiDBDecodeKeyJSONFunctionKeyType) String() string {
+	return "tidb_decode_key_json"
+}
+
+// TiDBDecodeKeyJSONFunctionKey is used to identify the JSON decoder function in context.
+const TiDBDecodeKeyJSONFunctionKey TiDBDecodeKeyJSONFunctionKeyType = 0
+
+type tidbDecodeKeyRangeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbDecodeKeyRangeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETJson, types.ETString, types.ETString)
 	if err != nil {
 		return nil, err
 	}
-	sig := &builtinTiDBDecodeSQLDigestsSig{bf}
+	sig := &builtinTiDBDecodeKeyRangeSig{bf}
 	return sig, nil
 }
 
-type builtinTiDBDecodeSQLDigestsSig struct {
+type builtinTiDBDecodeKeyRangeSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinTiDBDecodeSQLDigestsSig) Clone() builtinFunc {
-	newSig := &builtinTiDBDecodeSQLDigestsSig{}
+func (b *builtinTiDBDecodeKeyRangeSig) Clone() builtinFunc {
+	newSig := &builtinTiDBDecodeKeyRangeSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinTiDBDecodeSQLDigestsSig) evalString(row chunk.Row) (string, bool, error) {
-	args := b.getArgs()
-	digestsStr, isNull, err := args[0].EvalString(b.ctx, row)
-	if err != nil {
-		return "", true, err
+// evalJSON evals a builtinTiDBDecodeKeyRangeSig.
+// It decodes the start key and the end key of a range with the decoder of TIDB_DECODE_KEY_JSON(), and
+// returns them with the name of the table they belong to. If the keys belong to different tables,
+// both of the table names are returned. An empty key means the range is unbounded on that side.
+// The kind of the range is returned too if it covers a whole table, a range of records or a range of an index.
+func (b *builtinTiDBDecodeKeyRangeSig) evalJSON(row chunk.Row) (tjson.BinaryJSON, bool, error) {
+	startKey, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return tjson.BinaryJSON{}, isNull, err
 	}
-	if isNull {
-		return "", true, nil
+	endKey, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return tjson.BinaryJSON{}, isNull, err
+	}
+	start, startTbl, startOK := b.decodeRangeKey(startKey)
+	end, endTbl, endOK := b.decodeRangeKey(endKey)
+	if !startOK && !endOK {
+		b.ctx.GetSessionVars().StmtCtx.AppendWarning(errIncorrectArgs.GenWithStack("invalid key range: ['%s', '%s')", startKey, endKey))
+		return tjson.BinaryJSON{}, true, nil
+	}
+	result := map[string]interface{}{"start": start, "end": end}
+	switch {
+	case len(startTbl) == 0 && len(endTbl) == 0:
+	case startTbl == endTbl || len(endTbl) == 0:
+		result["table"] = startTbl
+	case len(startTbl) == 0:
+		result["table"] = endTbl
+	default:
+		result["start_table"], result["end_table"] = startTbl, endTbl
+	}
+	if kind := keyRangeKind(start, end); len(kind) > 0 {
+		result["kind"] = kind
+	}
+	return tjson.CreateBinary(result), false, nil
+}
+
+// decodeRangeKey decodes a key of the range and returns the decoded key and the name of the table it
+// belongs to. An empty key is decoded as null. If the key can't be decoded, the key itself is returned.
+func (b *builtinTiDBDecodeKeyRangeSig) decodeRangeKey(s string) (decoded interface{}, tblName string, ok bool) {
+	if len(s) == 0 {
+		return nil, "", false
+	}
+	decode := func(ctx sessionctx.Context, s string) (tjson.BinaryJSON, bool) { return tjson.BinaryJSON{}, false }
+	if fn := b.ctx.Value(TiDBDecodeKeyJSONFunctionKey); fn != nil {
+		decode = fn.(func(ctx sessionctx.Context, s string) (tjson.BinaryJSON, bool))
+	}
+	key, ok := decode(b.ctx, s)
+	if !ok {
+		return s, "", false
+	}
+	tableID, ok := decodedKeyInt(key, "table_id")
+	if !ok {
+		return s, "", false
+	}
+	if is := b.ctx.GetInfoSchema(); is != nil {
+		if name, ok := util.GetTableNameByID(is, tableID); ok {
+			tblName = name
+		}
 	}
+	return key, tblName, true
+}
 
-	stmtTruncateLength := int64(0)
-	if len(args) > 1 {
+// decodedKeyField returns the field of a key decoded by TIDB_DECODE_KEY_JSON().
+func decodedKeyField(key tjson.BinaryJSON, field string) (tjson.BinaryJSON, bool) {
+	if key.TypeCode != tjson.TypeCodeObject {
+		return tjson.BinaryJSON{}, false
+	}
+	pathExpr, err := tjson.ParseJSONPathExpr("$." + field)
+	if err != nil {
+		return tjson.BinaryJSON{}, false
+	}
+	return key.Extract([]tjson.PathExpression{pathExpr})
+}
+
+// decodedKeyInt returns the integer field of a key decoded by TIDB_DECODE_KEY_JSON(), e.g. table_id and index_id.
+func decodedKeyInt(key tjson.BinaryJSON, field string) (int64, bool) {
+	v, ok := decodedKeyField(key, field)
+	if !ok || v.TypeCode != tjson.TypeCodeInt64 {
+		return 0, false
+	}
+	return v.GetInt64(), true
+}
+
+// decodedKeyKind returns whether a key decoded by TIDB_DECODE_KEY_JSON() is a table prefix, a record key or an index key.
+func decodedKeyKind(key tjson.BinaryJSON) string {
+	if _, ok := decodedKeyField(key, "index_id"); ok {
+		return "index"
+	}
+	if _, ok := decodedKeyField(key, "handle"); ok {
+		return "record"
+	}
+	return "table"
+}
+
+// keyRangeKind returns what the range between two decoded keys covers:
+//   - "table" if the range is from the prefix of a table to the prefix of the next table,
+//   - "record" if both keys are the record keys of the same table, or the range is from a record key to the
+//     prefix of the next table,
+//   - "index" if both keys are the keys of the same index.
+//
+// An empty string is returned otherwise, e.g. the range is unbounded or crosses several tables.
+func keyRangeKind(start, end interface{}) string {
+	startKey, ok := start.(tjson.BinaryJSON)
+	if !ok {
+		return ""
+	}
+	endKey, ok := end.(tjson.BinaryJSON)
+	if !ok {
+		return ""
+	}
+	startTableID, ok := decodedKeyInt(startKey, "table_id")
+	if !ok {
+		return ""
+	}
+	endTableID, ok := decodedKeyInt(endKey, "table_id")
+	if !ok {
+		return ""
+	}
+	startKind, endKind := decodedKeyKind(startKey), decodedKeyKind(endKey)
+	if endKind == "table" && endTableID == startTableID+1 {
+		switch startKind {
+		case "table", "record":
+			return startKind
+		}
+		return ""
+	}
+	if startTableID != endTableID || startKind != endKind {
+		return ""
+	}
+	switch startKind {
+	case "record":
+		return startKind
+	case "index":
+		startIndexID, startOK := decodedKeyInt(startKey, "index_id")
+		endIndexID, endOK := decodedKeyInt(endKey, "index_id")
+		if startOK && endOK && startIndexID == endIndexID {
+			return startKind
+		}
+	}
+	return ""
+}
+
+type tidbDecodeMetaKeyFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbDecodeMetaKeyFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETJson, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinTiDBDecodeMetaKeySig{bf}
+	return sig, nil
+}
+
+type builtinTiDBDecodeMetaKeySig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBDecodeMetaKeySig) Clone() builtinFunc {
+	newSig := &builtinTiDBDecodeMetaKeySig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinTiDBDecodeMetaKeySig.
+// It decodes a meta key, which stores the schema information rather than the table data, and describes
+// what the key stores. Same as TIDB_DECODE_KEY(), a memcomparable-encoded key is decoded automatically.
+func (b *builtinTiDBDecodeMetaKeySig) evalJSON(row chunk.Row) (tjson.BinaryJSON, bool, error) {
+	s, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return tjson.BinaryJSON{}, isNull, err
+	}
+	sc := b.ctx.GetSessionVars().StmtCtx
+	key, err := hex.DecodeString(s)
+	if err != nil || len(key) == 0 {
+		sc.AppendWarning(errIncorrectArgs.GenWithStack("invalid key: '%s'", s))
+		return tjson.BinaryJSON{}, true, nil
+	}
+	desc, err := meta.DecodeKey(key)
+	if err != nil {
+		if _, bs, err1 := codec.DecodeBytes(key, nil); err1 == nil {
+			desc, err = meta.DecodeKey(bs)
+		}
+	}
+	if err != nil {
+		sc.AppendWarning(errIncorrectArgs.GenWithStack("invalid meta key: '%s'", s))
+		return tjson.BinaryJSON{}, true, nil
+	}
+	bs, err := json.Marshal(desc)
+	if err != nil {
+		return tjson.BinaryJSON{}, true, err
+	}
+	j, err := tjson.ParseBinaryFromString(string(bs))
+	return j, false, err
+}
+
+type tidbDecodeLockKeyFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbDecodeLockKeyFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+
+	pm := privilege.GetPrivilegeManager(ctx)
+	if pm != nil && !pm.RequestVerification(ctx.GetSessionVars().ActiveRoles, "", "", "", mysql.ProcessPriv) {
+		return nil, errSpecificAccessDenied.GenWithStackByArgs("PROCESS")
+	}
+
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETJson, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinTiDBDecodeLockKeySig{bf}
+	return sig, nil
+}
+
+type builtinTiDBDecodeLockKeySig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBDecodeLockKeySig) Clone() builtinFunc {
+	newSig := &builtinTiDBDecodeLockKeySig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinTiDBDecodeLockKeySig.
+func (b *builtinTiDBDecodeLockKeySig) evalJSON(row chunk.Row) (tjson.BinaryJSON, bool, error) {
+	s, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return tjson.BinaryJSON{}, isNull, err
+	}
+	sc := b.ctx.GetSessionVars().StmtCtx
+	key, err := hex.DecodeString(s)
+	if err != nil || len(key) == 0 {
+		sc.AppendWarning(errIncorrectArgs.GenWithStack("invalid key: '%s'", s))
+		return tjson.BinaryJSON{}, true, nil
+	}
+	fn := b.ctx.Value(TiDBDecodeLockKeyFunctionKey)
+	if fn == nil {
+		sc.AppendWarning(errors.Errorf("%s is not supported in this context", ast.TiDBDecodeLockKey))
+		return tjson.BinaryJSON{}, true, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), getKeyRetrievalTimeout(b.ctx.GetSessionVars()))
+	defer cancel()
+	lock, err := fn.(func(ctx context.Context, sctx sessionctx.Context, key []byte) (*LockInfo, error))(ctx, b.ctx, key)
+	if err != nil {
+		if errors.Cause(err) == context.DeadlineExceeded || errors.Cause(err) == context.Canceled {
+			return tjson.BinaryJSON{}, true, errUnknown.GenWithStack("Retrieving cancelled internally with error: %v", err)
+		}
+		sc.AppendWarning(errUnknown.GenWithStack("Retrieving lock information failed with error: %v", err))
+		return tjson.BinaryJSON{}, true, nil
+	}
+	if lock == nil {
+		sc.AppendWarning(errUnknown.GenWithStack("There is no lock on key '%s'", s))
+		return tjson.BinaryJSON{}, true, nil
+	}
+	return tjson.CreateBinary(lock.toJSONObject()), false, nil
+}
+
+// getKeyRetrievalTimeout returns the timeout to read the information of a key from the storage.
+// Same as TIDB_DECODE_SQL_DIGESTS(), it's bounded by max_execution_time.
+func getKeyRetrievalTimeout(sessVars *variable.SessionVars) time.Duration {
+	timeout := time.Duration(sessVars.MaxExecutionTime) * time.Millisecond
+	if timeout == 0 || timeout > 20*time.Second {
+		timeout = 20 * time.Second
+	}
+	return timeout
+}
+
+// LockInfo is the lock on a key returned by the lock provider of TIDB_DECODE_LOCK_KEY().
+type LockInfo struct {
+	LockType   string
+	Primary    []byte
+	TxnStartTS uint64
+	TTL        uint64
+}
+
+// toJSONObject converts the lock to the object returned by TIDB_DECODE_LOCK_KEY() and TIDB_MVCC_INFO().
+func (l *LockInfo) toJSONObject() map[string]interface{} {
+	return map[string]interface{}{
+		"lock_type":    l.LockType,
+		"primary":      strings.ToUpper(hex.EncodeToString(l.Primary)),
+		"txn_start_ts": l.TxnStartTS,
+		"ttl":          l.TTL,
+	}
+}
+
+// TiDBDecodeLockKeyFunctionKeyType is used to identify the lock provider in context.
+type TiDBDecodeLockKeyFunctionKeyType int
+
+// String() implements Stringer.
+func (k TiDBDecodeLockKeyFunctionKeyType) String() string {
+	return "tidb_decode_lock_key"
+}
+
+// TiDBDecodeLockKeyFunctionKey is used to identify the lock provider in context.
+const TiDBDecodeLockKeyFunctionKey TiDBDecodeLockKeyFunctionKeyType = 0
+
+type tidbMVCCInfoFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbMVCCInfoFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+
+	pm := privilege.GetPrivilegeManager(ctx)
+	if pm != nil && !pm.RequestVerification(ctx.GetSessionVars().ActiveRoles, "", "", "", mysql.ProcessPriv) {
+		return nil, errSpecificAccessDenied.GenWithStackByArgs("PROCESS")
+	}
+
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETJson, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinTiDBMVCCInfoSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBMVCCInfoSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBMVCCInfoSig) Clone() builtinFunc {
+	newSig := &builtinTiDBMVCCInfoSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinTiDBMVCCInfoSig.
+// It returns the lock, the versions in the write column family and the values in the default column family of the key.
+func (b *builtinTiDBMVCCInfoSig) evalJSON(row chunk.Row) (tjson.BinaryJSON, bool, error) {
+	s, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return tjson.BinaryJSON{}, isNull, err
+	}
+	sc := b.ctx.GetSessionVars().StmtCtx
+	key, err := hex.DecodeString(s)
+	if err != nil || len(key) == 0 {
+		sc.AppendWarning(errIncorrectArgs.GenWithStack("invalid key: '%s'", s))
+		return tjson.BinaryJSON{}, true, nil
+	}
+	fn := b.ctx.Value(TiDBMVCCInfoFunctionKey)
+	if fn == nil {
+		sc.AppendWarning(errors.Errorf("%s is not supported in this context", ast.TiDBMVCCInfo))
+		return tjson.BinaryJSON{}, true, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), getKeyRetrievalTimeout(b.ctx.GetSessionVars()))
+	defer cancel()
+	info, err := fn.(func(ctx context.Context, sctx sessionctx.Context, key []byte) (*MVCCInfo, error))(ctx, b.ctx, key)
+	if err != nil {
+		if errors.Cause(err) == context.DeadlineExceeded || errors.Cause(err) == context.Canceled {
+			return tjson.BinaryJSON{}, true, errUnknown.GenWithStack("Retrieving cancelled internally with error: %v", err)
+		}
+		sc.AppendWarning(errUnknown.GenWithStack("Retrieving MVCC information failed with error: %v", err))
+		return tjson.BinaryJSON{}, true, nil
+	}
+	if info == nil {
+		info = &MVCCInfo{}
+	}
+	var lock interface{}
+	if info.Lock != nil {
+		lock = info.Lock.toJSONObject()
+	}
+	writes := make([]interface{}, 0, len(info.Writes))
+	for _, w := range info.Writes {
+		writes = append(writes, map[string]interface{}{
+			"type":        w.Type,
+			"start_ts":    w.StartTS,
+			"commit_ts":   w.CommitTS,
+			"short_value": strings.ToUpper(hex.EncodeToString(w.ShortValue)),
+		})
+	}
+	values := make([]interface{}, 0, len(info.Values))
+	for _, v := range info.Values {
+		values = append(values, map[string]interface{}{
+			"start_ts": v.StartTS,
+			"value":    strings.ToUpper(hex.EncodeToString(v.Value)),
+		})
+	}
+	return tjson.CreateBinary(map[string]interface{}{
+		"key":     strings.ToUpper(s),
+		"lock":    lock,
+		"write":   writes,
+		"default": values,
+	}), false, nil
+}
+
+// MVCCInfo is the MVCC information of a key returned by the provider of TIDB_MVCC_INFO().
+type MVCCInfo struct {
+	// Lock is nil if the key is not locked.
+	Lock   *LockInfo
+	Writes []MVCCWrite
+	Values []MVCCValue
+}
+
+// MVCCWrite is a version of a key in the write column family.
+type MVCCWrite struct {
+	Type       string
+	StartTS    uint64
+	CommitTS   uint64
+	ShortValue []byte
+}
+
+// MVCCValue is a value of a key in the default column family.
+type MVCCValue struct {
+	StartTS uint64
+	Value   []byte
+}
+
+// TiDBMVCCInfoFunctionKeyType is used to identify the MVCC information provider in context.
+type TiDBMVCCInfoFunctionKeyType int
+
+// String() implements Stringer.
+func (k TiDBMVCCInfoFunctionKeyType) String() string {
+	return "tidb_mvcc_info"
+}
+
+// TiDBMVCCInfoFunctionKey is used to identify the MVCC information provider in context.
+const TiDBMVCCInfoFunctionKey TiDBMVCCInfoFunctionKeyType = 0
+
+type tidbKeyspaceIDFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbKeyspaceIDFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flag |= mysql.UnsignedFlag
+	sig := &builtinTiDBKeyspaceIDSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBKeyspaceIDSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBKeyspaceIDSig) Clone() builtinFunc {
+	newSig := &builtinTiDBKeyspaceIDSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinTiDBKeyspaceIDSig.
+// It returns NULL if the keyspace is disabled, i.e. no keyspace provider is installed or
+// the provider reports that the session doesn't belong to a keyspace.
+func (b *builtinTiDBKeyspaceIDSig) evalInt(_ chunk.Row) (int64, bool, error) {
+	fn := b.ctx.Value(TiDBKeyspaceIDFunctionKey)
+	if fn == nil {
+		return 0, true, nil
+	}
+	id, enabled := fn.(func(sctx sessionctx.Context) (uint32, bool))(b.ctx)
+	if !enabled {
+		return 0, true, nil
+	}
+	return int64(id), false, nil
+}
+
+// TiDBKeyspaceIDFunctionKeyType is used to identify the keyspace provider in context.
+type TiDBKeyspaceIDFunctionKeyType int
+
+// String() implements Stringer.
+func (k TiDBKeyspaceIDFunctionKeyType) String() string {
+	return "tidb_keyspace_id"
+}
+
+// TiDBKeyspaceIDFunctionKey is used to identify the keyspace provider in context.
+const TiDBKeyspaceIDFunctionKey TiDBKeyspaceIDFunctionKeyType = 0
+
+type tidbDecodeRowFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbDecodeRowFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETJson, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinTiDBDecodeRowSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBDecodeRowSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBDecodeRowSig) Clone() builtinFunc {
+	newSig := &builtinTiDBDecodeRowSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinTiDBDecodeRowSig.
+// It decodes a row value in either the old or the new row format with the schema of the given table,
+// and returns an object of column name to value. The handle columns are stored in the row key, so
+// they don't appear in the result.
+func (b *builtinTiDBDecodeRowSig) evalJSON(row chunk.Row) (tjson.BinaryJSON, bool, error) {
+	tableName, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return tjson.BinaryJSON{}, isNull, err
+	}
+	s, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return tjson.BinaryJSON{}, isNull, err
+	}
+
+	tblInfo, err := getDecodeRowTableInfo(b.ctx, tableName)
+	if err != nil {
+		return tjson.BinaryJSON{}, true, err
+	}
+
+	value, err := hex.DecodeString(s)
+	if err != nil || len(value) == 0 {
+		b.ctx.GetSessionVars().StmtCtx.AppendWarning(errIncorrectArgs.GenWithStack("invalid row value: '%s'", s))
+		return tjson.BinaryJSON{}, true, nil
+	}
+	return decodeRowToJSON(b.ctx, tblInfo, value, s)
+}
+
+// getDecodeRowTableInfo returns the info of the table whose rows are decoded, and checks that the current user
+// has the SELECT privilege on it.
+func getDecodeRowTableInfo(ctx sessionctx.Context, tableName string) (*model.TableInfo, error) {
+	db, tbl, err := getSchemaAndSequence(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	checker := privilege.GetPrivilegeManager(ctx)
+	user := ctx.GetSessionVars().User
+	if checker != nil && !checker.RequestVerification(ctx.GetSessionVars().ActiveRoles, db, tbl, "", mysql.SelectPriv) {
+		return nil, errTableAccessDenied.GenWithStackByArgs("SELECT", user.AuthUsername, user.AuthHostname, tbl)
+	}
+	return util.GetTableInfoByName(ctx.GetInfoSchema(), model.NewCIStr(db), model.NewCIStr(tbl))
+}
+
+// decodeRowToJSON decodes the row value with the schema of tblInfo into an object of column name to value.
+// s is the original argument, which is shown in the warning if the value can't be decoded.
+func decodeRowToJSON(ctx sessionctx.Context, tblInfo *model.TableInfo, value []byte, s string) (tjson.BinaryJSON, bool, error) {
+	sc := ctx.GetSessionVars().StmtCtx
+	cols := make(map[int64]*types.FieldType, len(tblInfo.Columns))
+	names := make(map[int64]string, len(tblInfo.Columns))
+	for _, col := range tblInfo.Cols() {
+		cols[col.ID] = &col.FieldType
+		names[col.ID] = col.Name.O
+	}
+	datums, err := tablecodec.DecodeRowToDatumMap(value, cols, ctx.GetSessionVars().Location())
+	if err != nil {
+		sc.AppendWarning(errUnknown.GenWithStack("decode row value '%s' of table %s failed with error: %v", s, tblInfo.Name.O, err))
+		return tjson.BinaryJSON{}, true, nil
+	}
+	result := make(map[string]interface{}, len(datums))
+	for id := range datums {
+		d := datums[id]
+		v, err := DatumToJSONObject(&d)
+		if err != nil {
+			sc.AppendWarning(errUnknown.GenWithStack("decode row value '%s' of table %s failed with error: %v", s, tblInfo.Name.O, err))
+			return tjson.BinaryJSON{}, true, nil
+		}
+		result[names[id]] = v
+	}
+	return tjson.CreateBinary(result), false, nil
+}
+
+type tidbDecodeBase64RowFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbDecodeBase64RowFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETJson, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinTiDBDecodeBase64RowSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBDecodeBase64RowSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBDecodeBase64RowSig) Clone() builtinFunc {
+	newSig := &builtinTiDBDecodeBase64RowSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinTiDBDecodeBase64RowSig.
+// It works like TIDB_DECODE_ROW, except that the row value is encoded in base64 rather than hex.
+func (b *builtinTiDBDecodeBase64RowSig) evalJSON(row chunk.Row) (tjson.BinaryJSON, bool, error) {
+	tableName, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return tjson.BinaryJSON{}, isNull, err
+	}
+	s, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return tjson.BinaryJSON{}, isNull, err
+	}
+	tblInfo, err := getDecodeRowTableInfo(b.ctx, tableName)
+	if err != nil {
+		return tjson.BinaryJSON{}, true, err
+	}
+
+	value, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(value) == 0 {
+		b.ctx.GetSessionVars().StmtCtx.AppendWarning(errIncorrectArgs.GenWithStack("invalid base64 row value: '%s'", s))
+		return tjson.BinaryJSON{}, true, nil
+	}
+	return decodeRowToJSON(b.ctx, tblInfo, value, s)
+}
+
+type tidbCurrentStmtTypeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbCurrentStmtTypeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = 64
+	sig := &builtinTiDBCurrentStmtTypeSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBCurrentStmtTypeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBCurrentStmtTypeSig) Clone() builtinFunc {
+	newSig := &builtinTiDBCurrentStmtTypeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinTiDBCurrentStmtTypeSig.
+// It returns the label of the executing statement, which is the same as the `Stmt_type` in the slow log,
+// or an empty string if the statement type is unknown.
+func (b *builtinTiDBCurrentStmtTypeSig) evalString(_ chunk.Row) (string, bool, error) {
+	stmtType := b.ctx.GetSessionVars().StmtCtx.StmtType
+	if stmtType == "other" {
+		stmtType = ""
+	}
+	return stmtType, false, nil
+}
+
+type tidbTraceIDFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbTraceIDFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = 16
+	sig := &builtinTiDBTraceIDSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBTraceIDSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBTraceIDSig) Clone() builtinFunc {
+	newSig := &builtinTiDBTraceIDSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinTiDBTraceIDSig.
+// It returns the id of the distributed trace propagated for the current statement as a hex string,
+// or an empty string if the statement is not traced.
+func (b *builtinTiDBTraceIDSig) evalString(_ chunk.Row) (string, bool, error) {
+	return b.ctx.GetSessionVars().StmtCtx.TraceID, false, nil
+}
+
+type tidbCurrentBatchSizeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbCurrentBatchSizeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinTiDBCurrentBatchSizeSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBCurrentBatchSizeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBCurrentBatchSizeSig) Clone() builtinFunc {
+	newSig := &builtinTiDBCurrentBatchSizeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinTiDBCurrentBatchSizeSig.
+// It returns the max row count of a chunk used by the executors of the current session.
+func (b *builtinTiDBCurrentBatchSizeSig) evalInt(_ chunk.Row) (int64, bool, error) {
+	return int64(b.ctx.GetSessionVars().MaxChunkSize), false, nil
+}
+
+type tidbCurrentSQLDigestTextFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbCurrentSQLDigestTextFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinTiDBCurrentSQLDigestTextSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBCurrentSQLDigestTextSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBCurrentSQLDigestTextSig) Clone() builtinFunc {
+	newSig := &builtinTiDBCurrentSQLDigestTextSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinTiDBCurrentSQLDigestTextSig.
+// It returns the normalized text of the executing statement, which is the same as the `DIGEST_TEXT` in the statements summary,
+// or an empty string if the statement has no text.
+func (b *builtinTiDBCurrentSQLDigestTextSig) evalString(_ chunk.Row) (string, bool, error) {
+	normalized, _ := b.ctx.GetSessionVars().StmtCtx.SQLDigest()
+	return normalized, false, nil
+}
+
+type tidbPlanDigestFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbPlanDigestFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = 64
+	sig := &builtinTiDBPlanDigestSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBPlanDigestSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBPlanDigestSig) Clone() builtinFunc {
+	newSig := &builtinTiDBPlanDigestSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinTiDBPlanDigestSig.
+// It returns the digest of the plan of the executing statement, which is the same as the `PLAN_DIGEST` in the
+// statements summary, or an empty string if the statement has no plan digest.
+func (b *builtinTiDBPlanDigestSig) evalString(_ chunk.Row) (string, bool, error) {
+	_, planDigest := b.ctx.GetSessionVars().StmtCtx.GetPlanDigest()
+	if planDigest == nil {
+		return "", false, nil
+	}
+	return planDigest.String(), false, nil
+}
+
+type tidbCurrentIsolationLevelFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbCurrentIsolationLevelFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinTiDBCurrentIsolationLevelSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBCurrentIsolationLevelSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBCurrentIsolationLevelSig) Clone() builtinFunc {
+	newSig := &builtinTiDBCurrentIsolationLevelSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinTiDBCurrentIsolationLevelSig.
+// It returns the isolation level of the current transaction, which takes the level set by
+// `SET TRANSACTION ISOLATION LEVEL` for the next transaction into account.
+func (b *builtinTiDBCurrentIsolationLevelSig) evalString(_ chunk.Row) (string, bool, error) {
+	return b.ctx.GetSessionVars().GetIsolation(), false, nil
+}
+
+type tidbCurrentTxnIDFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbCurrentTxnIDFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flag |= mysql.UnsignedFlag
+	sig := &builtinTiDBCurrentTxnIDSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBCurrentTxnIDSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBCurrentTxnIDSig) Clone() builtinFunc {
+	newSig := &builtinTiDBCurrentTxnIDSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinTiDBCurrentTxnIDSig.
+// It returns the start ts of the active transaction, or NULL if the session isn't in a transaction,
+// e.g. an auto-committed statement.
+func (b *builtinTiDBCurrentTxnIDSig) evalInt(_ chunk.Row) (int64, bool, error) {
+	sessVars := b.ctx.GetSessionVars()
+	if !sessVars.InTxn() || sessVars.TxnCtx == nil || sessVars.TxnCtx.StartTS == 0 {
+		return 0, true, nil
+	}
+	return int64(sessVars.TxnCtx.StartTS), false, nil
+}
+
+type tidbDecodeIndexValueFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbDecodeIndexValueFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETJson, types.ETString, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinTiDBDecodeIndexValueSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBDecodeIndexValueSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBDecodeIndexValueSig) Clone() builtinFunc {
+	newSig := &builtinTiDBDecodeIndexValueSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinTiDBDecodeIndexValueSig.
+// It decodes the fields stored in an index value, i.e. the handle of a unique index, the partition id of
+// a global index, the restored data and whether the index value is untouched.
+func (b *builtinTiDBDecodeIndexValueSig) evalJSON(row chunk.Row) (tjson.BinaryJSON, bool, error) {
+	tableName, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return tjson.BinaryJSON{}, isNull, err
+	}
+	indexName, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return tjson.BinaryJSON{}, isNull, err
+	}
+	s, isNull, err := b.args[2].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return tjson.BinaryJSON{}, isNull, err
+	}
+
+	db, tbl, err := getSchemaAndSequence(b.ctx, tableName)
+	if err != nil {
+		return tjson.BinaryJSON{}, true, err
+	}
+	checker := privilege.GetPrivilegeManager(b.ctx)
+	user := b.ctx.GetSessionVars().User
+	if checker != nil && !checker.RequestVerification(b.ctx.GetSessionVars().ActiveRoles, db, tbl, "", mysql.SelectPriv) {
+		return tjson.BinaryJSON{}, true, errTableAccessDenied.GenWithStackByArgs("SELECT", user.AuthUsername, user.AuthHostname, tbl)
+	}
+	tblInfo, err := util.GetTableInfoByName(b.ctx.GetInfoSchema(), model.NewCIStr(db), model.NewCIStr(tbl))
+	if err != nil {
+		return tjson.BinaryJSON{}, true, err
+	}
+	idxInfo := tblInfo.FindIndexByName(strings.ToLower(indexName))
+	if idxInfo == nil {
+		return tjson.BinaryJSON{}, true, errors.Errorf("index %s doesn't exist in table %s", indexName, tblInfo.Name.O)
+	}
+
+	sc := b.ctx.GetSessionVars().StmtCtx
+	value, err := hex.DecodeString(s)
+	if err == nil {
+		var result map[string]interface{}
+		if result, err = decodeIndexValue(tblInfo, idxInfo, value); err == nil {
+			return tjson.CreateBinary(result), false, nil
+		}
+	}
+	sc.AppendWarning(errUnknown.GenWithStack("decode index value '%s' of index %s failed with error: %v", s, idxInfo.Name.O, err))
+	return tjson.BinaryJSON{}, true, nil
+}
+
+// decodeIndexValue decodes the index value in both the old and the new layout. See tablecodec.GenIndexValuePortal
+// for the layouts. The tablecodec helpers expect a well-formed value and panic otherwise, so the panic is turned
+// into an error.
+func decodeIndexValue(tblInfo *model.TableInfo, idxInfo *model.IndexInfo, value []byte) (result map[string]interface{}, err error) {
+	errInvalid := errors.New("invalid index value")
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, errInvalid
+		}
+	}()
+	result = make(map[string]interface{})
+	var segs tablecodec.IndexValueSegments
+	hasHandle := false
+	if tblInfo.IsCommonHandle && tblInfo.CommonHandleVersion == 1 {
+		// The version 1 layout may be shorter than the old layout, so it's told by the table instead of the length.
+		if len(value) < 3 || value[1] != tablecodec.IndexVersionFlag || value[2] != 1 {
+			return nil, errInvalid
+		}
+		segs = tablecodec.SplitIndexValueForClusteredIndexVersion1(value)
+		hasHandle = segs.CommonHandle != nil
+		result["untouched"] = value[0] == 1
+	} else {
+		result["untouched"] = tablecodec.IsUntouchedIndexKValue(tablecodec.EncodeIndexSeekKey(tblInfo.ID, idxInfo.ID, nil), value)
+		if len(value) <= tablecodec.MaxOldEncodeValueLen {
+			switch len(value) {
+			case 1:
+				if value[0] != '0' && value[0] != kv.UnCommitIndexKVFlag {
+					return nil, errInvalid
+				}
+			case 8, 9:
+				if !(idxInfo.Unique || idxInfo.Primary) || tblInfo.IsCommonHandle {
+					return nil, errInvalid
+				}
+				hasHandle = true
+			default:
+				return nil, errInvalid
+			}
+		} else {
+			if int(value[0]) >= len(value) {
+				return nil, errInvalid
+			}
+			segs = tablecodec.SplitIndexValue(value)
+			hasHandle = segs.IntHandle != nil || segs.CommonHandle != nil
+		}
+	}
+	if hasHandle {
+		h, err := tablecodec.DecodeHandleInUniqueIndexValue(value, tblInfo.IsCommonHandle)
+		if err != nil {
+			return nil, err
+		}
+		if h.IsInt() {
+			result["handle"] = h.IntValue()
+		} else {
+			result["handle"] = h.String()
+		}
+	}
+	if segs.PartitionID != nil {
+		_, pid, err := codec.DecodeInt(segs.PartitionID)
+		if err != nil {
+			return nil, err
+		}
+		result["partition_id"] = pid
+	}
+	if segs.RestoredValues != nil {
+		result["restored_data"] = strings.ToUpper(hex.EncodeToString(segs.RestoredValues))
+	}
+	return result, nil
+}
+
+// DatumToJSONObject converts a decoded datum to the value stored in the JSON
+// output of the TiDB key and row decoding functions.
+func DatumToJSONObject(d *types.Datum) (interface{}, error) {
+	if d.IsNull() {
+		return nil, nil
+	}
+	return d.ToString()
+}
+
+type tidbEncodeSQLDigestFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbEncodeSQLDigestFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = 64
+	sig := &builtinTiDBEncodeSQLDigestSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBEncodeSQLDigestSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBEncodeSQLDigestSig) Clone() builtinFunc {
+	newSig := &builtinTiDBEncodeSQLDigestSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinTiDBEncodeSQLDigestSig.
+// It normalizes the statement and returns its digest, which is the same as the `DIGEST` in the statements summary.
+// The digest can be decoded back to the normalized statement by TIDB_DECODE_SQL_DIGESTS().
+func (b *builtinTiDBEncodeSQLDigestSig) evalString(row chunk.Row) (string, bool, error) {
+	sql, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	_, digest := parser.NormalizeDigest(sql)
+	return digest.String(), false, nil
+}
+
+type tidbParseAndExplainFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbParseAndExplainFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETJson, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinTiDBParseAndExplainSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBParseAndExplainSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBParseAndExplainSig) Clone() builtinFunc {
+	newSig := &builtinTiDBParseAndExplainSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinTiDBParseAndExplainSig.
+// The statement is only parsed and optimized, it's never executed.
+func (b *builtinTiDBParseAndExplainSig) evalJSON(row chunk.Row) (tjson.BinaryJSON, bool, error) {
+	sql, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return tjson.BinaryJSON{}, isNull, err
+	}
+	fn := b.ctx.Value(TiDBParseAndExplainFunctionKey)
+	if fn == nil {
+		return tjson.BinaryJSON{}, true, errors.Errorf("%s is not supported in this context", ast.TiDBParseAndExplain)
+	}
+	plan, err := fn.(func(ctx sessionctx.Context, sql string) (string, error))(b.ctx, sql)
+	if err == nil {
+		var res tjson.BinaryJSON
+		res, err = tjson.ParseBinaryFromString(plan)
+		if err == nil {
+			return res, false, nil
+		}
+	}
+	b.ctx.GetSessionVars().StmtCtx.AppendWarning(err)
+	return tjson.BinaryJSON{}, true, nil
+}
+
+// TiDBParseAndExplainFunctionKeyType is used to identify the explain function in context.
+type TiDBParseAndExplainFunctionKeyType int
+
+// String() implements Stringer.
+func (k TiDBParseAndExplainFunctionKeyType) String() string {
+	return "tidb_parse_and_explain"
+}
+
+// TiDBParseAndExplainFunctionKey is used to identify the explain function in context.
+const TiDBParseAndExplainFunctionKey TiDBParseAndExplainFunctionKeyType = 0
+
+type tidbEstimateIndexSelectivityFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbEstimateIndexSelectivityFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETReal, types.ETString, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinTiDBEstimateIndexSelectivitySig{bf}
+	return sig, nil
+}
+
+type builtinTiDBEstimateIndexSelectivitySig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBEstimateIndexSelectivitySig) Clone() builtinFunc {
+	newSig := &builtinTiDBEstimateIndexSelectivitySig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinTiDBEstimateIndexSelectivitySig.
+// It returns the estimated fraction of the rows of the table which are read by the range scan on the index,
+// if the predicate is used as the where condition of the table.
+func (b *builtinTiDBEstimateIndexSelectivitySig) evalReal(row chunk.Row) (float64, bool, error) {
+	tableName, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	indexName, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	predicate, isNull, err := b.args[2].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+
+	db, tbl, err := getSchemaAndSequence(b.ctx, tableName)
+	if err != nil {
+		return 0, true, err
+	}
+	checker := privilege.GetPrivilegeManager(b.ctx)
+	user := b.ctx.GetSessionVars().User
+	if checker != nil && !checker.RequestVerification(b.ctx.GetSessionVars().ActiveRoles, db, tbl, "", mysql.SelectPriv) {
+		return 0, true, errTableAccessDenied.GenWithStackByArgs("SELECT", user.AuthUsername, user.AuthHostname, tbl)
+	}
+	tblInfo, err := util.GetTableInfoByName(b.ctx.GetInfoSchema(), model.NewCIStr(db), model.NewCIStr(tbl))
+	if err != nil {
+		return 0, true, err
+	}
+	idxInfo := tblInfo.FindIndexByName(strings.ToLower(indexName))
+	if idxInfo == nil {
+		return 0, true, errUnknown.GenWithStack("index %s doesn't exist in table %s", indexName, tblInfo.Name.O)
+	}
+	fn := b.ctx.Value(TiDBEstimateIndexSelectivityFunctionKey)
+	if fn == nil {
+		return 0, true, errors.Errorf("%s is not supported in this context", ast.TiDBEstimateIndexSelectivity)
+	}
+	estimate := fn.(func(ctx sessionctx.Context, dbName string, tblInfo *model.TableInfo, idxInfo *model.IndexInfo, predicate string) (float64, error))
+	selectivity, err := estimate(b.ctx, db, tblInfo, idxInfo, predicate)
+	if err != nil {
+		b.ctx.GetSessionVars().StmtCtx.AppendWarning(err)
+		return 0, true, nil
+	}
+	return selectivity, false, nil
+}
+
+// TiDBEstimateIndexSelectivityFunctionKeyType is used to identify the selectivity estimation function in context.
+type TiDBEstimateIndexSelectivityFunctionKeyType int
+
+// String() implements Stringer.
+func (k TiDBEstimateIndexSelectivityFunctionKeyType) String() string {
+	return "tidb_estimate_index_selectivity"
+}
+
+// TiDBEstimateIndexSelectivityFunctionKey is used to identify the selectivity estimation function in context.
+const TiDBEstimateIndexSelectivityFunctionKey TiDBEstimateIndexSelectivityFunctionKeyType = 0
+
+type tidbEstimateCostFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbEstimateCostFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETReal, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinTiDBEstimateCostSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBEstimateCostSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBEstimateCostSig) Clone() builtinFunc {
+	newSig := &builtinTiDBEstimateCostSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinTiDBEstimateCostSig.
+// It returns the estimated cost of the root operator of the plan chosen for the statement.
+// Same as TIDB_PARSE_AND_EXPLAIN(), the statement is only parsed and optimized, it's never executed.
+func (b *builtinTiDBEstimateCostSig) evalReal(row chunk.Row) (float64, bool, error) {
+	sql, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	fn := b.ctx.Value(TiDBEstimateCostFunctionKey)
+	if fn == nil {
+		return 0, true, errors.Errorf("%s is not supported in this context", ast.TiDBEstimateCost)
+	}
+	cost, err := fn.(func(ctx sessionctx.Context, sql string) (float64, error))(b.ctx, sql)
+	if err != nil {
+		b.ctx.GetSessionVars().StmtCtx.AppendWarning(err)
+		return 0, true, nil
+	}
+	return cost, false, nil
+}
+
+// TiDBEstimateCostFunctionKeyType is used to identify the cost estimation function in context.
+type TiDBEstimateCostFunctionKeyType int
+
+// String() implements Stringer.
+func (k TiDBEstimateCostFunctionKeyType) String() string {
+	return "tidb_estimate_cost"
+}
+
+// TiDBEstimateCostFunctionKey is used to identify the cost estimation function in context.
+const TiDBEstimateCostFunctionKey TiDBEstimateCostFunctionKeyType = 0
+
+type tidbDecodeAutoRandomFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbDecodeAutoRandomFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETJson, types.ETString, types.ETInt)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinTiDBDecodeAutoRandomSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBDecodeAutoRandomSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBDecodeAutoRandomSig) Clone() builtinFunc {
+	newSig := &builtinTiDBDecodeAutoRandomSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinTiDBDecodeAutoRandomSig.
+// It splits the value of the auto_random column into the shard and the auto increment sequence,
+// e.g. {"seq": 1024, "shard": 3}.
+func (b *builtinTiDBDecodeAutoRandomSig) evalJSON(row chunk.Row) (tjson.BinaryJSON, bool, error) {
+	tableName, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return tjson.BinaryJSON{}, isNull, err
+	}
+	value, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return tjson.BinaryJSON{}, isNull, err
+	}
+	layout, err := b.getAutoRandomLayout(tableName)
+	if err != nil || layout == nil {
+		return tjson.BinaryJSON{}, true, err
+	}
+	return decodeAutoRandom(layout, value), false, nil
+}
+
+// getAutoRandomLayout returns the layout of the auto_random column of the table. It returns nil with a warning
+// if the table doesn't have an auto_random column.
+func (b *builtinTiDBDecodeAutoRandomSig) getAutoRandomLayout(tableName string) (*autoid.ShardIDLayout, error) {
+	db, tbl, err := getSchemaAndSequence(b.ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	checker := privilege.GetPrivilegeManager(b.ctx)
+	user := b.ctx.GetSessionVars().User
+	if checker != nil && !checker.RequestVerification(b.ctx.GetSessionVars().ActiveRoles, db, tbl, "", mysql.SelectPriv) {
+		return nil, errTableAccessDenied.GenWithStackByArgs("SELECT", user.AuthUsername, user.AuthHostname, tbl)
+	}
+	tblInfo, err := util.GetTableInfoByName(b.ctx.GetInfoSchema(), model.NewCIStr(db), model.NewCIStr(tbl))
+	if err != nil {
+		return nil, err
+	}
+	pkCol := tblInfo.GetPkColInfo()
+	if !tblInfo.ContainsAutoRandomBits() || pkCol == nil {
+		b.ctx.GetSessionVars().StmtCtx.AppendWarning(errIncorrectArgs.GenWithStack("table %s doesn't have an auto_random column", tblInfo.Name.O))
+		return nil, nil
+	}
+	return autoid.NewShardIDLayout(&pkCol.FieldType, tblInfo.AutoRandomBits), nil
+}
+
+// decodeAutoRandom splits the auto_random value into the shard bits and the incremental bits of the layout.
+func decodeAutoRandom(layout *autoid.ShardIDLayout, value int64) tjson.BinaryJSON {
+	// The bits of the unsigned value which is larger than MaxInt64 are kept in the int64 value.
+	seq := uint64(value & layout.IncrementalMask())
+	shard := (uint64(value) >> layout.IncrementalBits) & (1<<layout.ShardBits - 1)
+	return tjson.CreateBinary(map[string]interface{}{
+		"shard": shard,
+		"seq":   seq,
+	})
+}
+
+type tidbSessionAliveFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbSessionAliveFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+
+	pm := privilege.GetPrivilegeManager(ctx)
+	if pm != nil && !pm.RequestVerification(ctx.GetSessionVars().ActiveRoles, "", "", "", mysql.ProcessPriv) {
+		return nil, errSpecificAccessDenied.GenWithStackByArgs("PROCESS")
+	}
+
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, types.ETInt)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = 1
+	sig := &builtinTiDBSessionAliveSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBSessionAliveSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBSessionAliveSig) Clone() builtinFunc {
+	newSig := &builtinTiDBSessionAliveSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinTiDBSessionAliveSig.
+// It returns 1 if the connection of the id is still connected to this TiDB server, or 0 if it's not.
+// It returns NULL if there is no session manager to look the connection up, e.g. in the internal sessions.
+func (b *builtinTiDBSessionAliveSig) evalInt(row chunk.Row) (int64, bool, error) {
+	connID, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	sm := b.ctx.GetSessionManager()
+	if sm == nil {
+		return 0, true, nil
+	}
+	if connID < 0 && !mysql.HasUnsignedFlag(b.args[0].GetType().Flag) {
+		return 0, false, nil
+	}
+	if _, ok := sm.GetProcessInfo(uint64(connID)); ok {
+		return 1, false, nil
+	}
+	return 0, false, nil
+}
+
+type tidbEncodeTimeRangeKeysFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbEncodeTimeRangeKeysFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETJson, types.ETString, types.ETString, types.ETDatetime, types.ETDatetime, types.ETInt)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinTiDBEncodeTimeRangeKeysSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBEncodeTimeRangeKeysSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBEncodeTimeRangeKeysSig) Clone() builtinFunc {
+	newSig := &builtinTiDBEncodeTimeRangeKeysSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinTiDBEncodeTimeRangeKeysSig.
+// It returns the index keys at every interval boundary in [start_time, end_time], which can be
+// used to pre-split the regions of an index whose first column is a time column.
+func (b *builtinTiDBEncodeTimeRangeKeysSig) evalJSON(row chunk.Row) (tjson.BinaryJSON, bool, error) {
+	tableName, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return tjson.BinaryJSON{}, isNull, err
+	}
+	indexName, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return tjson.BinaryJSON{}, isNull, err
+	}
+	start, isNull, err := b.args[2].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return tjson.BinaryJSON{}, isNull, err
+	}
+	end, isNull, err := b.args[3].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return tjson.BinaryJSON{}, isNull, err
+	}
+	interval, isNull, err := b.args[4].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return tjson.BinaryJSON{}, isNull, err
+	}
+	if interval <= 0 {
+		return tjson.BinaryJSON{}, true, errIncorrectArgs.GenWithStack("Incorrect arguments to %s: interval_seconds should be positive, but got %d", ast.TiDBEncodeTimeRangeKeys, interval)
+	}
+	if start.Compare(end) > 0 {
+		return tjson.BinaryJSON{}, true, errIncorrectArgs.GenWithStack("Incorrect arguments to %s: start_time %s is later than end_time %s", ast.TiDBEncodeTimeRangeKeys, start, end)
+	}
+
+	db, tbl, err := getSchemaAndSequence(b.ctx, tableName)
+	if err != nil {
+		return tjson.BinaryJSON{}, true, err
+	}
+	// Splitting regions needs the same privilege as SPLIT TABLE, which writes the table.
+	checker := privilege.GetPrivilegeManager(b.ctx)
+	user := b.ctx.GetSessionVars().User
+	if checker != nil && !checker.RequestVerification(b.ctx.GetSessionVars().ActiveRoles, db, tbl, "", mysql.InsertPriv) {
+		return tjson.BinaryJSON{}, true, errTableAccessDenied.GenWithStackByArgs("INSERT", user.AuthUsername, user.AuthHostname, tbl)
+	}
+	tblInfo, err := util.GetTableInfoByName(b.ctx.GetInfoSchema(), model.NewCIStr(db), model.NewCIStr(tbl))
+	if err != nil {
+		return tjson.BinaryJSON{}, true, err
+	}
+	if tblInfo.GetPartitionInfo() != nil {
+		return tjson.BinaryJSON{}, true, errors.Errorf("%s doesn't support partitioned table %s", ast.TiDBEncodeTimeRangeKeys, tblInfo.Name.O)
+	}
+	idxInfo := tblInfo.FindIndexByName(strings.ToLower(indexName))
+	if idxInfo == nil {
+		return tjson.BinaryJSON{}, true, errors.Errorf("index %s doesn't exist in table %s", indexName, tblInfo.Name.O)
+	}
+	col := tblInfo.Columns[idxInfo.Columns[0].Offset]
+	if col.Tp != mysql.TypeDatetime && col.Tp != mysql.TypeTimestamp && col.Tp != mysql.TypeDate {
+		return tjson.BinaryJSON{}, true, errors.Errorf("the first column %s of index %s is not a time column", col.Name.O, idxInfo.Name.O)
+	}
+
+	sc := b.ctx.GetSessionVars().StmtCtx
+	step := types.Duration{Duration: time.Duration(interval) * time.Second}
+	maxKeys := int(config.GetGlobalConfig().SplitRegionMaxNum)
+	keys := make([]interface{}, 0)
+	for t := start; t.Compare(end) <= 0; {
+		if len(keys) >= maxKeys {
+			return tjson.BinaryJSON{}, true, errors.Errorf("%s generates more than %d keys", ast.TiDBEncodeTimeRangeKeys, maxKeys)
+		}
+		td := types.NewTimeDatum(t)
+		d, err := td.ConvertTo(sc, &col.FieldType)
+		if err != nil {
+			return tjson.BinaryJSON{}, true, err
+		}
+		if col.Tp == mysql.TypeTimestamp {
+			ts := d.GetMysqlTime()
+			if err = ts.ConvertTimeZone(b.ctx.GetSessionVars().Location(), time.UTC); err != nil {
+				return tjson.BinaryJSON{}, true, err
+			}
+			d.SetMysqlTime(ts)
+		}
+		encoded, err := codec.EncodeKey(sc, nil, d)
+		if err != nil {
+			return tjson.BinaryJSON{}, true, err
+		}
+		key := tablecodec.EncodeIndexSeekKey(tblInfo.ID, idxInfo.ID, encoded)
+		keys = append(keys, strings.ToUpper(hex.EncodeToString(key)))
+		if t, err = t.Add(sc, step); err != nil {
+			return tjson.BinaryJSON{}, true, err
+		}
+	}
+	return tjson.CreateBinary(keys), false, nil
+}
+
+type tidbDecodeSQLDigestsFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbDecodeSQLDigestsFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+
+	pm := privilege.GetPrivilegeManager(ctx)
+	if pm != nil && !pm.RequestVerification(ctx.GetSessionVars().ActiveRoles, "", "", "", mysql.ProcessPriv) {
+		return nil, errSpecificAccessDenied.GenWithStackByArgs("PROCESS")
+	}
+
+	// The optional arguments are the truncate length of the statements and whether to decode in strict mode.
+	argTps := []types.EvalType{types.ETString}
+	for range args[1:] {
+		argTps = append(argTps, types.ETInt)
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, argTps...)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinTiDBDecodeSQLDigestsSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBDecodeSQLDigestsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBDecodeSQLDigestsSig) Clone() builtinFunc {
+	newSig := &builtinTiDBDecodeSQLDigestsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinTiDBDecodeSQLDigestsSig) evalString(row chunk.Row) (string, bool, error) {
+	args := b.getArgs()
+	digestsStr, isNull, err := args[0].EvalString(b.ctx, row)
+	if err != nil {
+		return "", true, err
+	}
+	if isNull {
+		return "", true, nil
+	}
+
+	stmtTruncateLength := int64(0)
+	if len(args) > 1 {
 		stmtTruncateLength, isNull, err = args[1].EvalInt(b.ctx, row)
 		if err != nil {
 			return "", true, err
@@ -829,6 +2888,17 @@ func (b *builtinTiDBDecodeSQLDigestsSig) evalString(row chunk.Row) (string, bool
 		}
 	}
 
+	// In strict mode, an error is returned if any item in the array can't be resolved to a statement. JSON null
+	// items are still mapped to null.
+	strict := false
+	if len(args) > 2 {
+		val, isNull, err := args[2].EvalInt(b.ctx, row)
+		if err != nil {
+			return "", true, err
+		}
+		strict = !isNull && val != 0
+	}
+
 	var digests []interface{}
 	err = json.Unmarshal([]byte(digestsStr), &digests)
 	if err != nil {
@@ -840,33 +2910,41 @@ func (b *builtinTiDBDecodeSQLDigestsSig) evalString(row chunk.Row) (string, bool
 		return "", true, nil
 	}
 
-	// Query the SQL Statements by digests.
+	// Query the SQL Statements by digests. The digests that have been retrieved by the former rows of the statement
+	// are taken from the cache.
+	cache := getSQLDigestTextCache(b.ctx.GetSessionVars().StmtCtx)
+	texts := make(map[string]string, len(digests))
 	retriever := NewSQLDigestTextRetriever()
+	cache.Lock()
 	for _, item := range digests {
-		if item != nil {
-			digest, ok := item.(string)
-			if ok {
+		if digest, ok := item.(string); ok {
+			if text, ok := cache.texts[digest]; ok {
+				texts[digest] = text
+			} else {
 				retriever.SQLDigestsMap[digest] = ""
 			}
 		}
 	}
+	cache.Unlock()
 
-	// Querying may take some time and it takes a context.Context as argument, which is not available here.
-	// We simply create a context with a timeout here.
-	timeout := time.Duration(b.ctx.GetSessionVars().MaxExecutionTime) * time.Millisecond
-	if timeout == 0 || timeout > 20*time.Second {
-		timeout = 20 * time.Second
-	}
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
-	err = retriever.RetrieveGlobal(ctx, b.ctx)
-	if err != nil {
-		if errors.Cause(err) == context.DeadlineExceeded || errors.Cause(err) == context.Canceled {
-			return "", true, errUnknown.GenWithStack("Retrieving cancelled internally with error: %v", err)
-		}
+	if len(retriever.SQLDigestsMap) > 0 {
+		// Querying may take some time and it takes a context.Context as argument, which is not available here.
+		// We simply create a context with a timeout which doesn't exceed the time left for the statement, and the
+		// digests are returned unresolved if there's too little time left.
+		timeout, ok := getSQLDigestsRetrievalTimeout(b.ctx.GetSessionVars())
+		if ok {
+			err = b.retrieveSQLDigests(retriever, cache, texts, timeout)
+			if err != nil {
+				if errors.Cause(err) == context.DeadlineExceeded || errors.Cause(err) == context.Canceled {
+					return "", true, errUnknown.GenWithStack("Retrieving cancelled internally with error: %v", err)
+				}
 
-		b.ctx.GetSessionVars().StmtCtx.AppendWarning(errUnknown.GenWithStack("Retrieving statements information failed with error: %v", err))
-		return "", true, nil
+				b.ctx.GetSessionVars().StmtCtx.AppendWarning(errUnknown.GenWithStack("Retrieving statements information failed with error: %v", err))
+				return "", true, nil
+			}
+		} else {
+			b.ctx.GetSessionVars().StmtCtx.AppendWarning(errUnknown.GenWithStack("Retrieving statements information is skipped as the statement is about to exceed max_execution_time"))
+		}
 	}
 
 	// Collect the result.
@@ -876,7 +2954,7 @@ func (b *builtinTiDBDecodeSQLDigestsSig) evalString(row chunk.Row) (string, bool
 			continue
 		}
 		if digest, ok := item.(string); ok {
-			if stmt, ok := retriever.SQLDigestsMap[digest]; ok && len(stmt) > 0 {
+			if stmt, ok := texts[digest]; ok && len(stmt) > 0 {
 				// Truncate too-long statements if necessary.
 				if stmtTruncateLength > 0 && int64(len(stmt)) > stmtTruncateLength {
 					stmt = stmt[:stmtTruncateLength] + "..."
@@ -884,6 +2962,10 @@ func (b *builtinTiDBDecodeSQLDigestsSig) evalString(row chunk.Row) (string, bool
 				result[i] = stmt
 			}
 		}
+		if strict && item != nil && result[i] == nil {
+			itemStr, _ := json.Marshal(item)
+			return "", true, errUnknown.GenWithStack("The SQL digest %s can't be resolved", itemStr)
+		}
 	}
 
 	resultStr, err := json.Marshal(result)
@@ -895,6 +2977,67 @@ func (b *builtinTiDBDecodeSQLDigestsSig) evalString(row chunk.Row) (string, bool
 	return string(resultStr), false, nil
 }
 
+// retrieveSQLDigests retrieves the statements of the digests not cached, and puts them into the cache and texts.
+func (b *builtinTiDBDecodeSQLDigestsSig) retrieveSQLDigests(retriever *SQLDigestTextRetriever, cache *sqlDigestTextCache,
+	texts map[string]string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	err := retriever.RetrieveGlobal(ctx, b.ctx)
+	if err != nil {
+		return err
+	}
+
+	cache.Lock()
+	for digest, text := range retriever.SQLDigestsMap {
+		cache.texts[digest] = text
+		texts[digest] = text
+	}
+	cache.Unlock()
+	return nil
+}
+
+const (
+	// maxSQLDigestsRetrievalTimeout is the max time to retrieve the statements for TIDB_DECODE_SQL_DIGESTS().
+	maxSQLDigestsRetrievalTimeout = 20 * time.Second
+	// minSQLDigestsRetrievalTimeout is the min remaining time of the statement to retrieve the statements.
+	minSQLDigestsRetrievalTimeout = 100 * time.Millisecond
+)
+
+// getSQLDigestsRetrievalTimeout returns the timeout to retrieve the statements by the time left before the statement
+// exceeds max_execution_time. It returns false if there is too little time left to retrieve the statements.
+func getSQLDigestsRetrievalTimeout(sessVars *variable.SessionVars) (time.Duration, bool) {
+	maxExecutionTime := sessVars.MaxExecutionTime
+	if sessVars.StmtCtx.HasMaxExecutionTime {
+		maxExecutionTime = sessVars.StmtCtx.MaxExecutionTime
+	}
+	if maxExecutionTime == 0 {
+		return maxSQLDigestsRetrievalTimeout, true
+	}
+	remaining := time.Duration(maxExecutionTime) * time.Millisecond
+	if !sessVars.StartTime.IsZero() {
+		remaining -= time.Since(sessVars.StartTime)
+	}
+	if remaining < minSQLDigestsRetrievalTimeout {
+		return 0, false
+	}
+	if remaining > maxSQLDigestsRetrievalTimeout {
+		remaining = maxSQLDigestsRetrievalTimeout
+	}
+	return remaining, true
+}
+
+// sqlDigestTextCache caches the statement texts retrieved by tidb_decode_sql_digests within a statement, so that the
+// digests appearing in multiple rows are retrieved only once. It's only reachable from builtinTiDBDecodeSQLDigestsSig,
+// which can't be built without the PROCESS privilege.
+type sqlDigestTextCache struct {
+	sync.Mutex
+	texts map[string]string
+}
+
+func getSQLDigestTextCache(sc *stmtctx.StatementContext) *sqlDigestTextCache {
+	return sc.GetOrStoreStmtCache(stmtctx.StmtSQLDigestTextCacheKey, &sqlDigestTextCache{texts: make(map[string]string)}).(*sqlDigestTextCache)
+}
+
 type tidbDecodePlanFunctionClass struct {
 	baseFunctionClass
 }
@@ -933,6 +3076,47 @@ func (b *builtinTiDBDecodePlanSig) evalString(row chunk.Row) (string, bool, erro
 	return planTree, false, nil
 }
 
+type tidbDecodePlanStrictFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbDecodePlanStrictFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinTiDBDecodePlanStrictSig{bf}
+	return sig, nil
+}
+
+// builtinTiDBDecodePlanStrictSig is like builtinTiDBDecodePlanSig, but it returns NULL with a warning
+// instead of the original string when the plan can't be decoded.
+type builtinTiDBDecodePlanStrictSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBDecodePlanStrictSig) Clone() builtinFunc {
+	newSig := &builtinTiDBDecodePlanStrictSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinTiDBDecodePlanStrictSig) evalString(row chunk.Row) (string, bool, error) {
+	planString, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	planTree, err := plancodec.DecodePlan(planString)
+	if err != nil {
+		b.ctx.GetSessionVars().StmtCtx.AppendWarning(errUnknown.GenWithStack("decode plan failed with error: %v", err))
+		return "", true, nil
+	}
+	return planTree, false, nil
+}
+
 type nextValFunctionClass struct {
 	baseFunctionClass
 }
@@ -965,9 +3149,9 @@ func (b *builtinNextValSig) evalInt(row chunk.Row) (int64, bool, error) {
 	if isNull || err != nil {
 		return 0, isNull, err
 	}
-	db, seq := getSchemaAndSequence(sequenceName)
-	if len(db) == 0 {
-		db = b.ctx.GetSessionVars().CurrentDB
+	db, seq, err := getSchemaAndSequence(b.ctx, sequenceName)
+	if err != nil {
+		return 0, false, err
 	}
 	// Check the tableName valid.
 	sequence, err := util.GetSequenceByName(b.ctx.GetInfoSchema(), model.NewCIStr(db), model.NewCIStr(seq))
@@ -989,6 +3173,70 @@ func (b *builtinNextValSig) evalInt(row chunk.Row) (int64, bool, error) {
 	return nextVal, false, nil
 }
 
+type nextValNFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *nextValNFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, types.ETString, types.ETInt)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinNextValNSig{bf}
+	bf.tp.Flen = 10
+	return sig, nil
+}
+
+// builtinNextValNSig reserves n consecutive values of the sequence and returns the first one.
+type builtinNextValNSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinNextValNSig) Clone() builtinFunc {
+	newSig := &builtinNextValNSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinNextValNSig) evalInt(row chunk.Row) (int64, bool, error) {
+	sequenceName, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	db, seq, err := getSchemaAndSequence(b.ctx, sequenceName)
+	if err != nil {
+		return 0, false, err
+	}
+	// Check the tableName valid.
+	sequence, err := util.GetSequenceByName(b.ctx.GetInfoSchema(), model.NewCIStr(db), model.NewCIStr(seq))
+	if err != nil {
+		return 0, false, err
+	}
+	// Do the privilege check.
+	checker := privilege.GetPrivilegeManager(b.ctx)
+	user := b.ctx.GetSessionVars().User
+	if checker != nil && !checker.RequestVerification(b.ctx.GetSessionVars().ActiveRoles, db, seq, "", mysql.InsertPriv) {
+		return 0, false, errSequenceAccessDenied.GenWithStackByArgs("INSERT", user.AuthUsername, user.AuthHostname, seq)
+	}
+	n, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if n <= 0 {
+		return 0, false, errIncorrectArgs.GenWithStackByArgs(ast.NextValN)
+	}
+	first, last, err := sequence.GetSequenceNextValN(b.ctx, db, seq, n)
+	if err != nil {
+		return 0, false, err
+	}
+	// update the sequenceState with the last reserved value, so that the next nextval() continues after the block.
+	b.ctx.GetSessionVars().SequenceState.UpdateState(sequence.GetSequenceID(), last)
+	return first, false, nil
+}
+
 type lastValFunctionClass struct {
 	baseFunctionClass
 }
@@ -1001,13 +3249,23 @@ func (c *lastValFunctionClass) getFunction(ctx sessionctx.Context, args []Expres
 	if err != nil {
 		return nil, err
 	}
-	sig := &builtinLastValSig{bf}
+	sig := &builtinLastValSig{baseBuiltinFunc: bf}
 	bf.tp.Flen = 10
 	return sig, nil
 }
 
 type builtinLastValSig struct {
 	baseBuiltinFunc
+	// cache is not serialized with builtinLastValSig, it keeps the sequence resolved from a constant argument to
+	// avoid looking up the info schema and checking the privilege for each row.
+	cache lastValSequenceCache
+}
+
+// lastValSequenceCache keeps the id of the sequence whose SELECT privilege has been checked in the statement.
+type lastValSequenceCache struct {
+	sync.Mutex
+	stmtCtx    *stmtctx.StatementContext
+	sequenceID int64
 }
 
 func (b *builtinLastValSig) Clone() builtinFunc {
@@ -1017,26 +3275,51 @@ func (b *builtinLastValSig) Clone() builtinFunc {
 }
 
 func (b *builtinLastValSig) evalInt(row chunk.Row) (int64, bool, error) {
+	stmtCtx := b.ctx.GetSessionVars().StmtCtx
+	// The info schema and the privileges may change between statements, so the sequence is resolved once per statement.
+	isConst := b.args[0].ConstItem(stmtCtx)
+	if isConst {
+		b.cache.Lock()
+		cached, sequenceID := b.cache.stmtCtx == stmtCtx, b.cache.sequenceID
+		b.cache.Unlock()
+		if cached {
+			return b.ctx.GetSessionVars().SequenceState.GetLastValue(sequenceID)
+		}
+	}
 	sequenceName, isNull, err := b.args[0].EvalString(b.ctx, row)
 	if isNull || err != nil {
 		return 0, isNull, err
 	}
-	db, seq := getSchemaAndSequence(sequenceName)
-	if len(db) == 0 {
-		db = b.ctx.GetSessionVars().CurrentDB
+	sequenceID, err := b.resolveSequence(sequenceName)
+	if err != nil {
+		return 0, false, err
+	}
+	if isConst {
+		b.cache.Lock()
+		b.cache.stmtCtx, b.cache.sequenceID = stmtCtx, sequenceID
+		b.cache.Unlock()
+	}
+	return b.ctx.GetSessionVars().SequenceState.GetLastValue(sequenceID)
+}
+
+// resolveSequence looks up the sequence by its name and checks the SELECT privilege on it.
+func (b *builtinLastValSig) resolveSequence(sequenceName string) (int64, error) {
+	db, seq, err := getSchemaAndSequence(b.ctx, sequenceName)
+	if err != nil {
+		return 0, err
 	}
 	// Check the tableName valid.
 	sequence, err := util.GetSequenceByName(b.ctx.GetInfoSchema(), model.NewCIStr(db), model.NewCIStr(seq))
 	if err != nil {
-		return 0, false, err
+		return 0, err
 	}
 	// Do the privilege check.
 	checker := privilege.GetPrivilegeManager(b.ctx)
 	user := b.ctx.GetSessionVars().User
 	if checker != nil && !checker.RequestVerification(b.ctx.GetSessionVars().ActiveRoles, db, seq, "", mysql.SelectPriv) {
-		return 0, false, errSequenceAccessDenied.GenWithStackByArgs("SELECT", user.AuthUsername, user.AuthHostname, seq)
+		return 0, errSequenceAccessDenied.GenWithStackByArgs("SELECT", user.AuthUsername, user.AuthHostname, seq)
 	}
-	return b.ctx.GetSessionVars().SequenceState.GetLastValue(sequence.GetSequenceID())
+	return sequence.GetSequenceID(), nil
 }
 
 type setValFunctionClass struct {
@@ -1071,9 +3354,9 @@ func (b *builtinSetValSig) evalInt(row chunk.Row) (int64, bool, error) {
 	if isNull || err != nil {
 		return 0, isNull, err
 	}
-	db, seq := getSchemaAndSequence(sequenceName)
-	if len(db) == 0 {
-		db = b.ctx.GetSessionVars().CurrentDB
+	db, seq, err := getSchemaAndSequence(b.ctx, sequenceName)
+	if err != nil {
+		return 0, false, err
 	}
 	// Check the tableName valid.
 	sequence, err := util.GetSequenceByName(b.ctx.GetInfoSchema(), model.NewCIStr(db), model.NewCIStr(seq))
@@ -1090,31 +3373,199 @@ func (b *builtinSetValSig) evalInt(row chunk.Row) (int64, bool, error) {
 	if isNull || err != nil {
 		return 0, isNull, err
 	}
-	return sequence.SetSequenceVal(b.ctx, setValue, db, seq)
+	// The value isn't changed if it doesn't advance the sequence, in which case NULL is returned.
+	setVal, notChanged, err := sequence.SetSequenceVal(b.ctx, setValue, db, seq)
+	if err != nil || notChanged {
+		return 0, notChanged, err
+	}
+	// update the sequenceState, so that lastval() returns the value set here like the one got by nextval().
+	b.ctx.GetSessionVars().SequenceState.UpdateState(sequence.GetSequenceID(), setVal)
+	return setVal, false, nil
 }
 
-func getSchemaAndSequence(sequenceName string) (string, string) {
-	res := strings.Split(sequenceName, ".")
-	if len(res) == 1 {
-		return "", res[0]
+// getSchemaAndSequence parses the qualified name like "db.seq" or "`db`.`my.seq`" of a sequence or a table, and
+// the current database is used if the name is unqualified.
+func getSchemaAndSequence(ctx sessionctx.Context, name string) (string, string, error) {
+	parts, err := splitQualifiedName(name)
+	if err != nil {
+		return "", "", err
 	}
-	return res[0], res[1]
+	switch len(parts) {
+	case 1:
+		return ctx.GetSessionVars().CurrentDB, parts[0], nil
+	case 2:
+		return parts[0], parts[1], nil
+	}
+	return "", "", errWrongTableName.GenWithStackByArgs(name)
 }
 
-type formatBytesFunctionClass struct {
+// splitQualifiedName splits the name into parts by the dots outside the backticks. The spaces around each part are
+// trimmed, and the backticks quoting a part are removed, where a doubled backtick stands for a backtick in the part.
+func splitQualifiedName(name string) ([]string, error) {
+	var parts []string
+	for i := 0; ; i++ {
+		for i < len(name) && name[i] == ' ' {
+			i++
+		}
+		var part strings.Builder
+		if i < len(name) && name[i] == '`' {
+			closed := false
+			for i++; i < len(name); i++ {
+				if name[i] != '`' {
+					part.WriteByte(name[i])
+				} else if i+1 < len(name) && name[i+1] == '`' {
+					part.WriteByte('`')
+					i++
+				} else {
+					closed = true
+					i++
+					break
+				}
+			}
+			for i < len(name) && name[i] == ' ' {
+				i++
+			}
+			if !closed || (i < len(name) && name[i] != '.') {
+				return nil, errWrongTableName.GenWithStackByArgs(name)
+			}
+		} else {
+			end := strings.IndexByte(name[i:], '.')
+			if end < 0 {
+				end = len(name)
+			} else {
+				end += i
+			}
+			unquoted := strings.TrimRight(name[i:end], " ")
+			if strings.IndexByte(unquoted, '`') >= 0 {
+				return nil, errWrongTableName.GenWithStackByArgs(name)
+			}
+			part.WriteString(unquoted)
+			i = end
+		}
+		if part.Len() == 0 {
+			return nil, errWrongTableName.GenWithStackByArgs(name)
+		}
+		parts = append(parts, part.String())
+		if i >= len(name) {
+			return parts, nil
+		}
+	}
+}
+
+// maxGenerateSeriesCount is the max number of values generated by TIDB_GENERATE_SERIES() for a row,
+// it keeps a huge range from eating up the memory.
+const maxGenerateSeriesCount = 1 << 16
+
+type tidbGenerateSeriesFunctionClass struct {
 	baseFunctionClass
 }
 
-func (c *formatBytesFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+func (c *tidbGenerateSeriesFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
 	if err := c.verifyArgs(args); err != nil {
 		return nil, err
 	}
-	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETReal)
+	argTps := make([]types.EvalType, 0, len(args))
+	for range args {
+		argTps = append(argTps, types.ETInt)
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETJson, argTps...)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinTiDBGenerateSeriesSig{bf}
+	return sig, nil
+}
+
+// builtinTiDBGenerateSeriesSig generates the integers from start to stop by step like a sequence, but it doesn't
+// need a sequence object. The values are returned in a JSON array, e.g. [1, 3, 5] for TIDB_GENERATE_SERIES(1, 6, 2).
+type builtinTiDBGenerateSeriesSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBGenerateSeriesSig) Clone() builtinFunc {
+	newSig := &builtinTiDBGenerateSeriesSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinTiDBGenerateSeriesSig.
+// The step defaults to 1, and a descending range needs a negative step. The result is an empty array if the range
+// goes in the opposite direction of the step.
+func (b *builtinTiDBGenerateSeriesSig) evalJSON(row chunk.Row) (tjson.BinaryJSON, bool, error) {
+	start, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return tjson.BinaryJSON{}, isNull, err
+	}
+	stop, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return tjson.BinaryJSON{}, isNull, err
+	}
+	step := int64(1)
+	if len(b.args) == 3 {
+		step, isNull, err = b.args[2].EvalInt(b.ctx, row)
+		if isNull || err != nil {
+			return tjson.BinaryJSON{}, isNull, err
+		}
+	}
+	count, err := generateSeriesCount(start, stop, step)
+	if err != nil {
+		return tjson.BinaryJSON{}, false, err
+	}
+	values := make([]interface{}, 0, count)
+	for i, v := uint64(0), start; i < count; i, v = i+1, v+step {
+		values = append(values, v)
+	}
+	return tjson.CreateBinary(values), false, nil
+}
+
+// generateSeriesCount calculates the number of values from start to stop by step without overflow.
+func generateSeriesCount(start, stop, step int64) (uint64, error) {
+	var distance, absStep uint64
+	switch {
+	case step > 0:
+		if start > stop {
+			return 0, nil
+		}
+		distance, absStep = uint64(stop)-uint64(start), uint64(step)
+	case step < 0:
+		if start < stop {
+			return 0, nil
+		}
+		distance, absStep = uint64(start)-uint64(stop), uint64(-(step+1))+1
+	default:
+		return 0, errIncorrectArgs.GenWithStackByArgs(ast.TiDBGenerateSeries)
+	}
+	count := distance/absStep + 1
+	if count > maxGenerateSeriesCount {
+		return 0, errGenerateSeriesTooLarge.GenWithStackByArgs(ast.TiDBGenerateSeries, maxGenerateSeriesCount)
+	}
+	return count, nil
+}
+
+type formatBytesFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *formatBytesFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err = c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+
+	argsTp := []types.EvalType{types.ETReal}
+	if len(args) == 2 {
+		argsTp = append(argsTp, types.ETInt)
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, argsTp...)
 	if err != nil {
 		return nil, err
 	}
 	bf.tp.Charset, bf.tp.Collate = ctx.GetSessionVars().GetCharsetInfo()
-	sig := &builtinFormatBytesSig{bf}
+
+	if len(args) == 2 {
+		sig = &builtinFormatBytesWithPrecisionSig{bf}
+	} else {
+		sig = &builtinFormatBytesSig{bf}
+	}
 	return sig, nil
 }
 
@@ -1138,6 +3589,37 @@ func (b *builtinFormatBytesSig) evalString(row chunk.Row) (string, bool, error)
 	return GetFormatBytes(val), false, nil
 }
 
+// maxFormatBytesPrecision is the max number of fractional digits of FORMAT_BYTES(value, decimals).
+const maxFormatBytesPrecision = 6
+
+type builtinFormatBytesWithPrecisionSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinFormatBytesWithPrecisionSig) Clone() builtinFunc {
+	newSig := &builtinFormatBytesWithPrecisionSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals FORMAT_BYTES(value, decimals), decimals is clamped to [0, maxFormatBytesPrecision].
+func (b *builtinFormatBytesWithPrecisionSig) evalString(row chunk.Row) (string, bool, error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	precision, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	if precision < 0 {
+		precision = 0
+	} else if precision > maxFormatBytesPrecision {
+		precision = maxFormatBytesPrecision
+	}
+	return GetFormatBytesWithPrecision(val, int(precision)), false, nil
+}
+
 type formatNanoTimeFunctionClass struct {
 	baseFunctionClass
 }
@@ -1174,3 +3656,85 @@ func (b *builtinFormatNanoTimeSig) evalString(row chunk.Row) (string, bool, erro
 	}
 	return GetFormatNanoTime(val), false, nil
 }
+
+type parseBytesFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *parseBytesFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinParseBytesSig{bf}
+	return sig, nil
+}
+
+type builtinParseBytesSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinParseBytesSig) Clone() builtinFunc {
+	newSig := &builtinParseBytesSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinParseBytesSig.
+// It's the inverse of FORMAT_BYTES(), e.g. PARSE_BYTES('1.50 KiB') returns 1536.
+func (b *builtinParseBytesSig) evalInt(row chunk.Row) (int64, bool, error) {
+	val, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	bytes, err := ParseFormatBytes(val)
+	if err != nil {
+		b.ctx.GetSessionVars().StmtCtx.AppendWarning(errIncorrectArgs.GenWithStack("invalid bytes: '%s', %v", val, err))
+		return 0, true, nil
+	}
+	return bytes, false, nil
+}
+
+type parseNanoTimeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *parseNanoTimeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinParseNanoTimeSig{bf}
+	return sig, nil
+}
+
+type builtinParseNanoTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinParseNanoTimeSig) Clone() builtinFunc {
+	newSig := &builtinParseNanoTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinParseNanoTimeSig.
+// It's the inverse of FORMAT_NANO_TIME(), e.g. PARSE_NANO_TIME('1.50 ms') returns 1500000.
+func (b *builtinParseNanoTimeSig) evalInt(row chunk.Row) (int64, bool, error) {
+	val, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	nanoTime, err := ParseFormatNanoTime(val)
+	if err != nil {
+		b.ctx.GetSessionVars().StmtCtx.AppendWarning(errIncorrectArgs.GenWithStack("invalid nano time: '%s', %v", val, err))
+		return 0, true, nil
+	}
+	return nanoTime, false, nil
+}
@@ -15,16 +15,27 @@
 package expression
 
 import (
+	"context"
+	"encoding/hex"
+	"fmt"
 	"math"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/owner"
+	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/testkit/trequire"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/mock"
 	"github.com/pingcap/tidb/util/printer"
@@ -125,6 +136,82 @@ func TestCurrentRole(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "`r_1`@`%`,`r_2`@`localhost`", d.GetString())
 	require.Equal(t, f.PbCode(), f.Clone().PbCode())
+
+	tests := []struct {
+		roles  []*auth.RoleIdentity
+		result string
+	}{
+		{[]*auth.RoleIdentity{}, "NONE"},
+		{[]*auth.RoleIdentity{{Username: "r_1", Hostname: "%"}}, "`r_1`@`%`"},
+		{
+			[]*auth.RoleIdentity{{Username: "r_c", Hostname: "%"}, {Username: "r_a", Hostname: "localhost"}, {Username: "r_b", Hostname: "%"}},
+			"`r_a`@`localhost`,`r_b`@`%`,`r_c`@`%`",
+		},
+		{
+			[]*auth.RoleIdentity{{Username: "r_2", Hostname: "%"}, {Username: "r_1", Hostname: "%"}, {Username: "r_2", Hostname: "%"}},
+			"`r_1`@`%`,`r_2`@`%`",
+		},
+	}
+	input := chunk.NewChunkWithCapacity(nil, 3)
+	input.SetNumVirtualRows(3)
+	for _, test := range tests {
+		sessionVars.ActiveRoles = test.roles
+		d, err = evalBuiltinFunc(f, chunk.Row{})
+		require.NoError(t, err)
+		require.Equal(t, test.result, d.GetString())
+
+		result := chunk.NewColumn(f.getRetTp(), 3)
+		require.NoError(t, f.vecEvalString(input, result))
+		for i := 0; i < 3; i++ {
+			require.Equal(t, test.result, result.GetString(i))
+		}
+	}
+}
+
+func TestCurrentRoleJSON(t *testing.T) {
+	t.Parallel()
+	ctx := mock.NewContext()
+	fc := funcs[ast.CurrentRoleJSON]
+	f, err := fc.getFunction(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, mysql.TypeJSON, f.getRetTp().Tp)
+	require.Equal(t, f.PbCode(), f.Clone().PbCode())
+
+	sessionVars := ctx.GetSessionVars()
+	tests := []struct {
+		roles  []*auth.RoleIdentity
+		result string
+	}{
+		{[]*auth.RoleIdentity{}, `[]`},
+		{[]*auth.RoleIdentity{{Username: "r_1", Hostname: "%"}}, "[\"`r_1`@`%`\"]"},
+		{
+			[]*auth.RoleIdentity{{Username: "r_c", Hostname: "%"}, {Username: "r_a", Hostname: "localhost"}, {Username: "r_b", Hostname: "%"}},
+			"[\"`r_a`@`localhost`\", \"`r_b`@`%`\", \"`r_c`@`%`\"]",
+		},
+		{
+			[]*auth.RoleIdentity{{Username: "r_2", Hostname: "%"}, {Username: "r_1", Hostname: "%"}, {Username: "r_2", Hostname: "%"}},
+			"[\"`r_1`@`%`\", \"`r_2`@`%`\"]",
+		},
+		// The role names containing commas are kept in their own elements.
+		{
+			[]*auth.RoleIdentity{{Username: "r,1", Hostname: "%"}, {Username: "r", Hostname: "1,%"}},
+			"[\"`r,1`@`%`\", \"`r`@`1,%`\"]",
+		},
+	}
+	input := chunk.NewChunkWithCapacity(nil, 3)
+	input.SetNumVirtualRows(3)
+	for _, test := range tests {
+		sessionVars.ActiveRoles = test.roles
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		require.NoError(t, err)
+		require.Equal(t, test.result, d.GetMysqlJSON().String())
+
+		result := chunk.NewColumn(f.getRetTp(), 3)
+		require.NoError(t, f.vecEvalJSON(input, result))
+		for i := 0; i < 3; i++ {
+			require.Equal(t, test.result, result.GetJSON(i).String())
+		}
+	}
 }
 
 func TestConnectionID(t *testing.T) {
@@ -142,6 +229,52 @@ func TestConnectionID(t *testing.T) {
 	require.Equal(t, f.PbCode(), f.Clone().PbCode())
 }
 
+type mockSessionManager struct {
+	util.SessionManager
+	ps []*util.ProcessInfo
+}
+
+// GetProcessInfo implements the SessionManager.GetProcessInfo interface.
+func (msm *mockSessionManager) GetProcessInfo(id uint64) (*util.ProcessInfo, bool) {
+	for _, item := range msm.ps {
+		if item.ID == id {
+			return item, true
+		}
+	}
+	return &util.ProcessInfo{}, false
+}
+
+func TestTiDBSessionAlive(t *testing.T) {
+	t.Parallel()
+	ctx := mock.NewContext()
+	fc := funcs[ast.TiDBSessionAlive]
+	tests := []struct {
+		connID interface{}
+		expect interface{}
+	}{
+		{uint64(1), int64(1)},
+		{uint64(2), int64(0)},
+		{int64(-1), int64(0)},
+		{nil, nil},
+	}
+
+	// There is no session manager to look the connections up.
+	f, err := fc.getFunction(ctx, datumsToConstants(types.MakeDatums(uint64(1))))
+	require.NoError(t, err)
+	d, err := evalBuiltinFunc(f, chunk.Row{})
+	require.NoError(t, err)
+	require.True(t, d.IsNull())
+
+	ctx.SetSessionManager(&mockSessionManager{ps: []*util.ProcessInfo{{ID: 1}}})
+	for _, test := range tests {
+		f, err := fc.getFunction(ctx, datumsToConstants(types.MakeDatums(test.connID)))
+		require.NoError(t, err)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		require.NoError(t, err)
+		trequire.DatumEqual(t, types.NewDatum(test.expect), d)
+	}
+}
+
 func TestVersion(t *testing.T) {
 	t.Parallel()
 	ctx := createContext(t)
@@ -154,6 +287,41 @@ func TestVersion(t *testing.T) {
 	require.Equal(t, f.PbCode(), f.Clone().PbCode())
 }
 
+func TestVersionCompare(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	cases := []struct {
+		v1, v2   interface{}
+		expected interface{}
+	}{
+		{"5.7.25-TiDB-v6.1.0", "5.7.25-TiDB-v6.1.0", int64(0)},
+		{"5.7.25-TiDB-v6.1.0", "5.7.25-TiDB-v6.1.1", int64(-1)},
+		{"5.7.25-TiDB-v6.2.0", "5.7.25-TiDB-v6.1.9", int64(1)},
+		{"5.7.25-TiDB-v6.1.0", "v6.1.0", int64(0)},
+		{"5.7.25-TiDB-v6.1.0", "6.0.0", int64(1)},
+		{"5.7.25-TiDB-v6.1.0-alpha", "5.7.25-TiDB-v6.1.0", int64(-1)},
+		{"5.7.25-TiDB-v6.1.0-alpha-211-g09beefbe0-dirty", "5.7.25-TiDB-v6.1.0-alpha", int64(0)},
+		{"5.7.25-TiDB-v6.1.0-211-g09beefbe0", "5.7.25-TiDB-v6.0.0", int64(1)},
+		{"8.0.11-TiDB-v5.4.0", "5.7.25-TiDB-v6.1.0", int64(-1)},
+		{"5.7.25-TiDB-None", "5.7.25-TiDB-v6.1.0", nil},
+		{"5.7.25-TiDB-v6.1.0", "6.1", nil},
+		{"abc", "v6.1.0", nil},
+		{nil, "v6.1.0", nil},
+		{"v6.1.0", nil, nil},
+	}
+	for _, c := range cases {
+		f, err := newFunctionForTest(ctx, ast.VersionCompare, primitiveValsToConstants(ctx, []interface{}{c.v1, c.v2})...)
+		require.NoError(t, err)
+		d, err := f.Eval(chunk.Row{})
+		require.NoError(t, err)
+		if c.expected == nil {
+			require.True(t, d.IsNull(), "%v, %v", c.v1, c.v2)
+		} else {
+			require.Equal(t, c.expected, d.GetInt64(), "%v, %v", c.v1, c.v2)
+		}
+	}
+}
+
 func TestBenchMark(t *testing.T) {
 	t.Parallel()
 	ctx := createContext(t)
@@ -196,6 +364,149 @@ func TestBenchMark(t *testing.T) {
 	}
 }
 
+func TestBenchMarkWarnThreshold(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	sessVars := ctx.GetSessionVars()
+	require.Equal(t, int64(variable.DefTiDBBenchmarkWarnThreshold), sessVars.BenchmarkWarnThreshold)
+	sessVars.BenchmarkWarnThreshold = 5
+	cases := []struct {
+		threshold int64
+		loopCount int
+		warn      bool
+	}{
+		{5, 3, false},
+		{5, 5, false},
+		{5, 6, true},
+		{0, 6, false},
+	}
+	for _, c := range cases {
+		sessVars.BenchmarkWarnThreshold = c.threshold
+		sessVars.StmtCtx.SetWarnings(nil)
+		f, err := newFunctionForTest(ctx, ast.Benchmark, primitiveValsToConstants(ctx, []interface{}{c.loopCount, 1})...)
+		require.NoError(t, err)
+		d, err := f.Eval(chunk.Row{})
+		require.NoError(t, err)
+		require.Equal(t, int64(0), d.GetInt64())
+		if c.warn {
+			warnings := sessVars.StmtCtx.GetWarnings()
+			require.Len(t, warnings, 1)
+			require.EqualError(t, warnings[0].Err, fmt.Sprintf("[expression:1105]BENCHMARK() loop count %d exceeds tidb_benchmark_warn_threshold(%d)", c.loopCount, c.threshold))
+		} else {
+			require.Equal(t, uint16(0), sessVars.StmtCtx.WarningCount())
+		}
+	}
+}
+
+func TestTiDBBenchmark(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	cases := []struct {
+		LoopCount  interface{}
+		Expression interface{}
+		IsNil      bool
+	}{
+		{-3, 1, true},
+		{nil, 1, true},
+		{0, 1, false},
+		{3, 1, false},
+		{3, 1.234, false},
+		{3, types.NewDecFromFloatForTest(1.234), false},
+		{3, "abc", false},
+		{3, types.CurrentTime(mysql.TypeDatetime), false},
+		{3, types.CurrentTime(mysql.TypeDuration), false},
+		{3, json.CreateBinary("[1]"), false},
+	}
+
+	for _, c := range cases {
+		f, err := newFunctionForTest(ctx, ast.TiDBBenchmark, primitiveValsToConstants(ctx, []interface{}{
+			c.LoopCount,
+			c.Expression,
+		})...)
+		require.NoError(t, err)
+
+		d, err := f.Eval(chunk.Row{})
+		require.NoError(t, err)
+		if c.IsNil {
+			require.True(t, d.IsNull())
+		} else {
+			require.GreaterOrEqual(t, d.GetInt64(), int64(0))
+		}
+	}
+
+	// The elapsed time covers all the iterations.
+	sleep, err := newFunctionForTest(ctx, ast.Sleep, primitiveValsToConstants(ctx, []interface{}{0.001})...)
+	require.NoError(t, err)
+	f, err := newFunctionForTest(ctx, ast.TiDBBenchmark, append(primitiveValsToConstants(ctx, []interface{}{10}), sleep)...)
+	require.NoError(t, err)
+	d, err := f.Eval(chunk.Row{})
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, d.GetInt64(), int64(10*time.Millisecond))
+
+	// The eval errors are passed through.
+	strictSQLMode := ctx.GetSessionVars().StrictSQLMode
+	ctx.GetSessionVars().StrictSQLMode = true
+	defer func() {
+		ctx.GetSessionVars().StrictSQLMode = strictSQLMode
+	}()
+	sleep, err = newFunctionForTest(ctx, ast.Sleep, primitiveValsToConstants(ctx, []interface{}{-1})...)
+	require.NoError(t, err)
+	f, err = newFunctionForTest(ctx, ast.TiDBBenchmark, append(primitiveValsToConstants(ctx, []interface{}{3}), sleep)...)
+	require.NoError(t, err)
+	_, err = f.Eval(chunk.Row{})
+	require.True(t, errIncorrectArgs.Equal(err), "%v", err)
+
+	// The loop is interrupted once the query is killed.
+	f, err = newFunctionForTest(ctx, ast.TiDBBenchmark, primitiveValsToConstants(ctx, []interface{}{
+		int64(math.MaxInt64),
+		1,
+	})...)
+	require.NoError(t, err)
+	atomic.StoreUint32(&ctx.GetSessionVars().Killed, 1)
+	defer atomic.StoreUint32(&ctx.GetSessionVars().Killed, 0)
+	_, err = f.Eval(chunk.Row{})
+	require.True(t, ErrQueryInterrupted.Equal(err), "%v", err)
+}
+
+func TestBenchmarkKilled(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	f, err := newFunctionForTest(ctx, ast.Benchmark, primitiveValsToConstants(ctx, []interface{}{
+		int64(math.MaxInt64),
+		1,
+	})...)
+	require.NoError(t, err)
+	input := chunk.NewChunkWithCapacity([]*types.FieldType{types.NewFieldType(mysql.TypeLonglong)}, 1)
+	input.AppendInt64(0, 1)
+	result := chunk.NewColumn(types.NewFieldType(mysql.TypeLonglong), 1)
+
+	evals := []func() error{
+		func() error {
+			_, err := f.Eval(chunk.Row{})
+			return err
+		},
+		func() error {
+			return f.VecEvalInt(ctx, input, result)
+		},
+	}
+	for _, eval := range evals {
+		atomic.StoreUint32(&ctx.GetSessionVars().Killed, 0)
+		errCh := make(chan error, 1)
+		go func(eval func() error) {
+			errCh <- eval()
+		}(eval)
+		// Kill the query while BENCHMARK() is looping.
+		time.Sleep(10 * time.Millisecond)
+		atomic.StoreUint32(&ctx.GetSessionVars().Killed, 1)
+		select {
+		case err = <-errCh:
+			require.True(t, ErrQueryInterrupted.Equal(err), "%v", err)
+		case <-time.After(10 * time.Second):
+			require.FailNow(t, "BENCHMARK() isn't interrupted after the query is killed")
+		}
+	}
+}
+
 func TestCharset(t *testing.T) {
 	t.Parallel()
 	ctx := createContext(t)
@@ -223,6 +534,25 @@ func TestCollation(t *testing.T) {
 	require.NotNil(t, f)
 	require.NoError(t, err)
 	require.Equal(t, 64, f.getRetTp().Flen)
+
+	// A string literal without an explicit collation takes the connection collation.
+	arg := &Constant{Value: types.NewStringDatum("a"), RetType: types.NewFieldType(mysql.TypeVarString)}
+	arg.RetType.Charset, arg.RetType.Collate = ctx.GetSessionVars().GetCharsetInfo()
+	f, err = fc.getFunction(ctx, []Expression{arg})
+	require.NoError(t, err)
+	d, err := evalBuiltinFunc(f, chunk.Row{})
+	require.NoError(t, err)
+	require.Equal(t, arg.RetType.Collate, d.GetString())
+
+	// The collation set by a COLLATE clause is reported.
+	arg = &Constant{Value: types.NewStringDatum("a"), RetType: types.NewFieldType(mysql.TypeVarString)}
+	arg.RetType.Charset, arg.RetType.Collate = "utf8mb4", "utf8mb4_unicode_ci"
+	arg.SetCoercibility(CoercibilityExplicit)
+	f, err = fc.getFunction(ctx, []Expression{arg})
+	require.NoError(t, err)
+	d, err = evalBuiltinFunc(f, chunk.Row{})
+	require.NoError(t, err)
+	require.Equal(t, "utf8mb4_unicode_ci", d.GetString())
 }
 
 func TestRowCount(t *testing.T) {
@@ -255,6 +585,539 @@ func TestTiDBVersion(t *testing.T) {
 	require.Equal(t, printer.GetTiDBInfo(), v.GetString())
 }
 
+func TestTiDBVersionComment(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	f, err := newFunctionForTest(ctx, ast.TiDBVersionComment, primitiveValsToConstants(ctx, []interface{}{})...)
+	require.NoError(t, err)
+	v, err := f.Eval(chunk.Row{})
+	require.NoError(t, err)
+	require.Equal(t, variable.GetSysVar(variable.VersionComment).Value, v.GetString())
+	require.Equal(t, len(v.GetString()), f.GetType().Flen)
+}
+
+func TestTiDBVersionJSON(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	f, err := newFunctionForTest(ctx, ast.TiDBVersionJSON, primitiveValsToConstants(ctx, []interface{}{})...)
+	require.NoError(t, err)
+	require.Equal(t, mysql.TypeJSON, f.GetType().Tp)
+	v, err := f.Eval(chunk.Row{})
+	require.NoError(t, err)
+	info := printer.GetTiDBInfoMap()
+	for _, key := range []string{"release_version", "git_hash", "git_branch", "build_time", "go_version"} {
+		path, err := json.ParseJSONPathExpr("$." + key)
+		require.NoError(t, err)
+		item, found := v.GetMysqlJSON().Extract([]json.PathExpression{path})
+		require.True(t, found, key)
+		str, err := item.Unquote()
+		require.NoError(t, err)
+		require.Equal(t, info[key], str, key)
+	}
+}
+
+type mockDDLOwnerChecker struct {
+	ownerID string
+	err     error
+}
+
+func (c *mockDDLOwnerChecker) IsOwner() bool { return false }
+
+func (c *mockDDLOwnerChecker) GetOwnerID(_ context.Context) (string, error) {
+	return c.ownerID, c.err
+}
+
+// mockContextWithDDLOwner is a mock context reporting the DDL owner by its checker.
+type mockContextWithDDLOwner struct {
+	*mock.Context
+	checker *mockDDLOwnerChecker
+}
+
+func (c *mockContextWithDDLOwner) DDLOwnerChecker() owner.DDLOwnerChecker {
+	return c.checker
+}
+
+func TestGetSQLDigestsRetrievalTimeout(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	sessVars := ctx.GetSessionVars()
+	tests := []struct {
+		maxExecutionTime     uint64
+		stmtMaxExecutionTime uint64
+		elapsed              time.Duration
+		ok                   bool
+		minTimeout           time.Duration
+		maxTimeout           time.Duration
+	}{
+		// no max_execution_time
+		{elapsed: time.Minute, ok: true, minTimeout: maxSQLDigestsRetrievalTimeout, maxTimeout: maxSQLDigestsRetrievalTimeout},
+		// the time left is less than the max timeout
+		{maxExecutionTime: 5000, elapsed: time.Second, ok: true, minTimeout: 3 * time.Second, maxTimeout: 4 * time.Second},
+		{maxExecutionTime: 5000, ok: true, minTimeout: 4 * time.Second, maxTimeout: 5 * time.Second},
+		// the time left is more than the max timeout
+		{maxExecutionTime: 60000, elapsed: time.Second, ok: true, minTimeout: maxSQLDigestsRetrievalTimeout, maxTimeout: maxSQLDigestsRetrievalTimeout},
+		// the time left is too little
+		{maxExecutionTime: 2000, elapsed: 1950 * time.Millisecond, ok: false},
+		{maxExecutionTime: 2000, elapsed: 3 * time.Second, ok: false},
+		// the MAX_EXECUTION_TIME hint takes precedence
+		{stmtMaxExecutionTime: 1000, elapsed: 500 * time.Millisecond, ok: true, minTimeout: 400 * time.Millisecond, maxTimeout: 500 * time.Millisecond},
+		{maxExecutionTime: 1000, stmtMaxExecutionTime: 30000, ok: true, minTimeout: maxSQLDigestsRetrievalTimeout, maxTimeout: maxSQLDigestsRetrievalTimeout},
+	}
+	for i, test := range tests {
+		sessVars.MaxExecutionTime = test.maxExecutionTime
+		sessVars.StmtCtx.HasMaxExecutionTime = test.stmtMaxExecutionTime > 0
+		sessVars.StmtCtx.MaxExecutionTime = test.stmtMaxExecutionTime
+		sessVars.StartTime = time.Now().Add(-test.elapsed)
+		timeout, ok := getSQLDigestsRetrievalTimeout(sessVars)
+		require.Equal(t, test.ok, ok, "case %d", i)
+		if ok {
+			require.GreaterOrEqual(t, timeout, test.minTimeout, "case %d", i)
+			require.LessOrEqual(t, timeout, test.maxTimeout, "case %d", i)
+		}
+	}
+}
+
+func TestTiDBDecodeSQLDigestsSkipRetrieval(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	sessVars := ctx.GetSessionVars()
+	cache := getSQLDigestTextCache(sessVars.StmtCtx)
+	cache.texts["digest1"] = "select ?"
+
+	// The statement is about to exceed max_execution_time, so the digests not cached are returned unresolved.
+	sessVars.MaxExecutionTime = 1000
+	sessVars.StartTime = time.Now().Add(-2 * time.Second)
+	f, err := newFunctionForTest(ctx, ast.TiDBDecodeSQLDigests, primitiveValsToConstants(ctx, []interface{}{`["digest1","digest2"]`})...)
+	require.NoError(t, err)
+	v, err := f.Eval(chunk.Row{})
+	require.NoError(t, err)
+	require.Equal(t, `["select ?",null]`, v.GetString())
+	warnings := sessVars.StmtCtx.GetWarnings()
+	require.Len(t, warnings, 1)
+	require.Contains(t, warnings[0].Err.Error(), "skipped as the statement is about to exceed max_execution_time")
+	require.Len(t, cache.texts, 1)
+
+	// No retrieval is needed if all the digests are cached.
+	sessVars.StmtCtx.SetWarnings(nil)
+	f, err = newFunctionForTest(ctx, ast.TiDBDecodeSQLDigests, primitiveValsToConstants(ctx, []interface{}{`["digest1"]`})...)
+	require.NoError(t, err)
+	v, err = f.Eval(chunk.Row{})
+	require.NoError(t, err)
+	require.Equal(t, `["select ?"]`, v.GetString())
+	require.Equal(t, uint16(0), sessVars.StmtCtx.WarningCount())
+}
+
+func TestTiDBDDLOwner(t *testing.T) {
+	t.Parallel()
+	ctx := &mockContextWithDDLOwner{Context: mock.NewContext(), checker: &mockDDLOwnerChecker{}}
+	sc := ctx.GetSessionVars().StmtCtx
+	f, err := funcs[ast.TiDBDDLOwner].getFunction(ctx, nil)
+	require.NoError(t, err)
+	input := chunk.NewChunkWithCapacity(nil, 3)
+	input.SetNumVirtualRows(3)
+	tests := []struct {
+		ownerID  string
+		err      error
+		isNull   bool
+		warnings int
+	}{
+		{ownerID: "6a3a7e5e-4c9e-4a4f-9ac5-1c7a1b3f5d2e"},
+		// the owner is unknown.
+		{ownerID: "", isNull: true},
+		{err: errors.New("election: no leader"), isNull: true, warnings: 1},
+	}
+	for _, test := range tests {
+		ctx.checker.ownerID, ctx.checker.err = test.ownerID, test.err
+		sc.SetWarnings(nil)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		require.NoError(t, err)
+		require.Equal(t, test.isNull, d.IsNull())
+		if !test.isNull {
+			require.Equal(t, test.ownerID, d.GetString())
+		}
+		require.Equal(t, uint16(test.warnings), sc.WarningCount())
+
+		result := chunk.NewColumn(f.getRetTp(), 3)
+		require.NoError(t, f.vecEvalString(input, result))
+		for i := 0; i < 3; i++ {
+			require.Equal(t, test.isNull, result.IsNull(i))
+			if !test.isNull {
+				require.Equal(t, test.ownerID, result.GetString(i))
+			}
+		}
+		require.Equal(t, uint16(2*test.warnings), sc.WarningCount())
+	}
+}
+
+func TestTiDBGenerateSeries(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	tests := []struct {
+		args   []interface{}
+		expect interface{}
+	}{
+		// ascending ranges
+		{[]interface{}{1, 5}, "[1, 2, 3, 4, 5]"},
+		{[]interface{}{1, 6, 2}, "[1, 3, 5]"},
+		{[]interface{}{-2, 2, 2}, "[-2, 0, 2]"},
+		{[]interface{}{3, 3}, "[3]"},
+		// descending ranges
+		{[]interface{}{5, 1, -1}, "[5, 4, 3, 2, 1]"},
+		{[]interface{}{5, 1, -3}, "[5, 2]"},
+		// empty ranges
+		{[]interface{}{5, 1}, "[]"},
+		{[]interface{}{1, 5, -1}, "[]"},
+		// no overflow at the bounds of int64
+		{[]interface{}{int64(math.MinInt64), int64(math.MaxInt64), int64(math.MaxInt64)}, "[-9223372036854775808, -1, 9223372036854775806]"},
+		{[]interface{}{int64(math.MaxInt64), int64(math.MinInt64), int64(math.MinInt64)}, "[9223372036854775807, -1]"},
+		// null arguments
+		{[]interface{}{nil, 5}, nil},
+		{[]interface{}{1, nil}, nil},
+		{[]interface{}{1, 5, nil}, nil},
+	}
+	for _, test := range tests {
+		f, err := newFunctionForTest(ctx, ast.TiDBGenerateSeries, primitiveValsToConstants(ctx, test.args)...)
+		require.NoError(t, err)
+		require.Equal(t, mysql.TypeJSON, f.GetType().Tp)
+		d, err := f.Eval(chunk.Row{})
+		require.NoError(t, err)
+		if test.expect == nil {
+			require.True(t, d.IsNull(), "%v", test.args)
+		} else {
+			require.Equal(t, test.expect, d.GetMysqlJSON().String(), "%v", test.args)
+		}
+	}
+
+	// the step can't be zero.
+	f, err := newFunctionForTest(ctx, ast.TiDBGenerateSeries, primitiveValsToConstants(ctx, []interface{}{1, 5, 0})...)
+	require.NoError(t, err)
+	_, err = f.Eval(chunk.Row{})
+	require.True(t, errIncorrectArgs.Equal(err))
+
+	// the count of values is capped.
+	f, err = newFunctionForTest(ctx, ast.TiDBGenerateSeries, primitiveValsToConstants(ctx, []interface{}{1, maxGenerateSeriesCount})...)
+	require.NoError(t, err)
+	d, err := f.Eval(chunk.Row{})
+	require.NoError(t, err)
+	require.Equal(t, maxGenerateSeriesCount, d.GetMysqlJSON().GetElemCount())
+	f, err = newFunctionForTest(ctx, ast.TiDBGenerateSeries, primitiveValsToConstants(ctx, []interface{}{0, maxGenerateSeriesCount})...)
+	require.NoError(t, err)
+	_, err = f.Eval(chunk.Row{})
+	require.True(t, errGenerateSeriesTooLarge.Equal(err))
+	require.EqualError(t, err, "[expression:1210]Incorrect arguments to tidb_generate_series: the series has more than 65536 values")
+}
+
+func TestTiDBDecodeSQLDigestsCache(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	cache := getSQLDigestTextCache(ctx.GetSessionVars().StmtCtx)
+	cache.texts["digest1"] = "select ?"
+	cache.texts["digest2"] = ""
+
+	// All the digests are cached, so no rows retrieve the statements again.
+	col := &Column{Index: 0, RetType: types.NewFieldType(mysql.TypeVarString)}
+	f, err := newFunctionForTest(ctx, ast.TiDBDecodeSQLDigests, col)
+	require.NoError(t, err)
+	chk := chunk.NewChunkWithCapacity([]*types.FieldType{col.RetType}, 4)
+	inputs := []string{`["digest1"]`, `["digest2","digest1"]`, `["digest1",null,1]`, `[]`}
+	expected := []string{`["select ?"]`, `[null,"select ?"]`, `["select ?",null,null]`, `[]`}
+	for _, input := range inputs {
+		chk.AppendString(0, input)
+	}
+	for i := range inputs {
+		v, err := f.Eval(chk.GetRow(i))
+		require.NoError(t, err)
+		require.Equal(t, expected[i], v.GetString())
+	}
+	require.Len(t, cache.texts, 2)
+	require.Same(t, cache, getSQLDigestTextCache(ctx.GetSessionVars().StmtCtx))
+}
+
+func TestTiDBDecodeSQLDigestsStrict(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	cache := getSQLDigestTextCache(ctx.GetSessionVars().StmtCtx)
+	cache.texts["digest1"] = "select ?"
+	cache.texts["digest2"] = ""
+
+	tests := []struct {
+		digests  string
+		strict   interface{}
+		expected string
+		err      string
+	}{
+		{`["digest1"]`, 1, `["select ?"]`, ""},
+		{`["digest1",null]`, 1, `["select ?",null]`, ""},
+		{`["digest1","digest2"]`, 0, `["select ?",null]`, ""},
+		{`["digest1","digest2"]`, nil, `["select ?",null]`, ""},
+		{`["digest1","digest2"]`, 1, "", `The SQL digest "digest2" can't be resolved`},
+		{`["digest1",{"a":1}]`, 1, "", `The SQL digest {"a":1} can't be resolved`},
+	}
+	for _, test := range tests {
+		f, err := newFunctionForTest(ctx, ast.TiDBDecodeSQLDigests, primitiveValsToConstants(ctx, []interface{}{test.digests, 0, test.strict})...)
+		require.NoError(t, err)
+		v, err := f.Eval(chunk.Row{})
+		if test.err != "" {
+			require.Error(t, err)
+			require.Contains(t, err.Error(), test.err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, test.expected, v.GetString())
+	}
+}
+
+func TestTiDBEncodeSQLDigest(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	encode := func(sql interface{}) types.Datum {
+		f, err := newFunctionForTest(ctx, ast.TiDBEncodeSQLDigest, primitiveValsToConstants(ctx, []interface{}{sql})...)
+		require.NoError(t, err)
+		d, err := f.Eval(chunk.Row{})
+		require.NoError(t, err)
+		return d
+	}
+
+	sql := "select * from t where a = 1 and b in ('x', 'y')"
+	_, expected := parser.NormalizeDigest(sql)
+	d := encode(sql)
+	require.Equal(t, expected.String(), d.GetString())
+	require.Len(t, d.GetString(), 64)
+	// The statements which differ only in the literals, the case and the spaces have the same digest.
+	d2 := encode("SELECT *  FROM t WHERE a = 2 AND b IN ('z', 'w', 'v')")
+	require.Equal(t, d.GetString(), d2.GetString())
+	d2 = encode("select * from t where a = 1")
+	require.NotEqual(t, d.GetString(), d2.GetString())
+	d2 = encode(nil)
+	require.True(t, d2.IsNull())
+}
+
+func TestTiDBDecodeKeyJSON(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	decoded := map[string]string{
+		"7480000000000000FF4700000000000000F8":                     `{"table_id": 71}`,
+		"7480000000000000695F698000000000000001038000000000004E20": `{"index_id": 1, "index_values": "20000", "table_id": 105}`,
+	}
+	ctx.SetValue(TiDBDecodeKeyJSONFunctionKey, func(_ sessionctx.Context, s string) (json.BinaryJSON, bool) {
+		str, ok := decoded[s]
+		if !ok {
+			return json.BinaryJSON{}, false
+		}
+		j, err := json.ParseBinaryFromString(str)
+		require.NoError(t, err)
+		return j, true
+	})
+	for key, expected := range decoded {
+		f, err := newFunctionForTest(ctx, ast.TiDBDecodeKeyJSON, primitiveValsToConstants(ctx, []interface{}{key})...)
+		require.NoError(t, err)
+		require.Equal(t, mysql.TypeJSON, f.GetType().Tp)
+		d, err := f.Eval(chunk.Row{})
+		require.NoError(t, err)
+		require.Equal(t, expected, d.GetMysqlJSON().String())
+	}
+	for _, key := range []interface{}{"invalid", nil} {
+		f, err := newFunctionForTest(ctx, ast.TiDBDecodeKeyJSON, primitiveValsToConstants(ctx, []interface{}{key})...)
+		require.NoError(t, err)
+		d, err := f.Eval(chunk.Row{})
+		require.NoError(t, err)
+		require.True(t, d.IsNull())
+	}
+}
+
+func TestTiDBDecodeKeyRangeKind(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	decoded := map[string]string{
+		"t10":      `{"table_id": 10}`,
+		"t11":      `{"table_id": 11}`,
+		"t10_r1":   `{"handle": 1, "table_id": 10}`,
+		"t10_r100": `{"handle": 100, "table_id": 10}`,
+		"t11_r1":   `{"handle": 1, "table_id": 11}`,
+		"t10_i1_a": `{"index_id": 1, "index_values": {"a": "a"}, "table_id": 10}`,
+		"t10_i1_z": `{"index_id": 1, "index_values": {"a": "z"}, "table_id": 10}`,
+		"t10_i2_a": `{"index_id": 2, "index_values": {"b": "a"}, "table_id": 10}`,
+	}
+	ctx.SetValue(TiDBDecodeKeyJSONFunctionKey, func(_ sessionctx.Context, s string) (json.BinaryJSON, bool) {
+		str, ok := decoded[s]
+		if !ok {
+			return json.BinaryJSON{}, false
+		}
+		j, err := json.ParseBinaryFromString(str)
+		require.NoError(t, err)
+		return j, true
+	})
+	tests := []struct {
+		start    string
+		end      string
+		expected string
+	}{
+		{"t10", "t11", `{"end": {"table_id": 11}, "kind": "table", "start": {"table_id": 10}}`},
+		{"t10_r1", "t10_r100", `{"end": {"handle": 100, "table_id": 10}, "kind": "record", "start": {"handle": 1, "table_id": 10}}`},
+		{"t10_r100", "t11", `{"end": {"table_id": 11}, "kind": "record", "start": {"handle": 100, "table_id": 10}}`},
+		{"t10_i1_a", "t10_i1_z", `{"end": {"index_id": 1, "index_values": {"a": "z"}, "table_id": 10}, "kind": "index", "start": {"index_id": 1, "index_values": {"a": "a"}, "table_id": 10}}`},
+		// The ranges below cover neither a whole table nor a single kind of keys.
+		{"t10_i1_a", "t10_i2_a", `{"end": {"index_id": 2, "index_values": {"b": "a"}, "table_id": 10}, "start": {"index_id": 1, "index_values": {"a": "a"}, "table_id": 10}}`},
+		{"t10_i1_a", "t10_r1", `{"end": {"handle": 1, "table_id": 10}, "start": {"index_id": 1, "index_values": {"a": "a"}, "table_id": 10}}`},
+		{"t10_r1", "t11_r1", `{"end": {"handle": 1, "table_id": 11}, "start": {"handle": 1, "table_id": 10}}`},
+		{"t10_r1", "", `{"end": null, "start": {"handle": 1, "table_id": 10}}`},
+		{"not_decoded", "t10", `{"end": {"table_id": 10}, "start": "not_decoded"}`},
+	}
+	for _, test := range tests {
+		f, err := newFunctionForTest(ctx, ast.TiDBDecodeKeyRange, primitiveValsToConstants(ctx, []interface{}{test.start, test.end})...)
+		require.NoError(t, err)
+		d, err := f.Eval(chunk.Row{})
+		require.NoError(t, err)
+		require.Equal(t, test.expected, d.GetMysqlJSON().String(), "[%s, %s)", test.start, test.end)
+	}
+}
+
+func TestTiDBDecodeLockKey(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	locked := []byte("locked_key")
+	ctx.SetValue(TiDBDecodeLockKeyFunctionKey, func(_ context.Context, _ sessionctx.Context, key []byte) (*LockInfo, error) {
+		switch string(key) {
+		case string(locked):
+			return &LockInfo{LockType: "Pessimistic", Primary: []byte("primary"), TxnStartTS: 429333290016768001, TTL: 20000}, nil
+		case "error_key":
+			return nil, errors.New("region unavailable")
+		}
+		return nil, nil
+	})
+
+	eval := func(key interface{}) (types.Datum, error) {
+		f, err := newFunctionForTest(ctx, ast.TiDBDecodeLockKey, primitiveValsToConstants(ctx, []interface{}{key})...)
+		require.NoError(t, err)
+		return f.Eval(chunk.Row{})
+	}
+	sc := ctx.GetSessionVars().StmtCtx
+	d, err := eval(hex.EncodeToString(locked))
+	require.NoError(t, err)
+	require.Equal(t, `{"lock_type": "Pessimistic", "primary": "7072696D617279", "ttl": 20000, "txn_start_ts": 429333290016768001}`, d.GetMysqlJSON().String())
+	require.Equal(t, uint16(0), sc.WarningCount())
+
+	for _, key := range []string{hex.EncodeToString([]byte("unlocked_key")), hex.EncodeToString([]byte("error_key")), "not a hex key", ""} {
+		warnCnt := sc.WarningCount()
+		d, err = eval(key)
+		require.NoError(t, err)
+		require.True(t, d.IsNull())
+		require.Equal(t, warnCnt+1, sc.WarningCount())
+	}
+	d, err = eval(nil)
+	require.NoError(t, err)
+	require.True(t, d.IsNull())
+
+	// The lock provider is stopped by the timeout.
+	ctx.GetSessionVars().MaxExecutionTime = 1
+	ctx.SetValue(TiDBDecodeLockKeyFunctionKey, func(ctx context.Context, _ sessionctx.Context, _ []byte) (*LockInfo, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	_, err = eval(hex.EncodeToString(locked))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Retrieving cancelled internally")
+}
+
+func TestTiDBMVCCInfo(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	eval := func(key interface{}) (types.Datum, error) {
+		f, err := newFunctionForTest(ctx, ast.TiDBMVCCInfo, primitiveValsToConstants(ctx, []interface{}{key})...)
+		require.NoError(t, err)
+		return f.Eval(chunk.Row{})
+	}
+	sc := ctx.GetSessionVars().StmtCtx
+
+	// No MVCC information provider is installed.
+	d, err := eval(hex.EncodeToString([]byte("key")))
+	require.NoError(t, err)
+	require.True(t, d.IsNull())
+	require.Equal(t, uint16(1), sc.WarningCount())
+
+	ctx.SetValue(TiDBMVCCInfoFunctionKey, func(_ context.Context, _ sessionctx.Context, key []byte) (*MVCCInfo, error) {
+		switch string(key) {
+		case "key":
+			return &MVCCInfo{
+				Lock:   &LockInfo{LockType: "Put", Primary: []byte("key"), TxnStartTS: 30, TTL: 3000},
+				Writes: []MVCCWrite{{Type: "Put", StartTS: 10, CommitTS: 20, ShortValue: []byte("v")}},
+				Values: []MVCCValue{{StartTS: 5, Value: []byte("value")}},
+			}, nil
+		case "error_key":
+			return nil, errors.New("region unavailable")
+		}
+		return &MVCCInfo{}, nil
+	})
+	d, err = eval(hex.EncodeToString([]byte("key")))
+	require.NoError(t, err)
+	require.Equal(t, `{"default": [{"start_ts": 5, "value": "76616C7565"}], "key": "6B6579", `+
+		`"lock": {"lock_type": "Put", "primary": "6B6579", "ttl": 3000, "txn_start_ts": 30}, `+
+		`"write": [{"commit_ts": 20, "short_value": "76", "start_ts": 10, "type": "Put"}]}`, d.GetMysqlJSON().String())
+	d, err = eval(hex.EncodeToString([]byte("empty_key")))
+	require.NoError(t, err)
+	require.Equal(t, `{"default": [], "key": "656D7074795F6B6579", "lock": null, "write": []}`, d.GetMysqlJSON().String())
+
+	for _, key := range []string{hex.EncodeToString([]byte("error_key")), "not a hex key", ""} {
+		warnCnt := sc.WarningCount()
+		d, err = eval(key)
+		require.NoError(t, err)
+		require.True(t, d.IsNull())
+		require.Equal(t, warnCnt+1, sc.WarningCount())
+	}
+	d, err = eval(nil)
+	require.NoError(t, err)
+	require.True(t, d.IsNull())
+}
+
+func TestTiDBCurrentTxnID(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	sessVars := ctx.GetSessionVars()
+	f, err := funcs[ast.TiDBCurrentTxnID].getFunction(ctx, nil)
+	require.NoError(t, err)
+	require.True(t, mysql.HasUnsignedFlag(f.getRetTp().Flag))
+
+	// Not in a transaction.
+	sessVars.TxnCtx.StartTS = 1
+	d, err := evalBuiltinFunc(f, chunk.Row{})
+	require.NoError(t, err)
+	require.True(t, d.IsNull())
+
+	sessVars.SetStatusFlag(mysql.ServerStatusInTrans, true)
+	sessVars.TxnCtx.StartTS = math.MaxUint64
+	d, err = evalBuiltinFunc(f, chunk.Row{})
+	require.NoError(t, err)
+	require.Equal(t, uint64(math.MaxUint64), d.GetUint64())
+	require.Equal(t, f.PbCode(), f.Clone().PbCode())
+}
+
+func TestTiDBKeyspaceID(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	eval := func() types.Datum {
+		f, err := newFunctionForTest(ctx, ast.TiDBKeyspaceID)
+		require.NoError(t, err)
+		d, err := f.Eval(chunk.Row{})
+		require.NoError(t, err)
+		return d
+	}
+
+	// No keyspace provider is installed.
+	d := eval()
+	require.True(t, d.IsNull())
+
+	enabled := false
+	ctx.SetValue(TiDBKeyspaceIDFunctionKey, func(_ sessionctx.Context) (uint32, bool) {
+		return 4242, enabled
+	})
+	d = eval()
+	require.True(t, d.IsNull())
+	enabled = true
+	d = eval()
+	require.Equal(t, types.KindUint64, d.Kind())
+	require.Equal(t, uint64(4242), d.GetUint64())
+}
+
 func TestLastInsertID(t *testing.T) {
 	t.Parallel()
 	ctx := createContext(t)
@@ -269,7 +1132,7 @@ func TestLastInsertID(t *testing.T) {
 		{0, 1, 1, false, false},
 		{0, 1.1, 1, false, false},
 		{0, maxUint64, maxUint64, false, false},
-		{0, -1, math.MaxUint64, false, false},
+		{0, -1, 0, false, false},
 		{1, nil, 1, false, false},
 		{math.MaxUint64, nil, math.MaxUint64, false, false},
 	}
@@ -312,6 +1175,41 @@ func TestLastInsertID(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestLastInsertIDWithID(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	sc := ctx.GetSessionVars().StmtCtx
+	tests := []struct {
+		arg      interface{}
+		expected uint64
+		warning  string
+	}{
+		{-1, 0, "[types:1690]BIGINT UNSIGNED value is out of range in 'last_insert_id(-1)'"},
+		{int64(math.MinInt64), 0, "[types:1690]BIGINT UNSIGNED value is out of range in 'last_insert_id(-9223372036854775808)'"},
+		{-1.6, 0, "[types:1690]BIGINT UNSIGNED value is out of range in 'last_insert_id(-2)'"},
+		{0, 0, ""},
+		{int64(math.MaxInt64), math.MaxInt64, ""},
+		{uint64(math.MaxUint64), math.MaxUint64, ""},
+	}
+	for _, test := range tests {
+		sc.SetWarnings(nil)
+		sc.LastInsertID = 1
+		f, err := newFunctionForTest(ctx, ast.LastInsertId, primitiveValsToConstants(ctx, []interface{}{test.arg})...)
+		require.NoError(t, err)
+		d, err := f.Eval(chunk.Row{})
+		require.NoError(t, err)
+		require.Equal(t, test.expected, d.GetUint64())
+		require.Equal(t, test.expected, sc.LastInsertID)
+		warnings := sc.GetWarnings()
+		if test.warning == "" {
+			require.Len(t, warnings, 0)
+			continue
+		}
+		require.Len(t, warnings, 1)
+		require.EqualError(t, warnings[0].Err, test.warning)
+	}
+}
+
 func TestFormatBytes(t *testing.T) {
 	t.Parallel()
 	ctx := createContext(t)
@@ -340,6 +1238,84 @@ func TestFormatBytes(t *testing.T) {
 		require.NoError(t, err)
 		trequire.DatumEqual(t, tt["Ret"][0], v)
 	}
+
+	// The second argument controls the fractional digits, which is clamped to [0, 6].
+	tblWithPrecision := []struct {
+		Args []interface{}
+		Ret  interface{}
+	}{
+		{[]interface{}{nil, 1}, nil},
+		{[]interface{}{float64(1536), nil}, nil},
+		{[]interface{}{float64(0), 3}, "0 bytes"},
+		{[]interface{}{float64(-512), 3}, "-512 bytes"},
+		{[]interface{}{float64(1536), 1}, "1.5 KiB"},
+		{[]interface{}{float64(1536), 3}, "1.500 KiB"},
+		{[]interface{}{float64(1536), 0}, "2 KiB"},
+		{[]interface{}{float64(1536), -1}, "2 KiB"},
+		{[]interface{}{float64(75295729), 6}, "71.807603 MiB"},
+		{[]interface{}{float64(75295729), 10}, "71.807603 MiB"},
+		{[]interface{}{float64(-5287242702), 1}, "-4.9 GiB"},
+		{[]interface{}{float64(287952852482075252752429875), 4}, "2.4976e+08 EiB"},
+	}
+	for _, tt := range tblWithPrecision {
+		fc := funcs[ast.FormatBytes]
+		f, err := fc.getFunction(ctx, datumsToConstants(types.MakeDatums(tt.Args...)))
+		require.NoError(t, err)
+		v, err := evalBuiltinFunc(f, chunk.Row{})
+		require.NoError(t, err)
+		trequire.DatumEqual(t, types.NewDatum(tt.Ret), v)
+	}
+}
+
+func TestParseBytes(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	tbl := []struct {
+		Arg interface{}
+		Ret interface{}
+	}{
+		{nil, nil},
+		{"0 bytes", int64(0)},
+		{"512 bytes", int64(512)},
+		{"512", int64(512)},
+		{"1.50 KiB", int64(1536)},
+		{" 1.50kib ", int64(1536)},
+		{"3 MiB", int64(3145728)},
+		{"3  mib", int64(3145728)},
+		{"4.92 GiB", int64(5282809774)},
+		{"-16.00 EiB", nil},
+		{"-2.00 KiB", int64(-2048)},
+		{"7.99 EiB", int64(9211842821808707584)},
+		{"2.50e+08 EiB", nil},
+		{"1.5 KB", nil},
+		{"KiB", nil},
+		{"abc bytes", nil},
+		{"", nil},
+	}
+	Dtbl := tblToDtbl(tbl)
+
+	for _, tt := range Dtbl {
+		fc := funcs[ast.ParseBytes]
+		f, err := fc.getFunction(ctx, datumsToConstants(tt["Arg"]))
+		require.NoError(t, err)
+		v, err := evalBuiltinFunc(f, chunk.Row{})
+		require.NoError(t, err)
+		trequire.DatumEqual(t, tt["Ret"][0], v)
+	}
+	// Only the unparseable strings append warnings.
+	require.Equal(t, uint16(6), ctx.GetSessionVars().StmtCtx.WarningCount())
+
+	// PARSE_BYTES() is the inverse of FORMAT_BYTES(), the byte counts which don't lose precision
+	// when being formatted are kept after the round trip.
+	for _, bytes := range []int64{0, 1, 1023, 1024, 1536, 3 << 20, 5 << 30, -2048, 1 << 50, 3 << 60} {
+		formatBytes, err := newFunctionForTest(ctx, ast.FormatBytes, datumsToConstants(types.MakeDatums(bytes))...)
+		require.NoError(t, err)
+		parseBytes, err := newFunctionForTest(ctx, ast.ParseBytes, formatBytes)
+		require.NoError(t, err)
+		v, err := parseBytes.Eval(chunk.Row{})
+		require.NoError(t, err)
+		require.Equal(t, bytes, v.GetInt64())
+	}
 }
 
 func TestFormatNanoTime(t *testing.T) {
@@ -371,3 +1347,88 @@ func TestFormatNanoTime(t *testing.T) {
 		trequire.DatumEqual(t, tt["Ret"][0], v)
 	}
 }
+
+func TestParseNanoTime(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	tbl := []struct {
+		Arg interface{}
+		Ret interface{}
+	}{
+		{nil, nil},
+		{"0 ns", int64(0)},
+		{"500 ns", int64(500)},
+		{"500.00 ns", int64(500)},
+		{"500", int64(500)},
+		{"2.00 us", int64(2000)},
+		{"1.50 ms", int64(1500000)},
+		{" 1.50MS ", int64(1500000)},
+		{"3.00 s", int64(3000000000)},
+		{"3  s", int64(3000000000)},
+		{"14.98 min", int64(898800000000)},
+		{"1.62 h", int64(5832000000000)},
+		{"-10.00 s", int64(-10000000000)},
+		{"5.59e+10 d", nil},
+		{"1.5 sec", nil},
+		{"ms", nil},
+		{"abc ns", nil},
+		{"", nil},
+	}
+	Dtbl := tblToDtbl(tbl)
+
+	for _, tt := range Dtbl {
+		fc := funcs[ast.ParseNanoTime]
+		f, err := fc.getFunction(ctx, datumsToConstants(tt["Arg"]))
+		require.NoError(t, err)
+		v, err := evalBuiltinFunc(f, chunk.Row{})
+		require.NoError(t, err)
+		trequire.DatumEqual(t, tt["Ret"][0], v)
+	}
+	// Only the unparseable strings append warnings.
+	require.Equal(t, uint16(5), ctx.GetSessionVars().StmtCtx.WarningCount())
+
+	// PARSE_NANO_TIME() is the inverse of FORMAT_NANO_TIME(), the durations which don't lose precision
+	// when being formatted are kept after the round trip.
+	for _, nanoTime := range []int64{0, 1, 999, 1500, 2000, 1500000, 3000000000, -10000000000, 5400000000000, 172800000000000} {
+		formatNanoTime, err := newFunctionForTest(ctx, ast.FormatNanoTime, datumsToConstants(types.MakeDatums(nanoTime))...)
+		require.NoError(t, err)
+		parseNanoTime, err := newFunctionForTest(ctx, ast.ParseNanoTime, formatNanoTime)
+		require.NoError(t, err)
+		v, err := parseNanoTime.Eval(chunk.Row{})
+		require.NoError(t, err)
+		require.Equal(t, nanoTime, v.GetInt64())
+	}
+}
+
+func TestGetSchemaAndSequence(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	ctx.GetSessionVars().CurrentDB = "cur_db"
+	tests := []struct {
+		name string
+		db   string
+		seq  string
+	}{
+		{"seq", "cur_db", "seq"},
+		{"db.seq", "db", "seq"},
+		{"  db . seq  ", "db", "seq"},
+		{"`seq`", "cur_db", "seq"},
+		{"`my.seq`", "cur_db", "my.seq"},
+		{"db.`my.seq`", "db", "my.seq"},
+		{" `my.db` . `my.seq` ", "my.db", "my.seq"},
+		{"`my``seq`", "cur_db", "my`seq"},
+		{"` seq `", "cur_db", " seq "},
+	}
+	for _, test := range tests {
+		db, seq, err := getSchemaAndSequence(ctx, test.name)
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.db, db, test.name)
+		require.Equal(t, test.seq, seq, test.name)
+	}
+
+	for _, name := range []string{"", "  ", "db.", ".seq", "a.b.c", "`a`.`b`.`c`", "`seq", "`db`seq", "db.s`eq", "``"} {
+		_, _, err := getSchemaAndSequence(ctx, name)
+		require.Error(t, err, name)
+		require.True(t, errWrongTableName.Equal(err), name)
+	}
+}
@@ -15,10 +15,8 @@
 package expression
 
 import (
-	"sort"
-	"strings"
-
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/meta/autoid"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
@@ -109,12 +107,15 @@ func (b *builtinCurrentUserSig) vecEvalString(input *chunk.Chunk, result *chunk.
 	n := input.NumRows()
 
 	data := b.ctx.GetSessionVars()
-	result.ReserveString(n)
 	if data == nil || data.User == nil {
 		return errors.Errorf("Missing session variable when eval builtin")
 	}
+
+	// The user is constant in the session, so it's only formatted once for the whole column.
+	res := data.User.String()
+	result.ReserveString(n)
 	for i := 0; i < n; i++ {
-		result.AppendString(data.User.String())
+		result.AppendString(res)
 	}
 	return nil
 }
@@ -134,21 +135,29 @@ func (b *builtinCurrentRoleSig) vecEvalString(input *chunk.Chunk, result *chunk.
 	}
 
 	result.ReserveString(n)
-	if len(data.ActiveRoles) == 0 {
-		for i := 0; i < n; i++ {
-			result.AppendString("NONE")
-		}
-		return nil
+	res := joinActiveRoles(data.ActiveRoles)
+	for i := 0; i < n; i++ {
+		result.AppendString(res)
 	}
+	return nil
+}
+
+func (b *builtinCurrentRoleJSONSig) vectorized() bool {
+	return true
+}
 
-	sortedRes := make([]string, 0, 10)
-	for _, r := range data.ActiveRoles {
-		sortedRes = append(sortedRes, r.String())
+func (b *builtinCurrentRoleJSONSig) vecEvalJSON(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+
+	data := b.ctx.GetSessionVars()
+	if data == nil || data.ActiveRoles == nil {
+		return errors.Errorf("Missing session variable when eval builtin")
 	}
-	sort.Strings(sortedRes)
-	res := strings.Join(sortedRes, ",")
+
+	result.ReserveJSON(n)
+	res := activeRolesToJSON(data.ActiveRoles)
 	for i := 0; i < n; i++ {
-		result.AppendString(res)
+		result.AppendJSON(res)
 	}
 	return nil
 }
@@ -166,9 +175,28 @@ func (b *builtinUserSig) vecEvalString(input *chunk.Chunk, result *chunk.Column)
 		return errors.Errorf("Missing session variable when eval builtin")
 	}
 
+	res := data.User.LoginString()
 	result.ReserveString(n)
 	for i := 0; i < n; i++ {
-		result.AppendString(data.User.LoginString())
+		result.AppendString(res)
+	}
+	return nil
+}
+
+func (b *builtinTiDBDDLOwnerSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinTiDBDDLOwnerSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	ownerID, isNull := getDDLOwnerID(b.ctx)
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if isNull {
+			result.AppendNull()
+		} else {
+			result.AppendString(ownerID)
+		}
 	}
 	return nil
 }
@@ -225,47 +253,69 @@ func (b *builtinBenchmarkSig) vecEvalInt(input *chunk.Chunk, result *chunk.Colum
 		return err
 	}
 	defer b.bufAllocator.put(buf)
+	checkBenchmarkLoopCount(ctx, loopCount)
 
 	var k int64
 	switch evalType {
 	case types.ETInt:
 		for ; k < loopCount; k++ {
+			if err = checkBenchmarkKilled(ctx, k); err != nil {
+				return err
+			}
 			if err = arg.VecEvalInt(ctx, input, buf); err != nil {
 				return err
 			}
 		}
 	case types.ETReal:
 		for ; k < loopCount; k++ {
+			if err = checkBenchmarkKilled(ctx, k); err != nil {
+				return err
+			}
 			if err = arg.VecEvalReal(ctx, input, buf); err != nil {
 				return err
 			}
 		}
 	case types.ETDecimal:
 		for ; k < loopCount; k++ {
+			if err = checkBenchmarkKilled(ctx, k); err != nil {
+				return err
+			}
 			if err = arg.VecEvalDecimal(ctx, input, buf); err != nil {
 				return err
 			}
 		}
 	case types.ETString:
 		for ; k < loopCount; k++ {
+			if err = checkBenchmarkKilled(ctx, k); err != nil {
+				return err
+			}
 			if err = arg.VecEvalString(ctx, input, buf); err != nil {
 				return err
 			}
 		}
 	case types.ETDatetime, types.ETTimestamp:
 		for ; k < loopCount; k++ {
+			if err = checkBenchmarkKilled(ctx, k); err != nil {
+				return err
+			}
 			if err = arg.VecEvalTime(ctx, input, buf); err != nil {
 				return err
 			}
 		}
 	case types.ETDuration:
 		for ; k < loopCount; k++ {
+			if err = checkBenchmarkKilled(ctx, k); err != nil {
+				return err
+			}
 			if err = arg.VecEvalDuration(ctx, input, buf); err != nil {
 				return err
 			}
 		}
 	case types.ETJson:
 		for ; k < loopCount; k++ {
+			if err = checkBenchmarkKilled(ctx, k); err != nil {
+				return err
+			}
 			if err = arg.VecEvalJSON(ctx, input, buf); err != nil {
 				return err
 			}
@@ -305,6 +355,11 @@ func (b *builtinLastInsertIDWithIDSig) vecEvalInt(input *chunk.Chunk, result *ch
 		return err
 	}
 	i64s := result.Int64s()
+	for i := range i64s {
+		if !result.IsNull(i) {
+			i64s[i] = b.clampLastInsertID(i64s[i])
+		}
+	}
 	for i := len(i64s) - 1; i >= 0; i-- {
 		if !result.IsNull(i) {
 			b.ctx.GetSessionVars().SetLastInsertID(uint64(i64s[i]))
@@ -355,3 +410,83 @@ func (b *builtinTiDBDecodeKeySig) vecEvalString(input *chunk.Chunk, result *chun
 	}
 	return nil
 }
+
+func (b *builtinTiDBDecodeKeyJSONSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinTiDBDecodeKeyJSONSig) vecEvalJSON(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	result.ReserveJSON(n)
+	decode := b.getDecoder()
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		j, ok := decode(b.ctx, buf.GetString(i))
+		if !ok {
+			result.AppendNull()
+			continue
+		}
+		result.AppendJSON(j)
+	}
+	return nil
+}
+
+func (b *builtinTiDBDecodeAutoRandomSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinTiDBDecodeAutoRandomSig) vecEvalJSON(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	tableNames, err := b.bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(tableNames)
+	if err := b.args[0].VecEvalString(b.ctx, input, tableNames); err != nil {
+		return err
+	}
+	values, err := b.bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(values)
+	if err := b.args[1].VecEvalInt(b.ctx, input, values); err != nil {
+		return err
+	}
+
+	// The table name is usually the same for all the rows, so the layout is only resolved once for each table.
+	layouts := make(map[string]*autoid.ShardIDLayout, 1)
+	i64s := values.Int64s()
+	result.ReserveJSON(n)
+	for i := 0; i < n; i++ {
+		if tableNames.IsNull(i) || values.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		tableName := tableNames.GetString(i)
+		layout, ok := layouts[tableName]
+		if !ok {
+			if layout, err = b.getAutoRandomLayout(tableName); err != nil {
+				return err
+			}
+			layouts[tableName] = layout
+		}
+		if layout == nil {
+			result.AppendNull()
+			continue
+		}
+		result.AppendJSON(decodeAutoRandom(layout, i64s[i]))
+	}
+	return nil
+}
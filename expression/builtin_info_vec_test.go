@@ -21,9 +21,13 @@ import (
 
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/mock"
+	"github.com/stretchr/testify/require"
 )
 
 type tidbKeyGener struct {
@@ -79,9 +83,15 @@ var vecBuiltinInfoCases = map[string][]vecExprBenchCase{
 	ast.CurrentRole: {
 		{retEvalType: types.ETString, childrenTypes: []types.EvalType{}},
 	},
+	ast.CurrentRoleJSON: {
+		{retEvalType: types.ETJson, childrenTypes: []types.EvalType{}},
+	},
 	ast.TiDBIsDDLOwner: {
 		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{}},
 	},
+	ast.TiDBDDLOwner: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{}},
+	},
 	ast.ConnectionID: {
 		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{}},
 	},
@@ -113,6 +123,42 @@ func TestVectorizedBuiltinInfoFunc(t *testing.T) {
 	testVectorizedBuiltinFunc(t, vecBuiltinInfoCases)
 }
 
+func TestVectorizedCurrentUserAndUser(t *testing.T) {
+	ctx := mock.NewContext()
+	sessionVars := ctx.GetSessionVars()
+	// The current user differs from the login user, so CURRENT_USER() and USER() return different strings.
+	sessionVars.User = &auth.UserIdentity{Username: "root", Hostname: "localhost", AuthUsername: "root", AuthHostname: "%"}
+
+	input := chunk.NewChunkWithCapacity(nil, 1024)
+	input.SetNumVirtualRows(1024)
+	for _, funcName := range []string{ast.CurrentUser, ast.User} {
+		f, err := funcs[funcName].getFunction(ctx, nil)
+		require.NoError(t, err)
+		require.True(t, f.vectorized())
+		expected, isNull, err := f.evalString(chunk.Row{})
+		require.NoError(t, err)
+		require.False(t, isNull)
+
+		result := chunk.NewColumn(f.getRetTp(), 1024)
+		require.NoError(t, f.vecEvalString(input, result))
+		for i := 0; i < 1024; i++ {
+			require.False(t, result.IsNull(i))
+			require.Equal(t, expected, result.GetString(i), funcName)
+		}
+	}
+
+	// Both ways of the evaluation report the missing user.
+	sessionVars.User = nil
+	for _, funcName := range []string{ast.CurrentUser, ast.User} {
+		f, err := funcs[funcName].getFunction(ctx, nil)
+		require.NoError(t, err)
+		_, isNull, err := f.evalString(chunk.Row{})
+		require.Error(t, err)
+		require.True(t, isNull)
+		require.Error(t, f.vecEvalString(input, chunk.NewColumn(f.getRetTp(), 1024)))
+	}
+}
+
 func BenchmarkVectorizedBuiltinInfoFunc(b *testing.B) {
 	benchmarkVectorizedBuiltinFunc(b, vecBuiltinInfoCases)
 }
@@ -57,6 +57,7 @@ var (
 	_ functionClass = &jsonDepthFunctionClass{}
 	_ functionClass = &jsonKeysFunctionClass{}
 	_ functionClass = &jsonLengthFunctionClass{}
+	_ functionClass = &tidbJSONValidSchemaFunctionClass{}
 
 	_ builtinFunc = &builtinJSONTypeSig{}
 	_ builtinFunc = &builtinJSONQuoteSig{}
@@ -81,6 +82,7 @@ var (
 	_ builtinFunc = &builtinJSONValidJSONSig{}
 	_ builtinFunc = &builtinJSONValidStringSig{}
 	_ builtinFunc = &builtinJSONValidOthersSig{}
+	_ builtinFunc = &builtinTiDBJSONValidSchemaSig{}
 )
 
 type jsonTypeFunctionClass struct {
@@ -870,6 +872,55 @@ func (b *builtinJSONValidOthersSig) evalInt(row chunk.Row) (res int64, isNull bo
 	return 0, false, nil
 }
 
+type tidbJSONValidSchemaFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbJSONValidSchemaFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, types.ETJson, types.ETJson)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = 1
+	sig := &builtinTiDBJSONValidSchemaSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBJSONValidSchemaSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBJSONValidSchemaSig) Clone() builtinFunc {
+	newSig := &builtinTiDBJSONValidSchemaSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinTiDBJSONValidSchemaSig.
+// It returns 1 if the document matches the schema, and returns NULL with a warning if the schema is malformed.
+func (b *builtinTiDBJSONValidSchemaSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	doc, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	schema, isNull, err := b.args[1].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	valid, err := json.ValidateBinarySchema(schema, doc)
+	if err != nil {
+		b.ctx.GetSessionVars().StmtCtx.AppendWarning(err)
+		return 0, true, nil
+	}
+	if valid {
+		res = 1
+	}
+	return res, false, nil
+}
+
 type jsonArrayAppendFunctionClass struct {
 	baseFunctionClass
 }
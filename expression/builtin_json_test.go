@@ -1005,6 +1005,45 @@ func TestJSONValid(t *testing.T) {
 	}
 }
 
+func TestTiDBJSONValidSchema(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	fc := funcs[ast.TiDBJSONValidSchema]
+	schema := `{"type": "object", "required": ["a"], "properties": {"a": {"type": "integer", "enum": [1, 2]}}}`
+	tbl := []struct {
+		Input    []interface{}
+		Expected interface{}
+		Warning  bool
+	}{
+		{[]interface{}{`{"a": 1}`, schema}, 1, false},
+		{[]interface{}{`{"a": 2, "b": "x"}`, schema}, 1, false},
+		{[]interface{}{`{"a": 3}`, schema}, 0, false},
+		{[]interface{}{`{"b": 1}`, schema}, 0, false},
+		{[]interface{}{`[1]`, schema}, 0, false},
+		{[]interface{}{nil, schema}, nil, false},
+		{[]interface{}{`{"a": 1}`, nil}, nil, false},
+		{[]interface{}{`{"a": 1}`, `{"type": "unknown"}`}, nil, true},
+		{[]interface{}{`{"a": 1}`, `[]`}, nil, true},
+	}
+	for _, tt := range tbl {
+		ctx.GetSessionVars().StmtCtx.SetWarnings(nil)
+		f, err := fc.getFunction(ctx, datumsToConstants(types.MakeDatums(tt.Input...)))
+		require.NoError(t, err)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		require.NoError(t, err)
+		if tt.Expected == nil {
+			require.True(t, d.IsNull())
+		} else {
+			require.Equal(t, int64(tt.Expected.(int)), d.GetInt64())
+		}
+		if tt.Warning {
+			require.Equal(t, uint16(1), ctx.GetSessionVars().StmtCtx.WarningCount())
+		} else {
+			require.Equal(t, uint16(0), ctx.GetSessionVars().StmtCtx.WarningCount())
+		}
+	}
+}
+
 func TestJSONStorageSize(t *testing.T) {
 	t.Parallel()
 	ctx := createContext(t)
@@ -24,12 +24,14 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/cznic/mathutil"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/meta/autoid"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
@@ -7090,6 +7092,160 @@ func (b *builtinTidbParseTsoSig) evalTime(row chunk.Row) (types.Time, bool, erro
 	return result, false, nil
 }
 
+// tidbParseTsoLogicalFunctionClass extracts logical counter from a tso
+type tidbParseTsoLogicalFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbParseTsoLogicalFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, types.ETInt)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinTidbParseTsoLogicalSig{bf}
+	return sig, nil
+}
+
+type builtinTidbParseTsoLogicalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTidbParseTsoLogicalSig) Clone() builtinFunc {
+	newSig := &builtinTidbParseTsoLogicalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinTidbParseTsoLogicalSig.
+func (b *builtinTidbParseTsoLogicalSig) evalInt(row chunk.Row) (int64, bool, error) {
+	arg, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil || arg <= 0 {
+		return 0, true, err
+	}
+	return oracle.ExtractLogical(uint64(arg)), false, nil
+}
+
+// tidbDecodeTimeFromRowIDFunctionClass extracts the physical time from a rowid whose
+// bits after the shard bits are a tso.
+type tidbDecodeTimeFromRowIDFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbDecodeTimeFromRowIDFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETDatetime, types.ETInt, types.ETInt)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Tp, bf.tp.Flen, bf.tp.Decimal = mysql.TypeDatetime, mysql.MaxDatetimeWidthWithFsp, int(types.MaxFsp)
+	sig := &builtinTiDBDecodeTimeFromRowIDSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBDecodeTimeFromRowIDSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBDecodeTimeFromRowIDSig) Clone() builtinFunc {
+	newSig := &builtinTiDBDecodeTimeFromRowIDSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals a builtinTiDBDecodeTimeFromRowIDSig.
+// The rowid is laid out as [sign bit][shard bits][tso], the same as an AUTO_RANDOM value.
+func (b *builtinTiDBDecodeTimeFromRowIDSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	rowID, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return types.ZeroTime, true, err
+	}
+	shardBits, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return types.ZeroTime, true, err
+	}
+	sc := b.ctx.GetSessionVars().StmtCtx
+	if shardBits < 0 || shardBits > autoid.MaxAutoRandomBits {
+		sc.AppendWarning(errIncorrectArgs.GenWithStack("Incorrect arguments to %s: shard_bits should be in [0, %d], but got %d", ast.TiDBDecodeTimeFromRowID, autoid.MaxAutoRandomBits, shardBits))
+		return types.ZeroTime, true, nil
+	}
+	ts := rowID & (1<<(autoid.RowIDBitLength-1-shardBits) - 1)
+	if rowID <= 0 || ts == 0 {
+		sc.AppendWarning(errIncorrectArgs.GenWithStack("Incorrect arguments to %s: invalid rowid %d", ast.TiDBDecodeTimeFromRowID, rowID))
+		return types.ZeroTime, true, nil
+	}
+	t := oracle.GetTimeFromTS(uint64(ts))
+	result := types.NewTime(types.FromGoTime(t), mysql.TypeDatetime, types.MaxFsp)
+	err = result.ConvertTimeZone(time.Local, b.ctx.GetSessionVars().Location())
+	if err != nil {
+		return types.ZeroTime, true, err
+	}
+	return result, false, nil
+}
+
+// tidbDecodeTimestampColumnFunctionClass interprets the raw value of a timestamp column stored by TiDB.
+type tidbDecodeTimestampColumnFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbDecodeTimestampColumnFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETDatetime, types.ETInt)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Tp, bf.tp.Flen, bf.tp.Decimal = mysql.TypeDatetime, mysql.MaxDatetimeWidthWithFsp, int(types.MaxFsp)
+	sig := &builtinTiDBDecodeTimestampColumnSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBDecodeTimestampColumnSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBDecodeTimestampColumnSig) Clone() builtinFunc {
+	newSig := &builtinTiDBDecodeTimestampColumnSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals a builtinTiDBDecodeTimestampColumnSig.
+// The raw value is the packed UTC time stored for a timestamp column, which is converted into the session time zone.
+func (b *builtinTiDBDecodeTimestampColumnSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	raw, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return types.ZeroTime, true, err
+	}
+	return decodeTimestampColumn(b.ctx, raw)
+}
+
+// decodeTimestampColumn unpacks the raw value of a timestamp column into the session time zone. It returns NULL
+// with a warning if raw is the zero timestamp, or isn't a valid timestamp in [MinTimestamp, MaxTimestamp].
+func decodeTimestampColumn(ctx sessionctx.Context, raw int64) (types.Time, bool, error) {
+	sc := ctx.GetSessionVars().StmtCtx
+	result := types.NewTime(types.ZeroCoreTime, mysql.TypeDatetime, types.MaxFsp)
+	// The lowest 24 bits are the microseconds, see Time.ToPackedUint.
+	if raw > 0 && raw%(1<<24) <= 999999 {
+		if err := result.FromPackedUint(uint64(raw)); err != nil {
+			return types.ZeroTime, true, err
+		}
+	}
+	if result.IsZero() || result.Check(sc) != nil || result.Compare(types.MinTimestamp) < 0 || result.Compare(types.MaxTimestamp) > 0 {
+		sc.AppendWarning(types.ErrWrongValue.GenWithStackByArgs(types.TimestampStr, strconv.FormatInt(raw, 10)))
+		return types.ZeroTime, true, nil
+	}
+	if err := result.ConvertTimeZone(time.UTC, ctx.GetSessionVars().Location()); err != nil {
+		return types.ZeroTime, true, err
+	}
+	return result, false, nil
+}
+
 func handleInvalidZeroTime(ctx sessionctx.Context, t types.Time) (bool, error) {
 	// MySQL compatibility, #11203
 	// 0 | 0.0 should be converted to null without warnings
@@ -7172,6 +7328,15 @@ func GetMinSafeTime(sessionCtx sessionctx.Context) time.Time {
 }
 
 func getMinSafeTime(sessionCtx sessionctx.Context, timeZone *time.Location) time.Time {
+	minSafeTS := getMinSafeTS(sessionCtx)
+	// Try to get from the stmt cache to make sure this function is deterministic.
+	stmtCtx := sessionCtx.GetSessionVars().StmtCtx
+	minSafeTS = stmtCtx.GetOrStoreStmtCache(stmtctx.StmtSafeTSCacheKey, minSafeTS).(uint64)
+	return oracle.GetTimeFromTS(minSafeTS).In(timeZone)
+}
+
+// getMinSafeTS reads the latest minimal SafeTS of the store, which isn't cached in the statement.
+func getMinSafeTS(sessionCtx sessionctx.Context) uint64 {
 	var minSafeTS uint64
 	txnScope := config.GetTxnScopeFromConfig()
 	if store := sessionCtx.GetStore(); store != nil {
@@ -7182,10 +7347,88 @@ func getMinSafeTime(sessionCtx sessionctx.Context, timeZone *time.Location) time
 		injectTS := val.(int)
 		minSafeTS = uint64(injectTS)
 	})
-	// Try to get from the stmt cache to make sure this function is deterministic.
-	stmtCtx := sessionCtx.GetSessionVars().StmtCtx
-	minSafeTS = stmtCtx.GetOrStoreStmtCache(stmtctx.StmtSafeTSCacheKey, minSafeTS).(uint64)
-	return oracle.GetTimeFromTS(minSafeTS).In(timeZone)
+	return minSafeTS
+}
+
+// waitTxnTSPollInterval is the interval of polling the SafeTS in TIDB_WAIT_TXN_TS.
+const waitTxnTSPollInterval = 10 * time.Millisecond
+
+// tidbWaitTxnTSFunctionClass blocks until the latest SafeTS reaches the target TS, so that the target TS can
+// be used by the Stale Read, or until the timeout elapses.
+type tidbWaitTxnTSFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbWaitTxnTSFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, types.ETInt, types.ETInt)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = 1
+	sig := &builtinTiDBWaitTxnTSSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBWaitTxnTSSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBWaitTxnTSSig) Clone() builtinFunc {
+	newSig := &builtinTiDBWaitTxnTSSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinTiDBWaitTxnTSSig.
+// It returns 1 if the SafeTS reaches the target TS within the timeout in milliseconds, otherwise 0.
+func (b *builtinTiDBWaitTxnTSSig) evalInt(row chunk.Row) (int64, bool, error) {
+	targetTS, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	timeout, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	if timeout < 0 {
+		timeout = 0
+	}
+	reached, err := waitMinSafeTS(b.ctx, uint64(targetTS), time.Duration(timeout)*time.Millisecond)
+	if err != nil {
+		return 0, true, err
+	}
+	if reached {
+		return 1, false, nil
+	}
+	return 0, false, nil
+}
+
+// waitMinSafeTS polls the SafeTS until it reaches targetTS or the timeout elapses. It returns ErrQueryInterrupted
+// if the query is killed while waiting.
+func waitMinSafeTS(sessionCtx sessionctx.Context, targetTS uint64, timeout time.Duration) (bool, error) {
+	if getMinSafeTS(sessionCtx) >= targetTS {
+		return true, nil
+	}
+	ticker := time.NewTicker(waitTxnTSPollInterval)
+	defer ticker.Stop()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if atomic.LoadUint32(&sessionCtx.GetSessionVars().Killed) == 1 {
+				return false, ErrQueryInterrupted
+			}
+			if getMinSafeTS(sessionCtx) >= targetTS {
+				return true, nil
+			}
+		case <-timer.C:
+			return getMinSafeTS(sessionCtx) >= targetTS, nil
+		}
+	}
 }
 
 // CalAppropriateTime directly calls calAppropriateTime
@@ -18,11 +18,13 @@ import (
 	"fmt"
 	"math"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/mysql"
@@ -3005,6 +3007,156 @@ func TestTidbParseTso(t *testing.T) {
 	}
 }
 
+func TestTidbParseTsoLogical(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	tests := []struct {
+		param  interface{}
+		expect int64
+	}{
+		{404411537129996288, 0},
+		{404411537130008633, 12345},
+		{"404411537130008633", 12345},
+		{404411537130258431, 262143},
+		{1, 1},
+	}
+
+	fc := funcs[ast.TiDBParseTsoLogical]
+	for _, test := range tests {
+		dat := []types.Datum{types.NewDatum(test.param)}
+		f, err := fc.getFunction(ctx, datumsToConstants(dat))
+		require.NoError(t, err)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		require.NoError(t, err)
+		require.Equal(t, test.expect, d.GetInt64())
+	}
+
+	testsNull := []interface{}{
+		0,
+		-1,
+		"-1",
+		nil}
+
+	for _, i := range testsNull {
+		dat := []types.Datum{types.NewDatum(i)}
+		f, err := fc.getFunction(ctx, datumsToConstants(dat))
+		require.NoError(t, err)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		require.NoError(t, err)
+		require.True(t, d.IsNull())
+	}
+}
+
+func TestTiDBDecodeTimeFromRowID(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	ctx.GetSessionVars().TimeZone = time.UTC
+	tests := []struct {
+		rowID     interface{}
+		shardBits interface{}
+		expect    interface{}
+	}{
+		// 404411537129996288 is the tso of 2018-11-20 09:53:04.877.
+		{int64(404411537129996288), 0, "2018-11-20 09:53:04.877000"},
+		{int64(404411537129996288), 4, "2018-11-20 09:53:04.877000"},
+		{int64(0b1011<<59 | 404411537129996288), 4, "2018-11-20 09:53:04.877000"},
+		{int64(0b111<<60 | 404411537129996288), 3, "2018-11-20 09:53:04.877000"},
+		{nil, 4, nil},
+		{int64(404411537129996288), nil, nil},
+	}
+	fc := funcs[ast.TiDBDecodeTimeFromRowID]
+	for _, test := range tests {
+		f, err := fc.getFunction(ctx, datumsToConstants(types.MakeDatums(test.rowID, test.shardBits)))
+		require.NoError(t, err)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		require.NoError(t, err)
+		if test.expect == nil {
+			require.True(t, d.IsNull())
+			continue
+		}
+		require.Equal(t, test.expect, d.GetMysqlTime().String())
+	}
+
+	// The invalid rowids and shard bits are decoded as NULL with a warning.
+	invalids := []struct {
+		rowID     int64
+		shardBits int64
+		warn      string
+	}{
+		{0, 0, "invalid rowid 0"},
+		{-1, 0, "invalid rowid -1"},
+		{0b1011 << 59, 4, "invalid rowid 6341068275337658368"},
+		{404411537129996288, -1, "shard_bits should be in [0, 15], but got -1"},
+		{404411537129996288, 16, "shard_bits should be in [0, 15], but got 16"},
+	}
+	for _, test := range invalids {
+		sc := ctx.GetSessionVars().StmtCtx
+		warnCnt := sc.WarningCount()
+		f, err := fc.getFunction(ctx, datumsToConstants(types.MakeDatums(test.rowID, test.shardBits)))
+		require.NoError(t, err)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		require.NoError(t, err)
+		require.True(t, d.IsNull())
+		require.Equal(t, warnCnt+1, sc.WarningCount())
+		require.EqualError(t, sc.GetWarnings()[warnCnt].Err, "[expression:1210]Incorrect arguments to tidb_decode_time_from_rowid: "+test.warn)
+	}
+}
+
+func TestTiDBDecodeTimestampColumn(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	tests := []struct {
+		raw    interface{}
+		loc    string
+		expect interface{}
+	}{
+		{int64(1802216106174185472), "UTC", "1970-01-01 00:00:01.000000"},
+		{int64(1864461879738319423), "UTC", "2038-01-19 03:14:07.999999"},
+		{int64(1849645221953004096), "UTC", "2021-12-01 08:30:15.123456"},
+		{int64(1849645221953004096), "Asia/Shanghai", "2021-12-01 16:30:15.123456"},
+		{int64(1802216106174185472), "America/New_York", "1969-12-31 19:00:01.000000"},
+		{nil, "UTC", nil},
+	}
+	fc := funcs[ast.TiDBDecodeTimestampColumn]
+	for _, test := range tests {
+		loc, err := time.LoadLocation(test.loc)
+		require.NoError(t, err)
+		ctx.GetSessionVars().TimeZone = loc
+		f, err := fc.getFunction(ctx, datumsToConstants(types.MakeDatums(test.raw)))
+		require.NoError(t, err)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		require.NoError(t, err)
+		if test.expect == nil {
+			require.True(t, d.IsNull())
+			continue
+		}
+		require.Equal(t, test.expect, d.GetMysqlTime().String())
+	}
+
+	// The zero timestamp and the values out of the range of timestamp are decoded as NULL with a warning.
+	ctx.GetSessionVars().TimeZone = time.UTC
+	invalids := []int64{
+		0,
+		-1,
+		1802216106157408256, // 1970-01-01 00:00:00
+		1802142984255307776, // 1969-12-31 23:59:59
+		1864461879754096640, // 2038-01-19 03:14:08
+		1802216106174185472 | 0xFFFFFF,
+		1,
+	}
+	for _, raw := range invalids {
+		sc := ctx.GetSessionVars().StmtCtx
+		warnCnt := sc.WarningCount()
+		f, err := fc.getFunction(ctx, datumsToConstants(types.MakeDatums(raw)))
+		require.NoError(t, err)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		require.NoError(t, err)
+		require.True(t, d.IsNull())
+		require.Equal(t, warnCnt+1, sc.WarningCount())
+		require.True(t, types.ErrWrongValue.Equal(sc.GetWarnings()[warnCnt].Err))
+	}
+}
+
 func TestTiDBBoundedStaleness(t *testing.T) {
 	t.Parallel()
 	ctx := createContext(t)
@@ -3101,6 +3253,66 @@ func TestTiDBBoundedStaleness(t *testing.T) {
 	require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/expression/injectSafeTS"))
 }
 
+// mockSafeTSStore is a store whose SafeTS is set by the test.
+type mockSafeTSStore struct {
+	kv.Storage
+	safeTS uint64
+}
+
+func (s *mockSafeTSStore) GetMinSafeTS(_ string) uint64 {
+	return atomic.LoadUint64(&s.safeTS)
+}
+
+func TestTiDBWaitTxnTS(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	store := &mockSafeTSStore{safeTS: 100}
+	ctx.Store = store
+	waitTxnTS := func(targetTS, timeout interface{}) (types.Datum, error) {
+		f, err := newFunctionForTest(ctx, ast.TiDBWaitTxnTS, primitiveValsToConstants(ctx, []interface{}{targetTS, timeout})...)
+		require.NoError(t, err)
+		return f.Eval(chunk.Row{})
+	}
+
+	// The SafeTS has reached the target TS.
+	d, err := waitTxnTS(100, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), d.GetInt64())
+
+	// The SafeTS doesn't reach the target TS within the timeout.
+	start := time.Now()
+	d, err = waitTxnTS(200, 50)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), d.GetInt64())
+	require.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	// The SafeTS reaches the target TS while waiting.
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		atomic.StoreUint64(&store.safeTS, 200)
+	}()
+	d, err = waitTxnTS(200, 10000)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), d.GetInt64())
+
+	// The query is killed while waiting.
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		atomic.StoreUint32(&ctx.GetSessionVars().Killed, 1)
+	}()
+	_, err = waitTxnTS(300, 10000)
+	require.True(t, terror.ErrorEqual(err, ErrQueryInterrupted), "%v", err)
+	atomic.StoreUint32(&ctx.GetSessionVars().Killed, 0)
+
+	// NULL arguments.
+	d, err = waitTxnTS(nil, 10)
+	require.NoError(t, err)
+	require.True(t, d.IsNull())
+	d, err = waitTxnTS(100, nil)
+	require.NoError(t, err)
+	require.True(t, d.IsNull())
+}
+
 func TestGetIntervalFromDecimal(t *testing.T) {
 	t.Parallel()
 	ctx := createContext(t)
@@ -855,6 +855,29 @@ func (b *builtinTidbParseTsoSig) vecEvalTime(input *chunk.Chunk, result *chunk.C
 	return nil
 }
 
+func (b *builtinTidbParseTsoLogicalSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinTidbParseTsoLogicalSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	if err := b.args[0].VecEvalInt(b.ctx, input, result); err != nil {
+		return err
+	}
+	args := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		if args[i] <= 0 {
+			result.SetNull(i, true)
+			continue
+		}
+		args[i] = oracle.ExtractLogical(uint64(args[i]))
+	}
+	return nil
+}
+
 func (b *builtinTiDBBoundedStalenessSig) vectorized() bool {
 	return true
 }
@@ -2855,3 +2878,38 @@ func (b *builtinDayOfMonthSig) vecEvalInt(input *chunk.Chunk, result *chunk.Colu
 func (b *builtinDayOfMonthSig) vectorized() bool {
 	return true
 }
+
+func (b *builtinTiDBDecodeTimestampColumnSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinTiDBDecodeTimestampColumnSig) vecEvalTime(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalInt(b.ctx, input, buf); err != nil {
+		return err
+	}
+	args := buf.Int64s()
+	result.ResizeTime(n, false)
+	result.MergeNulls(buf)
+	times := result.Times()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		t, isNull, err := decodeTimestampColumn(b.ctx, args[i])
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.SetNull(i, true)
+			continue
+		}
+		times[i] = t
+	}
+	return nil
+}
@@ -521,6 +521,17 @@ var vecBuiltinTimeCases = map[string][]vecExprBenchCase{
 			geners: []dataGenerator{newRangeInt64Gener(248160190726144000, math.MaxInt64)},
 		},
 	},
+	ast.TiDBParseTsoLogical: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETInt}},
+	},
+	ast.TiDBDecodeTimestampColumn: {
+		{
+			retEvalType:   types.ETDatetime,
+			childrenTypes: []types.EvalType{types.ETInt},
+			// The packed values of [1970-01-01 00:00:00, 2038-01-19 03:14:08], some of them are invalid timestamps.
+			geners: []dataGenerator{newRangeInt64Gener(1802216106157408256, 1864461879754096640)},
+		},
+	},
 	// Todo: how to inject the safeTS for better testing.
 	ast.TiDBBoundedStaleness: {
 		{
@@ -46,7 +46,7 @@ func checkSequenceFunction(exprs []Expression) bool {
 			continue
 		}
 		switch scalaFunc.FuncName.L {
-		case ast.NextVal:
+		case ast.NextVal, ast.NextValN:
 			nextval++
 		case ast.LastVal:
 			lastval++
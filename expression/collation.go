@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
 )
 
 // ExprCollation is a struct that store the collation related information.
@@ -156,8 +157,21 @@ const (
 	UNICODE = ASCII | EXTENDED
 )
 
+// deriveCoercibilityForScarlarFunc derives the coercibility of sf by the collation inference of its arguments.
+// The functions built by newBaseBuiltinFunc carry their coercibility already, only the ones built with
+// newBaseBuiltinFuncWithFieldType, e.g. the functions decoded from the pushed down expressions, come here.
 func deriveCoercibilityForScarlarFunc(sf *ScalarFunction) Coercibility {
-	panic("this function should never be called")
+	args := sf.GetArgs()
+	argTps := make([]types.EvalType, 0, len(args))
+	for _, arg := range args {
+		argTps = append(argTps, arg.GetType().EvalType())
+	}
+	ec, err := deriveCollation(sf.GetCtx(), sf.FuncName.L, args, sf.GetType().EvalType(), argTps...)
+	if err != nil {
+		logutil.BgLogger().Warn("derive the coercibility of the scalar function failed", zap.String("function", sf.FuncName.L), zap.Error(err))
+		return CoercibilityIgnorable
+	}
+	return ec.Coer
 }
 
 func deriveCoercibilityForConstant(c *Constant) Coercibility {
@@ -242,7 +256,7 @@ func deriveCollation(ctx sessionctx.Context, funcName string, args []Expression,
 	case ast.Case:
 		// FIXME: case function aggregate collation is not correct.
 		return CheckAndDeriveCollationFromExprs(ctx, funcName, retType, args...)
-	case ast.Database, ast.User, ast.CurrentUser, ast.Version, ast.CurrentRole, ast.TiDBVersion:
+	case ast.Database, ast.Schema, ast.User, ast.SessionUser, ast.SystemUser, ast.CurrentUser, ast.Version, ast.CurrentRole, ast.TiDBVersion, ast.TiDBVersionComment:
 		chs, coll := charset.GetDefaultCharsetAndCollate()
 		return &ExprCollation{CoercibilitySysconst, UNICODE, chs, coll}, nil
 	case ast.Format, ast.Space, ast.ToBase64, ast.UUID, ast.Hex, ast.MD5, ast.SHA, ast.SHA2:
@@ -84,6 +84,26 @@ func TestPBToExpr(t *testing.T) {
 	require.Error(t, err)
 }
 
+// TestPBToExprCoercibility tests the coercibility of the functions decoded from the pushed down expressions,
+// which is derived by the collation inference since they are not built with it.
+func TestPBToExprCoercibility(t *testing.T) {
+	t.Parallel()
+	sc := new(stmtctx.StatementContext)
+	fieldTps := []*types.FieldType{newIntFieldType()}
+	tests := []struct {
+		expr *tipb.Expr
+		coer Coercibility
+	}{
+		{scalarFunctionExpr(tipb.ScalarFuncSig_CastIntAsString, toPBFieldType(newStringFieldType()), columnExpr(0)), CoercibilityCoercible},
+		{scalarFunctionExpr(tipb.ScalarFuncSig_LTInt, toPBFieldType(newIntFieldType()), columnExpr(0), columnExpr(0)), CoercibilityNumeric},
+	}
+	for _, tt := range tests {
+		expr, err := PBToExpr(tt.expr, fieldTps, sc)
+		require.NoError(t, err)
+		require.Equal(t, tt.coer, expr.Coercibility())
+	}
+}
+
 // TestEval test expr.Eval().
 func TestEval(t *testing.T) {
 	t.Parallel()
@@ -36,6 +36,7 @@ var (
 	ErrInvalidTableSample          = dbterror.ClassExpression.NewStd(mysql.ErrInvalidTableSample)
 	ErrInternal                    = dbterror.ClassOptimizer.NewStd(mysql.ErrInternal)
 	ErrNoDB                        = dbterror.ClassOptimizer.NewStd(mysql.ErrNoDB)
+	ErrQueryInterrupted            = dbterror.ClassExpression.NewStd(mysql.ErrQueryInterrupted)
 
 	// All the un-exported errors are defined here:
 	errFunctionNotExists             = dbterror.ClassExpression.NewStd(mysql.ErrSpDoesNotExist)
@@ -53,9 +54,13 @@ var (
 	errWrongValueForType             = dbterror.ClassExpression.NewStd(mysql.ErrWrongValueForType)
 	errUnknown                       = dbterror.ClassExpression.NewStd(mysql.ErrUnknown)
 	errSpecificAccessDenied          = dbterror.ClassExpression.NewStd(mysql.ErrSpecificAccessDenied)
+	errWrongTableName                = dbterror.ClassExpression.NewStd(mysql.ErrWrongTableName)
+	errGenerateSeriesTooLarge        = dbterror.ClassExpression.NewStdErr(mysql.ErrWrongArguments,
+		pmysql.Message("Incorrect arguments to %s: the series has more than %d values", nil))
 
 	// Sequence usage privilege check.
 	errSequenceAccessDenied      = dbterror.ClassExpression.NewStd(mysql.ErrTableaccessDenied)
+	errTableAccessDenied         = dbterror.ClassExpression.NewStd(mysql.ErrTableaccessDenied)
 	errUnsupportedJSONComparison = dbterror.ClassExpression.NewStdErr(mysql.ErrNotSupportedYet,
 		pmysql.Message("comparison of JSON in the LEAST and GREATEST operators", nil))
 )
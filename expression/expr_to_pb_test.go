@@ -1137,6 +1137,31 @@ func TestExprOnlyPushDownToTiKV(t *testing.T) {
 	require.Len(t, remained, 0)
 }
 
+func TestSessionDependentFuncNotPushDown(t *testing.T) {
+	t.Parallel()
+	sc := new(stmtctx.StatementContext)
+	client := new(mock.Client)
+	ctx := mock.NewContext()
+
+	intColumn := genColumn(mysql.TypeLonglong, 1)
+	plus, err := NewFunction(ctx, ast.Plus, types.NewFieldType(mysql.TypeLonglong), intColumn, intColumn)
+	require.NoError(t, err)
+
+	funcNames := []string{ast.RowCount, ast.FoundRows, ast.LastInsertId, ast.ConnectionID, ast.Database, ast.User, ast.CurrentUser}
+	for _, funcName := range funcNames {
+		// Build the function without folding, which is how it's met by the push down of a real query.
+		function, err := NewFunctionBase(ctx, funcName, types.NewFieldType(mysql.TypeUnspecified))
+		require.NoError(t, err)
+		require.IsType(t, &ScalarFunction{}, function, funcName)
+		exprs := []Expression{plus, function}
+		for _, storeType := range []kv.StoreType{kv.TiKV, kv.TiFlash, kv.UnSpecified} {
+			pushed, remained := PushDownExprs(sc, exprs, client, storeType)
+			require.Equal(t, []Expression{plus}, pushed, funcName)
+			require.Equal(t, []Expression{function}, remained, funcName)
+		}
+	}
+}
+
 func TestGroupByItem2Pb(t *testing.T) {
 	t.Parallel()
 	sc := new(stmtctx.StatementContext)
@@ -1145,7 +1145,18 @@ func scalarExprSupportedByTiDB(function *ScalarFunction) bool {
 	return scalarExprSupportedByTiKV(function) || scalarExprSupportedByFlash(function)
 }
 
+// isSessionDependentFunc checks whether the function depends on the session state, which only exists in TiDB.
+func isSessionDependentFunc(sf *ScalarFunction) bool {
+	_, ok := sessionDependentFunctions[sf.FuncName.L]
+	return ok
+}
+
 func canFuncBePushed(sf *ScalarFunction, storeType kv.StoreType) bool {
+	// The session state doesn't exist in the storage layer, even if the push down is forced by the failpoint.
+	if isSessionDependentFunc(sf) {
+		return false
+	}
+
 	// Use the failpoint to control whether to push down an expression in the integration test.
 	// Push down all expression if the `failpoint expression` is `all`, otherwise, check
 	// whether scalar function's name is contained in the enabled expression list (e.g.`ne,eq,lt`).
@@ -21,41 +21,78 @@ import (
 
 // UnCacheableFunctions stores functions which can not be cached to plan cache.
 var UnCacheableFunctions = map[string]struct{}{
+	ast.Database:            {},
+	ast.CurrentUser:         {},
+	ast.CurrentRole:         {},
+	ast.CurrentRoleJSON:     {},
+	ast.User:                {},
+	ast.ConnectionID:        {},
+	ast.LastInsertId:        {},
+	ast.RowCount:            {},
+	ast.TiDBParseAndExplain: {},
+	ast.TiDBEstimateCost:    {},
+	ast.Version:             {},
+	ast.TiDBVersionComment:  {},
+	ast.Like:                {},
+}
+
+// sessionDependentFunctions stores functions which are evaluated with the session state of TiDB, such as the
+// affected rows of the previous statement, so they can't be pushed down to the storage layer.
+var sessionDependentFunctions = map[string]struct{}{
+	ast.RowCount:     {},
+	ast.FoundRows:    {},
+	ast.LastInsertId: {},
+	ast.ConnectionID: {},
 	ast.Database:     {},
-	ast.CurrentUser:  {},
-	ast.CurrentRole:  {},
+	ast.Schema:       {},
 	ast.User:         {},
-	ast.ConnectionID: {},
-	ast.LastInsertId: {},
-	ast.RowCount:     {},
-	ast.Version:      {},
-	ast.Like:         {},
+	ast.SessionUser:  {},
+	ast.SystemUser:   {},
+	ast.CurrentUser:  {},
 }
 
 // unFoldableFunctions stores functions which can not be folded duration constant folding stage.
 var unFoldableFunctions = map[string]struct{}{
-	ast.Sysdate:   {},
-	ast.FoundRows: {},
-	ast.Rand:      {},
-	ast.UUID:      {},
-	ast.Sleep:     {},
-	ast.RowFunc:   {},
-	ast.Values:    {},
-	ast.SetVar:    {},
-	ast.GetVar:    {},
-	ast.GetParam:  {},
-	ast.Benchmark: {},
-	ast.DayName:   {},
-	ast.NextVal:   {},
-	ast.LastVal:   {},
-	ast.SetVal:    {},
+	ast.Sysdate:                      {},
+	ast.FoundRows:                    {},
+	ast.Rand:                         {},
+	ast.UUID:                         {},
+	ast.Sleep:                        {},
+	ast.RowFunc:                      {},
+	ast.Values:                       {},
+	ast.SetVar:                       {},
+	ast.GetVar:                       {},
+	ast.GetParam:                     {},
+	ast.Benchmark:                    {},
+	ast.TiDBParseAndExplain:          {},
+	ast.TiDBDecodeLockKey:            {},
+	ast.TiDBKeyspaceID:               {},
+	ast.TiDBCurrentStmtType:          {},
+	ast.TiDBTraceID:                  {},
+	ast.TiDBEstimateIndexSelectivity: {},
+	ast.TiDBCurrentBatchSize:         {},
+	ast.TiDBCurrentSQLDigestText:     {},
+	ast.TiDBPlanDigest:               {},
+	ast.TiDBCurrentIsolationLevel:    {},
+	ast.TiDBCurrentTxnID:             {},
+	ast.TiDBEstimateCost:             {},
+	ast.TiDBSessionAlive:             {},
+	ast.TiDBBenchmark:                {},
+	ast.DayName:                      {},
+	ast.NextVal:                      {},
+	ast.NextValN:                     {},
+	ast.LastVal:                      {},
+	ast.SetVal:                       {},
+	ast.TiDBWaitTxnTS:                {},
+	ast.TiDBMVCCInfo:                 {},
 }
 
 // DisableFoldFunctions stores functions which prevent child scope functions from being constant folded.
 // Typically, these functions shall also exist in unFoldableFunctions, to stop from being folded when they themselves
 // are in child scope of an outer function, and the outer function is recursively folding its children.
 var DisableFoldFunctions = map[string]struct{}{
-	ast.Benchmark: {},
+	ast.Benchmark:     {},
+	ast.TiDBBenchmark: {},
 }
 
 // TryFoldFunctions stores functions which try to fold constant in child scope functions if without errors/warnings,
@@ -93,6 +130,7 @@ var IllegalFunctions4GeneratedColumns = map[string]struct{}{
 	ast.UTCTime:          {},
 	ast.UTCTimestamp:     {},
 	ast.Benchmark:        {},
+	ast.TiDBBenchmark:    {},
 	ast.CurrentUser:      {},
 	ast.Database:         {},
 	ast.FoundRows:        {},
@@ -1087,6 +1087,51 @@ func TestIssue19116(t *testing.T) {
 	tk.MustQuery("select coercibility(concat(unix_timestamp(a))) from t;").Check(testkit.Rows("4"))
 }
 
+func TestCoercibilityLevels(t *testing.T) {
+	collate.SetNewCollationEnabledForTest(true)
+	defer collate.SetNewCollationEnabledForTest(false)
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(general varchar(10) collate utf8mb4_general_ci, unicode varchar(10) collate utf8mb4_unicode_ci, i int)")
+	tk.MustExec("insert into t values ('a', 'b', 1)")
+	tests := []struct {
+		expr   string
+		result int
+	}{
+		// explicit
+		{"general collate utf8mb4_bin", 0},
+		{"concat(general collate utf8mb4_bin, unicode)", 0},
+		// none
+		{"concat(general, unicode)", 1},
+		// implicit
+		{"general", 2},
+		{"concat(general, 'abc')", 2},
+		{"concat(general, user())", 2},
+		// system constant
+		{"user()", 3},
+		{"concat('abc', user())", 3},
+		{"concat(database(), version())", 3},
+		// coercible
+		{"'abc'", 4},
+		{"concat(i, 'abc')", 4},
+		// numeric
+		{"i", 5},
+		{"i + 1", 5},
+		{"general = unicode collate utf8mb4_bin", 5},
+		// ignorable
+		{"null", 6},
+		{"concat(null, null)", 6},
+	}
+	for _, tt := range tests {
+		tk.MustQuery(fmt.Sprintf("select coercibility(%s) from t", tt.expr)).Check(testkit.Rows(fmt.Sprintf("%d", tt.result)))
+	}
+}
+
 func TestIssue17063(t *testing.T) {
 	collate.SetNewCollationEnabledForTest(true)
 	defer collate.SetNewCollationEnabledForTest(false)
@@ -4264,7 +4309,6 @@ func TestNoopFunctions(t *testing.T) {
 
 	message := `.* has only noop implementation in tidb now, use tidb_enable_noop_functions to enable these functions`
 	stmts := []string{
-		"SELECT SQL_CALC_FOUND_ROWS * FROM t1 LIMIT 1",
 		"SELECT * FROM t1 LOCK IN SHARE MODE",
 		"SELECT * FROM t1 GROUP BY a DESC",
 		"SELECT * FROM t1 GROUP BY a ASC",
@@ -17,6 +17,7 @@ package expression_test
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"math"
@@ -27,11 +28,14 @@ import (
 	"testing"
 	"time"
 
+	basictracer "github.com/opentracing/basictracer-go"
+	"github.com/opentracing/opentracing-go"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
@@ -40,6 +44,7 @@ import (
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/structure"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/testkit"
@@ -47,6 +52,7 @@ import (
 	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/kvcache"
+	"github.com/pingcap/tidb/util/rowcodec"
 	"github.com/pingcap/tidb/util/sem"
 	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/pingcap/tidb/util/testutil"
@@ -1131,6 +1137,16 @@ func TestInfoBuiltin(t *testing.T) {
 	result.Check(testkit.Rows("5"))
 	result = tk.MustQuery("select last_insert_id();")
 	result.Check(testkit.Rows("5"))
+	// A negative argument is out of the range of BIGINT UNSIGNED, so it's clamped to 0,
+	// which keeps the last insert ID of the session like LAST_INSERT_ID(0).
+	result = tk.MustQuery("select last_insert_id(-1);")
+	result.Check(testkit.Rows("0"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1690 BIGINT UNSIGNED value is out of range in 'last_insert_id(-1)'"))
+	result = tk.MustQuery("select last_insert_id();")
+	result.Check(testkit.Rows("5"))
+	result = tk.MustQuery("select last_insert_id(18446744073709551615);")
+	result.Check(testkit.Rows("18446744073709551615"))
+	tk.MustQuery("show warnings").Check(testkit.Rows())
 
 	// for found_rows
 	tk.MustExec("drop table if exists t")
@@ -1159,6 +1175,33 @@ func TestInfoBuiltin(t *testing.T) {
 	tk.MustQuery("select count(*) from t") // Test ProjectionExec
 	result = tk.MustQuery("select found_rows()")
 	result.Check(testkit.Rows("1"))
+	tk.MustQuery("select * from t limit 1")
+	result = tk.MustQuery("select found_rows()")
+	result.Check(testkit.Rows("1"))
+	tk.MustQuery("select sql_calc_found_rows * from t limit 1").Check(testkit.Rows("1"))
+	result = tk.MustQuery("select found_rows()")
+	result.Check(testkit.Rows("3"))
+	tk.MustQuery("select sql_calc_found_rows * from t order by a desc limit 1, 1").Check(testkit.Rows("2"))
+	result = tk.MustQuery("select found_rows()")
+	result.Check(testkit.Rows("3"))
+	tk.MustQuery("select sql_calc_found_rows * from t where a = 2 limit 0").Check(testkit.Rows())
+	result = tk.MustQuery("select found_rows()")
+	result.Check(testkit.Rows("2"))
+	tk.MustQuery("select sql_calc_found_rows * from t limit 5, 1").Check(testkit.Rows())
+	result = tk.MustQuery("select found_rows()")
+	result.Check(testkit.Rows("3"))
+	tk.MustQuery("select sql_calc_found_rows * from t where a > 5 limit 1").Check(testkit.Rows())
+	result = tk.MustQuery("select found_rows()")
+	result.Check(testkit.Rows("0"))
+	tk.MustQuery("select sql_calc_found_rows * from t")
+	result = tk.MustQuery("select found_rows()")
+	result.Check(testkit.Rows("3"))
+	tk.MustQuery("select sql_calc_found_rows * from t union all select * from t limit 1").Check(testkit.Rows("1"))
+	result = tk.MustQuery("select found_rows()")
+	result.Check(testkit.Rows("6"))
+	tk.MustQuery("(select sql_calc_found_rows * from t limit 1) union all (select * from t limit 1)").Check(testkit.Rows("1", "1"))
+	result = tk.MustQuery("select found_rows()")
+	result.Check(testkit.Rows("2"))
 
 	// for database
 	result = tk.MustQuery("select database()")
@@ -1194,6 +1237,14 @@ func TestInfoBuiltin(t *testing.T) {
 	result = tk.MustQuery("select version()")
 	result.Check(testkit.Rows(mysql.ServerVersion))
 
+	// for version_compare
+	result = tk.MustQuery("select version_compare('5.7.25-TiDB-v6.1.0', 'v6.1.0'), version_compare('5.7.25-TiDB-v6.1.0', '5.7.25-TiDB-v5.4.0'), version_compare('v6.1.0', '5.7.25-TiDB-v6.1.1'), version_compare('v6.1.0', 'unknown')")
+	result.Check(testkit.Rows("0 1 -1 <nil>"))
+
+	// for tidb_version_comment
+	result = tk.MustQuery("select tidb_version_comment() = @@version_comment, coercibility(tidb_version_comment())")
+	result.Check(testkit.Rows("1 3"))
+
 	// for row_count
 	tk.MustExec("drop table if exists t")
 	tk.MustExec("create table t (a int, b int, PRIMARY KEY (a))")
@@ -1225,6 +1276,16 @@ func TestInfoBuiltin(t *testing.T) {
 	result.Check(success)
 	err := tk.ExecToErr(`select benchmark(3, length("a", "b"))`)
 	require.Error(t, err)
+	// A warning is appended when the loop count exceeds tidb_benchmark_warn_threshold.
+	tk.MustExec("set @@tidb_benchmark_warn_threshold = 5")
+	tk.MustQuery("select benchmark(5, 1)").Check(success)
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+	tk.MustQuery("select benchmark(6, 1)").Check(success)
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 BENCHMARK() loop count 6 exceeds tidb_benchmark_warn_threshold(5)"))
+	tk.MustExec("set @@tidb_benchmark_warn_threshold = 0")
+	tk.MustQuery("select benchmark(6, 1)").Check(success)
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+	tk.MustExec("set @@tidb_benchmark_warn_threshold = default")
 	// Quoted from https://dev.mysql.com/doc/refman/5.7/en/information-functions.html#function_benchmark
 	// Although the expression can be a subquery, it must return a single column and at most a single row.
 	// For example, BENCHMARK(10, (SELECT * FROM t)) will fail if the table t has more than one column or
@@ -1252,6 +1313,12 @@ func TestInfoBuiltin(t *testing.T) {
 	// 2 * 2, error.
 	err = tk.ExecToErr(twoColumnQuery)
 	require.Error(t, err)
+
+	// for tidb_benchmark
+	tk.MustQuery(`select tidb_benchmark(3, length("abc")) >= 0, tidb_benchmark(-1, 1), tidb_benchmark(null, 1)`).Check(testkit.Rows("1 <nil> <nil>"))
+	tk.MustQuery(`select tidb_benchmark(10, sleep(0.001)) >= 10000000`).Check(testkit.Rows("1"))
+	err = tk.ExecToErr("select tidb_benchmark(10, (select a from t))")
+	require.Error(t, err)
 }
 
 func TestControlBuiltin(t *testing.T) {
@@ -2787,6 +2854,20 @@ func TestTiDBIsOwnerFunc(t *testing.T) {
 	result.Check(testkit.Rows(fmt.Sprintf("%v", ret)))
 }
 
+func TestTiDBDDLOwnerFunc(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	ownerID, err := tk.Session().DDLOwnerChecker().GetOwnerID(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, ownerID)
+	tk.MustQuery("select tidb_ddl_owner()").Check(testkit.Rows(ownerID))
+	tk.MustQuery("select tidb_ddl_owner() is not null, tidb_is_ddl_owner()").Check(testkit.Rows("1 1"))
+}
+
 func TestTiDBDecodePlanFunc(t *testing.T) {
 	t.Parallel()
 
@@ -2826,6 +2907,758 @@ func TestTiDBDecodePlanFunc(t *testing.T) {
 	tk.MustQuery("select tidb_decode_plan('xxx')").Check(testkit.Rows("xxx"))
 }
 
+func TestTiDBDecodePlanStrictFunc(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustQuery("select tidb_decode_plan_strict('')").Check(testkit.Rows(""))
+	tk.MustQuery("select tidb_decode_plan_strict(null)").Check(testkit.Rows("<nil>"))
+	plan := "7APIMAk1XzEzCTAJMQlmdW5jczpjb3VudCgxKQoxCTE3XzE0CTAJMAlpbm5lciBqb2luLCBp" +
+		"AQyQOlRhYmxlUmVhZGVyXzIxLCBlcXVhbDpbZXEoQ29sdW1uIzEsIA0KCDkpIBkXADIVFywxMCldCjIJMzFfMTgFZXhkYXRhOlNlbGVjdGlvbl" +
+		"8xNwozCTFfMTcJMQkwCWx0HVlATlVMTCksIG5vdChpc251bGwVHAApUhcAUDIpKQo0CTEwXzE2CTEJMTAwMDAJdAHB2Dp0MSwgcmFuZ2U6Wy1p" +
+		"bmYsK2luZl0sIGtlZXAgb3JkZXI6ZmFsc2UsIHN0YXRzOnBzZXVkbwoFtgAyAZcEMAk6tgAEMjAFtgQyMDq2AAg5LCBmtgAAMFa3AAA5FbcAO" +
+		"T63AAAyzrcA"
+	tk.MustQuery(fmt.Sprintf("select tidb_decode_plan_strict('%s') = tidb_decode_plan('%s')", plan, plan)).Check(testkit.Rows("1"))
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+
+	// The malformed plans are reported instead of being returned as they are.
+	tk.MustQuery("select tidb_decode_plan_strict('xxx')").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 decode plan failed with error: illegal base64 data at input byte 0"))
+	tk.MustQuery("select tidb_decode_plan_strict(to_base64('xxx'))").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 decode plan failed with error: snappy: corrupt input"))
+}
+
+func TestTiDBParseAndExplain(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int primary key, b int)")
+	tk.MustQuery("select tidb_parse_and_explain('select * from t where a = 1')").Check(testkit.Rows(
+		`[{"accessObject": "table:t", "estRows": "1.00", "id": "Point_Get_1", "operatorInfo": "handle:1", "task": "root"}]`))
+	tk.MustQuery("select json_length(tidb_parse_and_explain('select b from t where b > 1'))").Check(testkit.Rows("3"))
+	// The statement is never executed.
+	tk.MustQuery("select tidb_parse_and_explain('select sleep(10)')").Check(testkit.Rows(
+		`[{"accessObject": "", "estRows": "1.00", "id": "Projection_3", "operatorInfo": "sleep(10)->Column#1", "task": "root"}, ` +
+			`{"accessObject": "", "estRows": "1.00", "id": "└─TableDual_4", "operatorInfo": "rows:1", "task": "root"}]`))
+
+	tk.MustQuery("select tidb_parse_and_explain('select * form t')").Check(testkit.Rows("<nil>"))
+	require.Equal(t, uint16(1), tk.Session().GetSessionVars().StmtCtx.WarningCount())
+	tk.MustQuery("select tidb_parse_and_explain('select * from t_not_exists')").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1146 Table 'test.t_not_exists' doesn't exist"))
+	tk.MustQuery("select tidb_parse_and_explain('delete from t')").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 tidb_parse_and_explain only supports read-only statements"))
+	tk.MustQuery("select tidb_parse_and_explain(null)").Check(testkit.Rows("<nil>"))
+
+	// The hints of the explained statement don't take effect on the running statement.
+	tk.MustQuery("select /*+ max_execution_time(1000) */ " +
+		"json_length(tidb_parse_and_explain('select /*+ max_execution_time(10) */ * from t where a = 1'))").Check(testkit.Rows("1"))
+	require.Equal(t, uint64(1000), tk.Session().GetSessionVars().StmtCtx.MaxExecutionTime)
+
+	tk.MustExec("create user 'parse_and_explain'@'%'")
+	tk.MustExec("grant select on test.t to 'parse_and_explain'@'%'")
+	tk.MustExec("create table t1(a int)")
+	tk2 := testkit.NewTestKit(t, store)
+	tk2.MustExec("use test")
+	require.True(t, tk2.Session().Auth(&auth.UserIdentity{Username: "parse_and_explain", Hostname: "%"}, nil, nil))
+	tk2.MustQuery("select json_length(tidb_parse_and_explain('select * from t where b > 1'))").Check(testkit.Rows("3"))
+	tk2.MustQuery("select tidb_parse_and_explain('select * from t1')").Check(testkit.Rows("<nil>"))
+	tk2.MustQuery("show warnings").Check(testkit.Rows("Warning 1142 SELECT command denied to user 'parse_and_explain'@'%' for table 't1'"))
+}
+
+func TestTiDBEstimateCost(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, index ib(b))")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3), (4, 4), (5, 5), (6, 6), (7, 7), (8, 8), (9, 9), (10, 10)")
+	tk.MustExec("analyze table t")
+
+	tk.MustQuery("select tidb_estimate_cost('select * from t where b = 1') > 0").Check(testkit.Rows("1"))
+	tk.MustQuery("select tidb_estimate_cost('select * from t where b = 1') < tidb_estimate_cost('select * from t where b > 0')").Check(testkit.Rows("1"))
+	tk.MustQuery("select tidb_estimate_cost('select * from t use index(ib) where b < 3') < tidb_estimate_cost('select * from t use index(ib) where b < 8')").Check(testkit.Rows("1"))
+
+	tk.MustQuery("select tidb_estimate_cost('select * form t')").Check(testkit.Rows("<nil>"))
+	require.Equal(t, uint16(1), tk.Session().GetSessionVars().StmtCtx.WarningCount())
+	tk.MustQuery("select tidb_estimate_cost('select * from t_not_exists')").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1146 Table 'test.t_not_exists' doesn't exist"))
+	tk.MustQuery("select tidb_estimate_cost('delete from t')").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 tidb_estimate_cost only supports read-only statements"))
+	tk.MustQuery("select tidb_estimate_cost(null)").Check(testkit.Rows("<nil>"))
+
+	// The hints of the estimated statement don't take effect on the running statement.
+	tk.MustQuery("select /*+ max_execution_time(1000) */ " +
+		"tidb_estimate_cost('select /*+ max_execution_time(10) */ * from t where b = 1') > 0").Check(testkit.Rows("1"))
+	require.Equal(t, uint64(1000), tk.Session().GetSessionVars().StmtCtx.MaxExecutionTime)
+
+	tk.MustExec("create user 'estimate_cost'@'%'")
+	tk.MustExec("grant select on test.t to 'estimate_cost'@'%'")
+	tk.MustExec("create table t1(a int)")
+	tk2 := testkit.NewTestKit(t, store)
+	tk2.MustExec("use test")
+	require.True(t, tk2.Session().Auth(&auth.UserIdentity{Username: "estimate_cost", Hostname: "%"}, nil, nil))
+	tk2.MustQuery("select tidb_estimate_cost('select * from t where b > 1') > 0").Check(testkit.Rows("1"))
+	tk2.MustQuery("select tidb_estimate_cost('select * from t1')").Check(testkit.Rows("<nil>"))
+	tk2.MustQuery("show warnings").Check(testkit.Rows("Warning 1142 SELECT command denied to user 'estimate_cost'@'%' for table 't1'"))
+}
+
+func TestTiDBSessionAlive(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	// The test kit has no session manager to look the connections up.
+	tk.MustQuery("select tidb_session_alive(connection_id())").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("select tidb_session_alive(null)").Check(testkit.Rows("<nil>"))
+
+	tk.MustExec("create user 'session_alive'@'%'")
+	tk2 := testkit.NewTestKit(t, store)
+	require.True(t, tk2.Session().Auth(&auth.UserIdentity{Username: "session_alive", Hostname: "%"}, nil, nil))
+	err := tk2.ExecToErr("select tidb_session_alive(1)")
+	require.EqualError(t, err, "[expression:1227]Access denied; you need (at least one of) the PROCESS privilege(s) for this operation")
+	tk.MustExec("grant process on *.* to 'session_alive'@'%'")
+	tk2.MustQuery("select tidb_session_alive(1)").Check(testkit.Rows("<nil>"))
+}
+
+func TestTiDBEncodeTimeRangeKeys(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, ts datetime, b int, key idx_ts(ts), key idx_a(a))")
+	tk.MustExec("set @keys = tidb_encode_time_range_keys('test.t', 'idx_ts', '2021-01-01 00:00:00', '2021-01-01 00:03:00', 60)")
+	tk.MustQuery("select json_length(@keys)").Check(testkit.Rows("4"))
+	for i := 0; i < 4; i++ {
+		tk.MustQuery(fmt.Sprintf("select json_extract(tidb_decode_key(json_unquote(json_extract(@keys, '$[%d]'))), '$.index_vals.ts')", i)).
+			Check(testkit.Rows(fmt.Sprintf(`"2021-01-01 00:0%d:00"`, i)))
+	}
+	tk.MustQuery("select json_unquote(json_extract(@keys, '$[0]')) < json_unquote(json_extract(@keys, '$[1]')), " +
+		"json_unquote(json_extract(@keys, '$[1]')) < json_unquote(json_extract(@keys, '$[2]')), " +
+		"json_unquote(json_extract(@keys, '$[2]')) < json_unquote(json_extract(@keys, '$[3]'))").Check(testkit.Rows("1 1 1"))
+	// The end time is not on a boundary, and the table name is resolved in the current database.
+	tk.MustQuery("select json_length(tidb_encode_time_range_keys('t', 'idx_ts', '2021-01-01', '2021-01-02 00:00:01', 3600))").Check(testkit.Rows("25"))
+	tk.MustQuery("select json_length(tidb_encode_time_range_keys('t', 'idx_ts', '2021-01-01', '2021-01-01', 3600))").Check(testkit.Rows("1"))
+	tk.MustQuery("select tidb_encode_time_range_keys('t', 'idx_ts', null, '2021-01-01', 3600)").Check(testkit.Rows("<nil>"))
+
+	require.EqualError(t, tk.QueryToErr("select tidb_encode_time_range_keys('t', 'idx_ts', '2021-01-01', '2021-01-02', 0)"),
+		"[expression:1210]Incorrect arguments to tidb_encode_time_range_keys: interval_seconds should be positive, but got 0")
+	require.EqualError(t, tk.QueryToErr("select tidb_encode_time_range_keys('t', 'idx_ts', '2021-01-02', '2021-01-01', 60)"),
+		"[expression:1210]Incorrect arguments to tidb_encode_time_range_keys: start_time 2021-01-02 00:00:00.000000 is later than end_time 2021-01-01 00:00:00.000000")
+	require.EqualError(t, tk.QueryToErr("select tidb_encode_time_range_keys('t', 'idx_a', '2021-01-01', '2021-01-02', 60)"),
+		"the first column a of index idx_a is not a time column")
+	require.EqualError(t, tk.QueryToErr("select tidb_encode_time_range_keys('t', 'idx_x', '2021-01-01', '2021-01-02', 60)"),
+		"index idx_x doesn't exist in table t")
+	require.EqualError(t, tk.QueryToErr("select tidb_encode_time_range_keys('t_not_exists', 'idx_ts', '2021-01-01', '2021-01-02', 60)"),
+		"[schema:1146]Table 'test.t_not_exists' doesn't exist")
+
+	tk.MustExec("create user 'encode_time_range_keys'@'%'")
+	tk.MustExec("grant select on test.t to 'encode_time_range_keys'@'%'")
+	tk2 := testkit.NewTestKit(t, store)
+	require.True(t, tk2.Session().Auth(&auth.UserIdentity{Username: "encode_time_range_keys", Hostname: "%"}, nil, nil))
+	require.EqualError(t, tk2.QueryToErr("select tidb_encode_time_range_keys('test.t', 'idx_ts', '2021-01-01', '2021-01-02', 3600)"),
+		"[expression:1142]INSERT command denied to user 'encode_time_range_keys'@'%' for table 't'")
+	// The privilege is checked before the table is resolved, so the existence of a table isn't leaked.
+	require.EqualError(t, tk2.QueryToErr("select tidb_encode_time_range_keys('test.t_not_exists', 'idx_ts', '2021-01-01', '2021-01-02', 3600)"),
+		"[expression:1142]INSERT command denied to user 'encode_time_range_keys'@'%' for table 't_not_exists'")
+	tk.MustExec("grant insert on test.t to 'encode_time_range_keys'@'%'")
+	tk2.MustQuery("select json_length(tidb_encode_time_range_keys('test.t', 'idx_ts', '2021-01-01', '2021-01-02', 3600))").Check(testkit.Rows("25"))
+}
+
+func TestTiDBDecodeRow(t *testing.T) {
+	t.Parallel()
+
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int primary key, b varchar(10), c datetime, d double, e int)")
+	tbl, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	colIDs := make([]int64, 0, 4)
+	for _, col := range tbl.Meta().Columns[1:] {
+		colIDs = append(colIDs, col.ID)
+	}
+	row := types.MakeDatums("abc", types.NewTime(types.FromDate(2021, 1, 2, 3, 4, 5, 0), mysql.TypeDatetime, 0), 1.5, nil)
+	sc := tk.Session().GetSessionVars().StmtCtx
+	newRow, err := tablecodec.EncodeRow(sc, row, colIDs, nil, nil, &rowcodec.Encoder{})
+	require.NoError(t, err)
+	oldRow, err := tablecodec.EncodeOldRow(sc, row, colIDs, nil, nil)
+	require.NoError(t, err)
+
+	expected := `{"b": "abc", "c": "2021-01-02 03:04:05", "d": "1.5", "e": null}`
+	for _, value := range [][]byte{newRow, oldRow} {
+		tk.MustQuery(fmt.Sprintf("select tidb_decode_row('test.t', '%X')", value)).Check(testkit.Rows(expected))
+		tk.MustQuery(fmt.Sprintf("select tidb_decode_row('t', '%x')", value)).Check(testkit.Rows(expected))
+	}
+	tk.MustQuery("select tidb_decode_row('t', null)").Check(testkit.Rows("<nil>"))
+
+	tk.MustQuery("select tidb_decode_row('t', 'not a hex value')").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1210 invalid row value: 'not a hex value'"))
+	tk.MustQuery("select tidb_decode_row('t', '0102')").Check(testkit.Rows("<nil>"))
+	require.Len(t, tk.Session().GetSessionVars().StmtCtx.GetWarnings(), 1)
+	require.EqualError(t, tk.QueryToErr("select tidb_decode_row('t_not_exists', '00')"),
+		"[schema:1146]Table 'test.t_not_exists' doesn't exist")
+
+	tk.MustExec("create user 'decode_row'@'%'")
+	tk2 := testkit.NewTestKit(t, store)
+	require.True(t, tk2.Session().Auth(&auth.UserIdentity{Username: "decode_row", Hostname: "%"}, nil, nil))
+	require.EqualError(t, tk2.QueryToErr(fmt.Sprintf("select tidb_decode_row('test.t', '%X')", newRow)),
+		"[expression:1142]SELECT command denied to user 'decode_row'@'%' for table 't'")
+	tk.MustExec("grant select on test.t to 'decode_row'@'%'")
+	tk2.MustQuery(fmt.Sprintf("select tidb_decode_row('test.t', '%X')", newRow)).Check(testkit.Rows(expected))
+}
+
+func TestTiDBDecodeBase64Row(t *testing.T) {
+	t.Parallel()
+
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int primary key, b varchar(10), c double)")
+	tbl, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	colIDs := []int64{tbl.Meta().Columns[1].ID, tbl.Meta().Columns[2].ID}
+	sc := tk.Session().GetSessionVars().StmtCtx
+	value, err := tablecodec.EncodeRow(sc, types.MakeDatums("abc", 1.5), colIDs, nil, nil, &rowcodec.Encoder{})
+	require.NoError(t, err)
+	encoded := base64.StdEncoding.EncodeToString(value)
+	value, err = tablecodec.EncodeRow(sc, types.MakeDatums(nil, -2.25), colIDs, nil, nil, &rowcodec.Encoder{})
+	require.NoError(t, err)
+	encodedWithNull := base64.StdEncoding.EncodeToString(value)
+
+	tk.MustQuery(fmt.Sprintf("select tidb_decode_base64_row('test.t', '%s')", encoded)).Check(testkit.Rows(`{"b": "abc", "c": "1.5"}`))
+	tk.MustQuery(fmt.Sprintf("select tidb_decode_base64_row('test.t', '%s')", encodedWithNull)).Check(testkit.Rows(`{"b": null, "c": "-2.25"}`))
+	tk.MustExec("create table rows_base64(id int, v varchar(100))")
+	tk.MustExec(fmt.Sprintf("insert into rows_base64 values (1, '%s'), (2, '%s')", encoded, encodedWithNull))
+	tk.MustQuery("select id, json_unquote(json_extract(tidb_decode_base64_row('test.t', v), '$.b')), json_unquote(json_extract(tidb_decode_base64_row('test.t', v), '$.c')) from rows_base64 order by id").
+		Check(testkit.Rows("1 abc 1.5", "2 null -2.25"))
+	tk.MustQuery("select tidb_decode_base64_row('t', null)").Check(testkit.Rows("<nil>"))
+
+	tk.MustQuery("select tidb_decode_base64_row('t', 'not base64!')").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1210 invalid base64 row value: 'not base64!'"))
+	tk.MustQuery("select tidb_decode_base64_row('t', 'AQI=')").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 decode row value 'AQI=' of table t failed with error: insufficient bytes to decode value"))
+
+	tk.MustExec("create user 'decode_base64_row'@'%'")
+	tk2 := testkit.NewTestKit(t, store)
+	require.True(t, tk2.Session().Auth(&auth.UserIdentity{Username: "decode_base64_row", Hostname: "%"}, nil, nil))
+	require.EqualError(t, tk2.QueryToErr(fmt.Sprintf("select tidb_decode_base64_row('test.t', '%s')", encoded)),
+		"[expression:1142]SELECT command denied to user 'decode_base64_row'@'%' for table 't'")
+	require.EqualError(t, tk2.QueryToErr(fmt.Sprintf("select tidb_decode_base64_row('test.t_not_exists', '%s')", encoded)),
+		"[expression:1142]SELECT command denied to user 'decode_base64_row'@'%' for table 't_not_exists'")
+}
+
+func TestTiDBDecodeIndexValue(t *testing.T) {
+	t.Parallel()
+
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int primary key, b int, c varchar(10), unique key uk(b), key k(c))")
+	tk.MustExec("create table t_clustered(a varchar(10) primary key clustered, b int, unique key uk(b), key k(b))")
+	is := dom.InfoSchema()
+	tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	tblInfo := tbl.Meta()
+	sc := tk.Session().GetSessionVars().StmtCtx
+	genIndexValue := func(tblInfo *model.TableInfo, idxName string, needRestoredData, untouched bool, h kv.Handle, values ...interface{}) string {
+		idxInfo := tblInfo.FindIndexByName(idxName)
+		val, err := tablecodec.GenIndexValuePortal(sc, tblInfo, idxInfo, needRestoredData, idxInfo.Unique, untouched, types.MakeDatums(values...), h, 0, nil)
+		require.NoError(t, err)
+		return fmt.Sprintf("%X", val)
+	}
+
+	// Unique index stores the handle in the value.
+	tk.MustQuery(fmt.Sprintf("select tidb_decode_index_value('test.t', 'uk', '%s')", genIndexValue(tblInfo, "uk", false, false, kv.IntHandle(5), 1))).
+		Check(testkit.Rows(`{"handle": 5, "untouched": false}`))
+	tk.MustQuery(fmt.Sprintf("select tidb_decode_index_value('t', 'uk', '%s')", genIndexValue(tblInfo, "uk", false, true, kv.IntHandle(5), 1))).
+		Check(testkit.Rows(`{"handle": 5, "untouched": true}`))
+	tk.MustQuery(fmt.Sprintf("select tidb_decode_index_value('t', 'uk', '%s')", genIndexValue(tblInfo, "uk", true, false, kv.IntHandle(5), 1))).
+		Check(testkit.Rows(`{"handle": 5, "restored_data": "80000100000002010001", "untouched": false}`))
+	// Non-unique index stores the handle in the key.
+	tk.MustQuery(fmt.Sprintf("select tidb_decode_index_value('t', 'k', '%s')", genIndexValue(tblInfo, "k", false, false, kv.IntHandle(5), "abc"))).
+		Check(testkit.Rows(`{"untouched": false}`))
+	tk.MustQuery(fmt.Sprintf("select tidb_decode_index_value('t', 'k', '%s')", genIndexValue(tblInfo, "k", true, true, kv.IntHandle(5), "abc"))).
+		Check(testkit.Rows(`{"restored_data": "800001000000030300616263", "untouched": true}`))
+	// Unique index of a clustered table stores the common handle in the value, which is in the version 1 layout.
+	tbl, err = is.TableByName(model.NewCIStr("test"), model.NewCIStr("t_clustered"))
+	require.NoError(t, err)
+	require.Equal(t, uint16(1), tbl.Meta().CommonHandleVersion)
+	encoded, err := codec.EncodeKey(sc, nil, types.NewStringDatum("abc"))
+	require.NoError(t, err)
+	h, err := kv.NewCommonHandle(encoded)
+	require.NoError(t, err)
+	tk.MustQuery(fmt.Sprintf("select tidb_decode_index_value('t_clustered', 'uk', '%s')", genIndexValue(tbl.Meta(), "uk", false, false, h, 1))).
+		Check(testkit.Rows(`{"handle": "{abc}", "untouched": false}`))
+	tk.MustQuery(fmt.Sprintf("select tidb_decode_index_value('t_clustered', 'uk', '%s')", genIndexValue(tbl.Meta(), "uk", false, true, h, 1))).
+		Check(testkit.Rows(`{"handle": "{abc}", "untouched": true}`))
+	tk.MustQuery(fmt.Sprintf("select tidb_decode_index_value('t_clustered', 'k', '%s')", genIndexValue(tbl.Meta(), "k", true, false, h, 1))).
+		Check(testkit.Rows(`{"restored_data": "800000000000", "untouched": false}`))
+	tk.MustQuery("select tidb_decode_index_value('t', 'uk', null)").Check(testkit.Rows("<nil>"))
+
+	tk.MustQuery("select tidb_decode_index_value('t', 'uk', '0102')").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 decode index value '0102' of index uk failed with error: invalid index value"))
+	tk.MustQuery("select tidb_decode_index_value('t', 'uk', 'not a hex value')").Check(testkit.Rows("<nil>"))
+	require.Len(t, tk.Session().GetSessionVars().StmtCtx.GetWarnings(), 1)
+	require.EqualError(t, tk.QueryToErr("select tidb_decode_index_value('t', 'idx_x', '00')"), "index idx_x doesn't exist in table t")
+
+	tk.MustExec("create user 'decode_index_value'@'%'")
+	tk2 := testkit.NewTestKit(t, store)
+	require.True(t, tk2.Session().Auth(&auth.UserIdentity{Username: "decode_index_value", Hostname: "%"}, nil, nil))
+	require.EqualError(t, tk2.QueryToErr("select tidb_decode_index_value('test.t', 'uk', '30')"),
+		"[expression:1142]SELECT command denied to user 'decode_index_value'@'%' for table 't'")
+	require.EqualError(t, tk2.QueryToErr("select tidb_decode_index_value('test.t_not_exists', 'uk', '30')"),
+		"[expression:1142]SELECT command denied to user 'decode_index_value'@'%' for table 't_not_exists'")
+	tk.MustExec("grant select on test.t to 'decode_index_value'@'%'")
+	tk2.MustQuery("select tidb_decode_index_value('test.t', 'k', '30')").Check(testkit.Rows(`{"untouched": false}`))
+}
+
+func TestTiDBCurrentStmtType(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int primary key, b varchar(32))")
+	tk.MustQuery("select tidb_current_stmt_type()").Check(testkit.Rows("Select"))
+	tk.MustExec("insert into t values (1, tidb_current_stmt_type())")
+	tk.MustExec("replace into t values (2, tidb_current_stmt_type())")
+	tk.MustQuery("select b from t order by a").Check(testkit.Rows("Insert", "Replace"))
+	tk.MustExec("update t set b = tidb_current_stmt_type() where a = 1")
+	tk.MustQuery("select b from t where a = 1").Check(testkit.Rows("Update"))
+	tk.MustExec("delete from t where tidb_current_stmt_type() = 'Delete' and a = 2")
+	tk.MustQuery("select a from t").Check(testkit.Rows("1"))
+	tk.MustExec("set @stmt_type = tidb_current_stmt_type()")
+	tk.MustQuery("select @stmt_type").Check(testkit.Rows("Set"))
+	tk.MustExec("prepare stmt from 'select tidb_current_stmt_type()'")
+	tk.MustQuery("execute stmt").Check(testkit.Rows("Select"))
+	tk.MustExec("do @stmt_type := tidb_current_stmt_type()")
+	tk.MustQuery("select @stmt_type").Check(testkit.Rows(""))
+}
+
+func TestTiDBDecodeKeyRange(t *testing.T) {
+	t.Parallel()
+
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1(a int primary key, b int, index idx(b))")
+	tk.MustExec("create table t2(a int primary key)")
+	is := dom.InfoSchema()
+	t1, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t1"))
+	require.NoError(t, err)
+	t2, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t2"))
+	require.NoError(t, err)
+	t1Start := []byte(tablecodec.EncodeRowKeyWithHandle(t1.Meta().ID, kv.IntHandle(1)))
+	t1End := []byte(tablecodec.EncodeRowKeyWithHandle(t1.Meta().ID, kv.IntHandle(100)))
+	t2End := []byte(tablecodec.EncodeRowKeyWithHandle(t2.Meta().ID, kv.IntHandle(10)))
+
+	tk.MustQuery(fmt.Sprintf("select tidb_decode_key_range('%X', '%X')", t1Start, t1End)).Check(testkit.Rows(
+		fmt.Sprintf(`{"end": {"handle": 100, "table_id": %[1]d}, "kind": "record", "start": {"handle": 1, "table_id": %[1]d}, "table": "t1"}`, t1.Meta().ID)))
+	tk.MustQuery(fmt.Sprintf("select tidb_decode_key_range('%X', '%X')", t1Start, t2End)).Check(testkit.Rows(
+		fmt.Sprintf(`{"end": {"handle": 10, "table_id": %d}, "end_table": "t2", "start": {"handle": 1, "table_id": %d}, "start_table": "t1"}`, t2.Meta().ID, t1.Meta().ID)))
+	tk.MustQuery(fmt.Sprintf("select tidb_decode_key_range('%X', '')", t1Start)).Check(testkit.Rows(
+		fmt.Sprintf(`{"end": null, "start": {"handle": 1, "table_id": %d}, "table": "t1"}`, t1.Meta().ID)))
+	tk.MustQuery(fmt.Sprintf("select tidb_decode_key_range('', '%X')", []byte(tablecodec.EncodeTablePrefix(t2.Meta().ID)))).Check(testkit.Rows(
+		fmt.Sprintf(`{"end": {"table_id": %d}, "start": null, "table": "t2"}`, t2.Meta().ID)))
+	tk.MustQuery(fmt.Sprintf("select tidb_decode_key_range('%X', '%X')",
+		[]byte(tablecodec.EncodeTablePrefix(t1.Meta().ID)), []byte(tablecodec.EncodeTablePrefix(t1.Meta().ID+1)))).Check(testkit.Rows(
+		fmt.Sprintf(`{"end": {"table_id": %d}, "kind": "table", "start": {"table_id": %d}, "table": "t1"}`, t1.Meta().ID+1, t1.Meta().ID)))
+	idxStart, err := codec.EncodeKey(tk.Session().GetSessionVars().StmtCtx, nil, types.NewIntDatum(1))
+	require.NoError(t, err)
+	idxEnd, err := codec.EncodeKey(tk.Session().GetSessionVars().StmtCtx, nil, types.NewIntDatum(10))
+	require.NoError(t, err)
+	idxID := t1.Meta().Indices[0].ID
+	tk.MustQuery(fmt.Sprintf("select tidb_decode_key_range('%X', '%X')",
+		[]byte(tablecodec.EncodeIndexSeekKey(t1.Meta().ID, idxID, idxStart)), []byte(tablecodec.EncodeIndexSeekKey(t1.Meta().ID, idxID, idxEnd)))).Check(testkit.Rows(
+		fmt.Sprintf(`{"end": {"index_id": %[2]d, "index_values": {"b": "10"}, "table_id": %[1]d}, "kind": "index", "start": {"index_id": %[2]d, "index_values": {"b": "1"}, "table_id": %[1]d}, "table": "t1"}`, t1.Meta().ID, idxID)))
+
+	tk.MustQuery("select tidb_decode_key_range('abc', '')").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("show warnings").Check(testkit.Rows(
+		"Warning 1105 invalid record/index key: AB",
+		"Warning 1210 invalid key range: ['abc', '')"))
+	tk.MustQuery("select tidb_decode_key_range(null, '')").Check(testkit.Rows("<nil>"))
+}
+
+func TestTiDBDecodeMetaKey(t *testing.T) {
+	t.Parallel()
+
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int)")
+	is := dom.InfoSchema()
+	db, ok := is.SchemaByName(model.NewCIStr("test"))
+	require.True(t, ok)
+	tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	txStruct := structure.NewStructure(nil, nil, []byte("m"))
+	dbKey := []byte(txStruct.EncodeHashDataKey([]byte("DBs"), []byte(fmt.Sprintf("DB:%d", db.ID))))
+	tblKey := []byte(txStruct.EncodeHashDataKey([]byte(fmt.Sprintf("DB:%d", db.ID)), []byte(fmt.Sprintf("Table:%d", tbl.Meta().ID))))
+
+	tk.MustQuery(fmt.Sprintf("select tidb_decode_meta_key('%X')", dbKey)).Check(testkit.Rows(
+		fmt.Sprintf(`{"db_id": %d, "type": "DB"}`, db.ID)))
+	tk.MustQuery(fmt.Sprintf("select tidb_decode_meta_key('%X')", tblKey)).Check(testkit.Rows(
+		fmt.Sprintf(`{"db_id": %d, "table_id": %d, "type": "Table"}`, db.ID, tbl.Meta().ID)))
+	// The memcomparable-encoded key is decoded automatically.
+	tk.MustQuery(fmt.Sprintf("select tidb_decode_meta_key('%X')", codec.EncodeBytes(nil, tblKey))).Check(testkit.Rows(
+		fmt.Sprintf(`{"db_id": %d, "table_id": %d, "type": "Table"}`, db.ID, tbl.Meta().ID)))
+
+	recordKey := []byte(tablecodec.EncodeRowKeyWithHandle(tbl.Meta().ID, kv.IntHandle(1)))
+	tk.MustQuery(fmt.Sprintf("select tidb_decode_meta_key('%X')", recordKey)).Check(testkit.Rows("<nil>"))
+	tk.MustQuery("show warnings").Check(testkit.Rows(fmt.Sprintf("Warning 1210 invalid meta key: '%X'", recordKey)))
+	tk.MustQuery("select tidb_decode_meta_key('xyz')").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1210 invalid key: 'xyz'"))
+	tk.MustQuery("select tidb_decode_meta_key(null)").Check(testkit.Rows("<nil>"))
+}
+
+func TestTiDBCurrentIsolationLevel(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustQuery("select tidb_current_isolation_level()").Check(testkit.Rows("REPEATABLE-READ"))
+	tk.MustExec("set session transaction isolation level read committed")
+	tk.MustQuery("select tidb_current_isolation_level()").Check(testkit.Rows("READ-COMMITTED"))
+
+	// The level set without the session scope only takes effect for the next transaction.
+	tk.MustExec("set transaction isolation level repeatable read")
+	tk.MustQuery("select tidb_current_isolation_level()").Check(testkit.Rows("REPEATABLE-READ"))
+	tk.MustQuery("select tidb_current_isolation_level()").Check(testkit.Rows("READ-COMMITTED"))
+
+	tk.MustExec("set transaction isolation level repeatable read")
+	tk.MustExec("begin")
+	tk.MustQuery("select tidb_current_isolation_level()").Check(testkit.Rows("REPEATABLE-READ"))
+	tk.MustQuery("select tidb_current_isolation_level()").Check(testkit.Rows("REPEATABLE-READ"))
+	tk.MustExec("commit")
+	tk.MustQuery("select tidb_current_isolation_level()").Check(testkit.Rows("READ-COMMITTED"))
+}
+
+func TestTiDBCurrentTxnID(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int)")
+	// An auto-committed statement isn't in an active transaction.
+	tk.MustQuery("select tidb_current_txn_id()").Check(testkit.Rows("<nil>"))
+
+	for _, begin := range []string{"begin optimistic", "begin pessimistic", "start transaction with consistent snapshot"} {
+		tk.MustExec(begin)
+		tk.MustExec("insert into t values (1)")
+		txnID := fmt.Sprint(tk.Session().GetSessionVars().TxnCtx.StartTS)
+		tk.MustQuery("select tidb_current_txn_id(), @@tidb_current_ts").Check(testkit.Rows(txnID + " " + txnID))
+		tk.MustQuery("select tidb_current_txn_id()").Check(testkit.Rows(txnID))
+		tk.MustExec("commit")
+		tk.MustQuery("select tidb_current_txn_id()").Check(testkit.Rows("<nil>"))
+	}
+
+	tk.MustExec("set autocommit = 0")
+	tk.MustExec("insert into t values (1)")
+	tk.MustQuery("select tidb_current_txn_id() = @@tidb_current_ts").Check(testkit.Rows("1"))
+	tk.MustExec("rollback")
+	tk.MustExec("set autocommit = 1")
+	tk.MustQuery("select tidb_current_txn_id()").Check(testkit.Rows("<nil>"))
+}
+
+func TestTiDBEstimateIndexSelectivity(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, index ia(a), index iab(a, b))")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3), (4, 4), (5, 5), (6, 6), (7, 7), (8, 8), (9, 9), (10, 10)")
+	tk.MustExec("analyze table t")
+
+	tk.MustQuery("select tidb_estimate_index_selectivity('test.t', 'ia', 'a > 8')").Check(testkit.Rows("0.2"))
+	tk.MustQuery("select tidb_estimate_index_selectivity('t', 'ia', 'a > 0')").Check(testkit.Rows("1"))
+	tk.MustQuery("select tidb_estimate_index_selectivity('t', 'ia', 'b < 3')").Check(testkit.Rows("1"))
+	tk.MustQuery("select tidb_estimate_index_selectivity('t', 'ia', 'a = 5 and b = 5')").Check(testkit.Rows("0.1"))
+	tk.MustQuery("select tidb_estimate_index_selectivity('t', 'iab', 'a = 5 and b = 5')").Check(testkit.Rows("0.1"))
+
+	tk.MustQuery("select tidb_estimate_index_selectivity('t', 'ia', 'a >')").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 invalid predicate: 'a >'"))
+	tk.MustQuery("select tidb_estimate_index_selectivity('t', 'ia', 'c > 1')").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1054 Unknown column 'c' in 'where clause'"))
+	tk.MustQuery("select tidb_estimate_index_selectivity('t', 'ia', null)").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("select tidb_estimate_index_selectivity('t', 'ia', 'a in (select a from t)')").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 invalid predicate: 'a in (select a from t)', only columns and constants are allowed"))
+	tk.MustQuery("select tidb_estimate_index_selectivity('t', 'ia', 'a > (select max(a) from t)')").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 invalid predicate: 'a > (select max(a) from t)', only columns and constants are allowed"))
+	tk.MustQuery("select tidb_estimate_index_selectivity('t', 'ia', 'a > abs(-8)')").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 invalid predicate: 'a > abs(-8)', only columns and constants are allowed"))
+	tk.MustQuery("select tidb_estimate_index_selectivity('t', 'ia', 'a in (9, 10) or a is null')").Check(testkit.Rows("0.2"))
+	require.EqualError(t, tk.QueryToErr("select tidb_estimate_index_selectivity('t', 'ix', 'a > 1')"),
+		"[expression:1105]index ix doesn't exist in table t")
+	require.EqualError(t, tk.QueryToErr("select tidb_estimate_index_selectivity('t_not_exists', 'ia', 'a > 1')"),
+		"[schema:1146]Table 'test.t_not_exists' doesn't exist")
+
+	tk.MustExec("create user 'estimate_selectivity'@'%'")
+	tk2 := testkit.NewTestKit(t, store)
+	require.True(t, tk2.Session().Auth(&auth.UserIdentity{Username: "estimate_selectivity", Hostname: "%"}, nil, nil))
+	require.EqualError(t, tk2.QueryToErr("select tidb_estimate_index_selectivity('test.t', 'ia', 'a > 1')"),
+		"[expression:1142]SELECT command denied to user 'estimate_selectivity'@'%' for table 't'")
+	// The existence of the table is not leaked to the user without the privilege.
+	require.EqualError(t, tk2.QueryToErr("select tidb_estimate_index_selectivity('test.t_not_exists', 'ia', 'a > 1')"),
+		"[expression:1142]SELECT command denied to user 'estimate_selectivity'@'%' for table 't_not_exists'")
+
+	// The subquery on the table without the privilege is rejected.
+	tk.MustExec("create table secret(a int)")
+	tk.MustExec("grant select on test.t to 'estimate_selectivity'@'%'")
+	tk2.MustQuery("select tidb_estimate_index_selectivity('test.t', 'ia', 'a > (select max(a) from test.secret)')").Check(testkit.Rows("<nil>"))
+	tk2.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 invalid predicate: 'a > (select max(a) from test.secret)', only columns and constants are allowed"))
+	// Only the expression is taken from the predicate, the clauses injected after it are rejected.
+	for _, pred := range []string{
+		"1) ORDER BY ((select max(a) from test.secret)",
+		"1) GROUP BY a HAVING ((select max(a) from test.secret) > 0",
+		"1 ORDER BY (select max(a) from test.secret)",
+		"1 FROM test.t GROUP BY a HAVING (select max(a) from test.secret) > 0",
+		"a > 1 LIMIT 1",
+		"a > 1 FOR UPDATE",
+		"a > 1, (select max(a) from test.secret)",
+	} {
+		tk2.MustQuery(fmt.Sprintf(`select tidb_estimate_index_selectivity('test.t', 'ia', "%s")`, pred)).Check(testkit.Rows("<nil>"))
+		tk2.MustQuery("show warnings").Check(testkit.Rows(fmt.Sprintf("Warning 1105 invalid predicate: '%s'", pred)))
+	}
+	tk2.MustQuery("select tidb_estimate_index_selectivity('test.t', 'ia', 'a > 8')").Check(testkit.Rows("0.2"))
+
+	// The statement context of the running statement is kept.
+	tk.MustQuery("select /*+ max_execution_time(1000) */ tidb_estimate_index_selectivity('t', 'ia', 'a > 8')").Check(testkit.Rows("0.2"))
+	require.Equal(t, uint64(1000), tk.Session().GetSessionVars().StmtCtx.MaxExecutionTime)
+}
+
+func TestTiDBTraceID(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustQuery("select tidb_trace_id()").Check(testkit.Rows(""))
+
+	tracer := basictracer.New(basictracer.NewInMemoryRecorder())
+	span := tracer.StartSpan("test")
+	defer span.Finish()
+	ctx := opentracing.ContextWithSpan(context.Background(), span)
+	rs, err := tk.Session().Execute(ctx, "select tidb_trace_id()")
+	require.NoError(t, err)
+	traceID := fmt.Sprintf("%016x", span.Context().(basictracer.SpanContext).TraceID)
+	require.NotEmpty(t, traceID)
+	tk.ResultSetToResultWithCtx(ctx, rs[0], "select tidb_trace_id()").Check(testkit.Rows(traceID))
+
+	// The trace id is only visible to the traced statement.
+	tk.MustQuery("select tidb_trace_id()").Check(testkit.Rows(""))
+}
+
+func TestTiDBCurrentBatchSize(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustQuery("select tidb_current_batch_size()").Check(testkit.Rows("1024"))
+	tk.MustExec("set @@tidb_max_chunk_size = 64")
+	tk.MustQuery("select tidb_current_batch_size()").Check(testkit.Rows("64"))
+	tk.MustExec("set @@tidb_max_chunk_size = default")
+	tk.MustQuery("select tidb_current_batch_size()").Check(testkit.Rows("1024"))
+}
+
+func TestTiDBCurrentSQLDigestText(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b varchar(10))")
+	tk.MustExec("insert into t values (1, 'a')")
+	for _, sql := range []string{
+		"select tidb_current_sql_digest_text()",
+		"select tidb_current_sql_digest_text() from t where a = 1 and b in ('a', 'b')",
+		"SELECT  TIDB_CURRENT_SQL_DIGEST_TEXT() FROM t WHERE a > 0 LIMIT 10",
+	} {
+		rows := tk.MustQuery(sql).Rows()
+		require.Len(t, rows, 1)
+		require.Equal(t, parser.Normalize(sql), rows[0][0])
+	}
+}
+
+func TestTiDBEncodeSQLDigest(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b varchar(10))")
+	// The statements summary is only recorded for an authenticated user.
+	require.True(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil))
+
+	sql := "select b from t where a = 1 and b in ('a', 'b')"
+	tk.MustQuery(sql).Check(testkit.Rows())
+	rows := tk.MustQuery("select digest from information_schema.statements_summary where query_sample_text = ?", sql).Rows()
+	require.Len(t, rows, 1)
+	tk.MustQuery("select tidb_encode_sql_digest(?)", sql).Check(testkit.Rows(rows[0][0].(string)))
+	// The digest of a statement differing only in the literals is the same.
+	tk.MustQuery("select tidb_encode_sql_digest(?)", "SELECT b FROM t WHERE a = 2 AND b IN ('c', 'd', 'e')").Check(testkit.Rows(rows[0][0].(string)))
+	tk.MustQuery("select tidb_decode_sql_digests(concat('[\"', tidb_encode_sql_digest(?), '\"]'))", sql).Check(testkit.Rows(
+		"[\"select `b` from `t` where `a` = ? and `b` in ( ... )\"]"))
+	tk.MustQuery("select tidb_encode_sql_digest(null)").Check(testkit.Rows("<nil>"))
+}
+
+// keyspaceStore is a storage which belongs to a keyspace.
+type keyspaceStore struct {
+	kv.Storage
+	keyspaceID uint32
+}
+
+func (s *keyspaceStore) GetKeyspaceID() uint32 {
+	return s.keyspaceID
+}
+
+func TestTiDBKeyspaceID(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	// The keyspace is disabled.
+	tk := testkit.NewTestKit(t, store)
+	tk.MustQuery("select tidb_keyspace_id()").Check(testkit.Rows("<nil>"))
+
+	// The keyspace is enabled.
+	se, err := session.CreateSession(&keyspaceStore{Storage: store, keyspaceID: 4096})
+	require.NoError(t, err)
+	tk.SetSession(se)
+	tk.MustQuery("select tidb_keyspace_id()").Check(testkit.Rows("4096"))
+}
+
+func TestTiDBMVCCInfo(t *testing.T) {
+	t.Parallel()
+
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int primary key, b int)")
+	tk.MustExec("insert into t values (1, 1)")
+	tbl, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	key := fmt.Sprintf("%X", []byte(tablecodec.EncodeRowKeyWithHandle(tbl.Meta().ID, kv.IntHandle(1))))
+	tk.MustQuery("select json_extract(tidb_mvcc_info(?), '$.key', '$.lock', '$.write[0].type')", key).Check(testkit.Rows(
+		fmt.Sprintf(`["%s", null, "Put"]`, key)))
+	tk.MustQuery("select json_length(json_extract(tidb_mvcc_info(?), '$.write'))", key).Check(testkit.Rows("1"))
+	tk.MustExec("update t set b = 2 where a = 1")
+	tk.MustQuery("select json_length(json_extract(tidb_mvcc_info(?), '$.write'))", key).Check(testkit.Rows("2"))
+
+	tk.MustQuery("select tidb_mvcc_info('abc')").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1210 invalid key: 'abc'"))
+
+	// The PROCESS privilege is required.
+	tk.MustExec("create user 'mvcc_info_user'@'%'")
+	defer tk.MustExec("drop user 'mvcc_info_user'@'%'")
+	tk2 := testkit.NewTestKit(t, store)
+	require.True(t, tk2.Session().Auth(&auth.UserIdentity{Username: "mvcc_info_user", Hostname: "%"}, nil, nil))
+	_, err = tk2.Exec("select tidb_mvcc_info(?)", key)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "you need (at least one of) the PROCESS privilege(s) for this operation")
+}
+
+func TestTiDBMVCCInfoPlanCache(t *testing.T) {
+	t.Parallel()
+
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	orgEnable := plannercore.PreparedPlanCacheEnabled()
+	defer func() {
+		plannercore.SetPreparedPlanCache(orgEnable)
+	}()
+	plannercore.SetPreparedPlanCache(true)
+	se, err := session.CreateSession4TestWithOpt(store, &session.Opt{
+		PreparedPlanCache: kvcache.NewSimpleLRUCache(100, 0.1, math.MaxUint64),
+	})
+	require.NoError(t, err)
+	tk.SetSession(se)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int primary key, b int)")
+	tk.MustExec("insert into t values (1, 1)")
+	tbl, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	key := fmt.Sprintf("%X", []byte(tablecodec.EncodeRowKeyWithHandle(tbl.Meta().ID, kv.IntHandle(1))))
+	tk.MustExec(fmt.Sprintf(`prepare stmt from 'select json_length(json_extract(tidb_mvcc_info("%s"), "$.write"))'`, key))
+	tk.MustQuery("execute stmt").Check(testkit.Rows("1"))
+	tk.MustExec("update t set b = 2 where a = 1")
+	tk.MustQuery("execute stmt").Check(testkit.Rows("2"))
+}
+
+func TestTiDBPlanDigest(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, key(a))")
+	tk.MustExec("insert into t values (1, 1), (2, 2)")
+	// The statements summary is only recorded for an authenticated user.
+	require.True(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil))
+
+	rows := tk.MustQuery("select tidb_plan_digest() from t where a > 1").Rows()
+	require.Len(t, rows, 1)
+	digest := rows[0][0].(string)
+	require.NotEmpty(t, digest)
+	tk.MustQuery("select plan_digest from information_schema.statements_summary where query_sample_text = 'select tidb_plan_digest() from t where a > 1'").
+		Check(testkit.Rows(digest))
+
+	// A different plan has a different digest.
+	rows = tk.MustQuery("select tidb_plan_digest() from t use index() where a > 1").Rows()
+	require.Len(t, rows, 1)
+	require.NotEqual(t, digest, rows[0][0])
+	// The digest is read from the statement context of the executing statement.
+	tk.MustQuery("select tidb_plan_digest() from t where a > 1").Check(testkit.Rows(digest))
+}
+
 func TestTiDBInternalFunc(t *testing.T) {
 	t.Parallel()
 
@@ -4228,6 +5061,7 @@ func TestIssue15346(t *testing.T) {
 	tk := testkit.NewTestKit(t, store)
 	tk.MustExec("use test")
 	tk.MustQuery("select collation(format_bytes(1024)) != 'binary';").Check(testkit.Rows("1"))
+	tk.MustQuery("select collation(format_bytes(1024, 3)) != 'binary';").Check(testkit.Rows("1"))
 	tk.MustQuery("select collation(format_nano_time(234)) != 'binary';").Check(testkit.Rows("1"))
 }
 
@@ -4295,6 +5129,36 @@ func TestSelectLimitPlanCache(t *testing.T) {
 	tk.MustQuery("execute stmt").Check(testkit.Rows("1", "2"))
 }
 
+func TestCurrentRoleJSONPlanCache(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create user 'role_plan_cache'@'%'")
+	tk.MustExec("create role r1, r2")
+	tk.MustExec("grant r1, r2 to 'role_plan_cache'@'%'")
+
+	orgEnable := plannercore.PreparedPlanCacheEnabled()
+	defer func() {
+		plannercore.SetPreparedPlanCache(orgEnable)
+	}()
+	plannercore.SetPreparedPlanCache(true)
+	se, err := session.CreateSession4TestWithOpt(store, &session.Opt{
+		PreparedPlanCache: kvcache.NewSimpleLRUCache(100, 0.1, math.MaxUint64),
+	})
+	require.NoError(t, err)
+	require.True(t, se.Auth(&auth.UserIdentity{Username: "role_plan_cache", Hostname: "%"}, nil, nil))
+	tk2 := testkit.NewTestKit(t, store)
+	tk2.SetSession(se)
+	tk2.MustExec("set role r1")
+	tk2.MustExec("prepare s2 from 'select current_role_json()'")
+	tk2.MustQuery("execute s2").Check(testkit.Rows("[\"`r1`@`%`\"]"))
+	tk2.MustExec("set role r2")
+	tk2.MustQuery("execute s2").Check(testkit.Rows("[\"`r2`@`%`\"]"))
+}
+
 func TestCollation(t *testing.T) {
 	t.Parallel()
 
@@ -4347,6 +5211,20 @@ func TestCollation(t *testing.T) {
 	tk.MustExec("set names utf8mb4 collate utf8mb4_general_ci")
 	tk.MustQuery("select collation('a')").Check(testkit.Rows("utf8mb4_general_ci"))
 
+	// An explicit COLLATE clause overrides the collation of its argument.
+	tk.MustQuery("select collation('a' collate utf8mb4_bin)").Check(testkit.Rows("utf8mb4_bin"))
+	tk.MustQuery("select collation(_utf8mb4'a' collate utf8mb4_unicode_ci)").Check(testkit.Rows("utf8mb4_unicode_ci"))
+	tk.MustQuery("select collation(_latin1'a' collate latin1_bin)").Check(testkit.Rows("latin1_bin"))
+	tk.MustQuery("select collation(('a' collate utf8mb4_bin))").Check(testkit.Rows("utf8mb4_bin"))
+	tk.MustQuery("select collation(concat('a', 'b') collate utf8mb4_bin)").Check(testkit.Rows("utf8mb4_bin"))
+	tk.MustQuery("select collation(upper('a' collate utf8mb4_bin))").Check(testkit.Rows("utf8mb4_bin"))
+	tk.MustQuery("select collation(concat('a' collate utf8mb4_bin, 'b'))").Check(testkit.Rows("utf8mb4_bin"))
+	tk.MustQuery("select collation(utf8_gen_c collate utf8_bin), collation(utf8_gen_c) from t").Check(testkit.Rows("utf8_bin utf8_general_ci"))
+	tk.MustQuery("select collation(concat(u4ci, 'a') collate utf8mb4_bin) from t").Check(testkit.Rows("utf8mb4_bin"))
+	tk.MustExec("prepare stmt from 'select collation(? collate utf8mb4_bin)'")
+	tk.MustExec("set @a = 'a'")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("utf8mb4_bin"))
+
 	tk.MustExec("set names utf8mb4 collate utf8mb4_general_ci")
 	tk.MustExec("set @test_collate_var = 'a'")
 	tk.MustQuery("select collation(@test_collate_var)").Check(testkit.Rows("utf8mb4_general_ci"))
@@ -4386,6 +5264,7 @@ func TestCoercibility(t *testing.T) {
 		// sys-constants
 		{"version()", 3}, {"user()", 3}, {"database()", 3},
 		{"current_role()", 3}, {"current_user()", 3},
+		{"schema()", 3}, {"session_user()", 3}, {"system_user()", 3}, {"collation('abc')", 3},
 		// scalar functions after constant folding
 		{"1+null", 5}, {"null+'abcde'", 5}, {"concat(null, 'abcde')", 4},
 		// non-deterministic functions
@@ -7257,3 +8136,138 @@ func TestIssue30326(t *testing.T) {
 	err := tk.QueryToErr("select (FIRST_VALUE(1) over (partition by v.a)) as c3 from (select a from t where t.a = (select a from t t2 where t.a = t2.a)) as v;")
 	require.Error(t, err, "[executor:1242]Subquery returns more than 1 row")
 }
+
+func TestTiDBDecodeAutoRandom(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, t1, t2")
+	tk.MustExec("create table t (a bigint primary key auto_random(5), b int)")
+	tk.MustExec("set @@allow_auto_random_explicit_insert = 1")
+	tk.MustExec("insert into t values ((3 << 58) | 1024, 0)")
+	tk.MustQuery("select tidb_decode_auto_random('test.t', a) from t").Check(testkit.Rows(`{"seq": 1024, "shard": 3}`))
+	tk.MustQuery("select tidb_decode_auto_random('t', (31 << 58) | 7)").Check(testkit.Rows(`{"seq": 7, "shard": 31}`))
+	tk.MustQuery("select tidb_decode_auto_random('t', null)").Check(testkit.Rows("<nil>"))
+
+	// The decoded parts must invert the layout of the generated values.
+	tk.MustExec("delete from t")
+	tk.MustExec("insert into t(b) values (1), (2), (3), (4), (5)")
+	for _, vec := range []string{"on", "off"} {
+		tk.MustExec("set @@tidb_enable_vectorized_expression = " + vec)
+		tk.MustQuery("select count(*) from t where " +
+			"json_extract(tidb_decode_auto_random('test.t', a), '$.shard') = a >> 58 and " +
+			"json_extract(tidb_decode_auto_random('test.t', a), '$.seq') = a & ((1 << 58) - 1)").Check(testkit.Rows("5"))
+	}
+
+	tk.MustExec("create table t1 (a bigint unsigned primary key auto_random(3))")
+	tk.MustExec("insert into t1 values ((7 << 61) | 42)")
+	tk.MustQuery("select tidb_decode_auto_random('t1', a) from t1").Check(testkit.Rows(`{"seq": 42, "shard": 7}`))
+
+	tk.MustExec("create table t2 (a bigint primary key)")
+	tk.MustQuery("select tidb_decode_auto_random('t2', 1)").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1210 table t2 doesn't have an auto_random column"))
+	err := tk.QueryToErr("select tidb_decode_auto_random('test.not_exist', 1)")
+	require.Error(t, err)
+
+	tk.MustExec("create user 'decode_auto_random'@'%'")
+	tk2 := testkit.NewTestKit(t, store)
+	require.True(t, tk2.Session().Auth(&auth.UserIdentity{Username: "decode_auto_random", Hostname: "%"}, nil, nil))
+	require.EqualError(t, tk2.QueryToErr("select tidb_decode_auto_random('test.t', 1)"),
+		"[expression:1142]SELECT command denied to user 'decode_auto_random'@'%' for table 't'")
+	require.EqualError(t, tk2.QueryToErr("select tidb_decode_auto_random('test.not_exist', 1)"),
+		"[expression:1142]SELECT command denied to user 'decode_auto_random'@'%' for table 'not_exist'")
+}
+
+func TestTiDBDecodeKeyJSON(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustQuery("select tidb_decode_key_json('74800000000000002B5F72800000000000A5D3')").Check(testkit.Rows(`{"handle": 42451, "table_id": 43}`))
+	tk.MustQuery("select tidb_decode_key_json('7480000000000000695F698000000000000001038000000000004E20')").Check(testkit.Rows(`{"index_id": 1, "index_values": "20000", "table_id": 105}`))
+	tk.MustQuery("select tidb_decode_key_json('7480000000000000FF4700000000000000F8')").Check(testkit.Rows(`{"table_id": 71}`))
+	tk.MustQuery("select tidb_decode_key_json(null)").Check(testkit.Rows("<nil>"))
+
+	// An invalid key is decoded as NULL.
+	tk.MustQuery("select tidb_decode_key_json('7480000000000000FF2E5F728000000011FFE1A3000000000000')").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 invalid record/index key: 7480000000000000FF2E5F728000000011FFE1A3000000000000"))
+
+	// The decoded components can be used to filter the keys.
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (k varchar(255))")
+	tk.MustExec("insert into t values ('74800000000000002B5F72800000000000A5D3'), ('7480000000000000695F698000000000000001038000000000004E20'), " +
+		"('7480000000000000FF4700000000000000F8'), ('7480000000000000FF2E5F728000000011FFE1A3000000000000'), (null)")
+	for _, vec := range []string{"on", "off"} {
+		tk.MustExec("set @@tidb_enable_vectorized_expression = " + vec)
+		tk.MustQuery("select k from t where json_extract(tidb_decode_key_json(k), '$.index_id') = 1").Check(testkit.Rows("7480000000000000695F698000000000000001038000000000004E20"))
+		tk.MustQuery("select json_extract(tidb_decode_key_json(k), '$.table_id') from t where json_extract(tidb_decode_key_json(k), '$.table_id') > 50").Sort().Check(testkit.Rows("105", "71"))
+	}
+}
+
+func TestTiDBParseTsoLogicalFunc(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("set @@time_zone = '+00:00'")
+	tk.MustQuery(`select tidb_parse_tso_logical(404411537129996288), tidb_parse_tso_logical(404411537130008633), tidb_parse_tso_logical("404411537130008633")`).Check(testkit.Rows("0 12345 12345"))
+	tk.MustQuery("select tidb_parse_tso(404411537130008633), tidb_parse_tso_logical(404411537130008633)").Check(testkit.Rows("2018-11-20 09:53:04.877000 12345"))
+	tk.MustQuery("select tidb_parse_tso_logical(0), tidb_parse_tso_logical(-1), tidb_parse_tso_logical(null)").Check(testkit.Rows("<nil> <nil> <nil>"))
+}
+
+func TestTiDBDecodeTimeFromRowID(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@time_zone = '+08:00'")
+	tk.MustQuery("select tidb_decode_time_from_rowid(404411537129996288, 0)").Check(testkit.Rows("2018-11-20 17:53:04.877000"))
+	tk.MustQuery("select tidb_decode_time_from_rowid(6745479812467654656, 4)").Check(testkit.Rows("2018-11-20 17:53:04.877000"))
+	tk.MustQuery("select tidb_decode_time_from_rowid(null, 4), tidb_decode_time_from_rowid(1, null)").Check(testkit.Rows("<nil> <nil>"))
+
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id bigint, shard_bits int)")
+	tk.MustExec("insert into t values (6745479812467654656, 4), (0, 0), (404411537129996288, 16), (null, 0)")
+	tk.MustQuery("select tidb_decode_time_from_rowid(id, shard_bits) from t").Check(testkit.Rows("2018-11-20 17:53:04.877000", "<nil>", "<nil>", "<nil>"))
+	tk.MustQuery("show warnings").Check(testkit.Rows(
+		"Warning 1210 Incorrect arguments to tidb_decode_time_from_rowid: invalid rowid 0",
+		"Warning 1210 Incorrect arguments to tidb_decode_time_from_rowid: shard_bits should be in [0, 15], but got 16"))
+}
+
+func TestTiDBDecodeTimestampColumn(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@time_zone = '+08:00'")
+	tk.MustQuery("select tidb_decode_timestamp_column(1849645221953004096)").Check(testkit.Rows("2021-12-01 16:30:15.123456"))
+	tk.MustQuery("select tidb_decode_timestamp_column(1802216106174185472)").Check(testkit.Rows("1970-01-01 08:00:01.000000"))
+	tk.MustQuery("select tidb_decode_timestamp_column(null)").Check(testkit.Rows("<nil>"))
+
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (raw bigint)")
+	tk.MustExec("insert into t values (1849645221953004096), (0), (1802216106157408256), (1864461879754096640), (null)")
+	for _, vec := range []string{"on", "off"} {
+		tk.MustExec("set @@tidb_enable_vectorized_expression = " + vec)
+		tk.MustQuery("select tidb_decode_timestamp_column(raw) from t").Check(testkit.Rows("2021-12-01 16:30:15.123456", "<nil>", "<nil>", "<nil>", "<nil>"))
+		tk.MustQuery("show warnings").Check(testkit.Rows(
+			"Warning 1292 Incorrect timestamp value: '0'",
+			"Warning 1292 Incorrect timestamp value: '1802216106157408256'",
+			"Warning 1292 Incorrect timestamp value: '1864461879754096640'"))
+	}
+}
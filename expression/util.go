@@ -978,6 +978,11 @@ const (
 
 // GetFormatBytes convert byte count to value with units.
 func GetFormatBytes(bytes float64) string {
+	return GetFormatBytesWithPrecision(bytes, 2)
+}
+
+// GetFormatBytesWithPrecision convert byte count to value with units, keeping precision fractional digits.
+func GetFormatBytesWithPrecision(bytes float64, precision int) string {
 	var divisor float64
 	var unit string
 
@@ -1010,9 +1015,48 @@ func GetFormatBytes(bytes float64) string {
 	}
 	value := bytes / divisor
 	if math.Abs(value) >= 100000.0 {
-		return strconv.FormatFloat(value, 'e', 2, 64) + " " + unit
+		return strconv.FormatFloat(value, 'e', precision, 64) + " " + unit
 	}
-	return strconv.FormatFloat(value, 'f', 2, 64) + " " + unit
+	return strconv.FormatFloat(value, 'f', precision, 64) + " " + unit
+}
+
+// ParseFormatBytes converts the value with units returned by GetFormatBytes back to the byte count.
+// The unit is case-insensitive, and the spaces around the value and the unit are ignored.
+func ParseFormatBytes(str string) (int64, error) {
+	str = strings.TrimSpace(str)
+	end := len(str)
+	for end > 0 && unicode.IsLetter(rune(str[end-1])) {
+		end--
+	}
+	num, unit := strings.TrimSpace(str[:end]), strings.ToLower(str[end:])
+	var multiplier float64
+	switch unit {
+	case "", "b", "byte", "bytes":
+		multiplier = 1
+	case "kib":
+		multiplier = kib
+	case "mib":
+		multiplier = mib
+	case "gib":
+		multiplier = gib
+	case "tib":
+		multiplier = tib
+	case "pib":
+		multiplier = pib
+	case "eib":
+		multiplier = eib
+	default:
+		return 0, errors.Errorf("unknown unit '%s'", str[end:])
+	}
+	value, err := strconv.ParseFloat(num, 64)
+	if err != nil || math.IsNaN(value) {
+		return 0, errors.Errorf("invalid value '%s'", num)
+	}
+	bytes := math.Round(value * multiplier)
+	if bytes < math.MinInt64 || bytes >= math.MaxInt64 {
+		return 0, errors.Errorf("value '%s' is out of range", str)
+	}
+	return int64(bytes), nil
 }
 
 // GetFormatNanoTime convert time in nanoseconds to value with units.
@@ -1054,6 +1098,45 @@ func GetFormatNanoTime(time float64) string {
 	return strconv.FormatFloat(value, 'f', 2, 64) + " " + unit
 }
 
+// ParseFormatNanoTime converts the value with units returned by GetFormatNanoTime back to nanoseconds.
+// The unit is case-insensitive, and the spaces around the value and the unit are ignored.
+func ParseFormatNanoTime(str string) (int64, error) {
+	str = strings.TrimSpace(str)
+	end := len(str)
+	for end > 0 && unicode.IsLetter(rune(str[end-1])) {
+		end--
+	}
+	num, unit := strings.TrimSpace(str[:end]), strings.ToLower(str[end:])
+	var multiplier float64
+	switch unit {
+	case "", "ns":
+		multiplier = nano
+	case "us":
+		multiplier = micro
+	case "ms":
+		multiplier = milli
+	case "s":
+		multiplier = sec
+	case "min":
+		multiplier = min
+	case "h":
+		multiplier = hour
+	case "d":
+		multiplier = dayTime
+	default:
+		return 0, errors.Errorf("unknown unit '%s'", str[end:])
+	}
+	value, err := strconv.ParseFloat(num, 64)
+	if err != nil || math.IsNaN(value) {
+		return 0, errors.Errorf("invalid value '%s'", num)
+	}
+	nanoTime := math.Round(value * multiplier)
+	if nanoTime < math.MinInt64 || nanoTime >= math.MaxInt64 {
+		return 0, errors.Errorf("value '%s' is out of range", str)
+	}
+	return int64(nanoTime), nil
+}
+
 // SQLDigestTextRetriever is used to find the normalized SQL statement text by SQL digests in statements_summary table.
 // It's exported for test purposes. It's used by the `tidb_decode_sql_digests` builtin function, but also exposed to
 // be used in other modules.
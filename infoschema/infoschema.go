@@ -356,6 +356,20 @@ func init() {
 	util.GetSequenceByName = func(is interface{}, schema, sequence model.CIStr) (util.SequenceTable, error) {
 		return GetSequenceByName(is.(InfoSchema), schema, sequence)
 	}
+	util.GetTableInfoByName = func(is interface{}, schema, table model.CIStr) (*model.TableInfo, error) {
+		tbl, err := is.(InfoSchema).TableByName(schema, table)
+		if err != nil {
+			return nil, err
+		}
+		return tbl.Meta(), nil
+	}
+	util.GetTableNameByID = func(is interface{}, tableID int64) (string, bool) {
+		tbl, ok := is.(InfoSchema).TableByID(tableID)
+		if !ok {
+			return "", false
+		}
+		return tbl.Meta().Name.O, true
+	}
 }
 
 // HasAutoIncrementColumn checks whether the table has auto_increment columns, if so, return true and the column name.
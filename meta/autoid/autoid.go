@@ -144,6 +144,10 @@ type Allocator interface {
 	// cycle option.
 	AllocSeqCache() (min int64, max int64, round int64, err error)
 
+	// AllocSeqCacheN is like AllocSeqCache, but the returned range covers at least n sequence values if the rest of
+	// the sequence is enough, even if n is larger than the size of sequence cache.
+	AllocSeqCacheN(n int64) (min int64, max int64, round int64, err error)
+
 	// Rebase rebases the autoID base for table with tableID and the new base value.
 	// If allocIDs is true, it will allocate some IDs and save to the cache.
 	// If allocIDs is false, it will not allocate IDs.
@@ -582,7 +586,13 @@ func (alloc *allocator) Alloc(ctx context.Context, n uint64, increment, offset i
 func (alloc *allocator) AllocSeqCache() (int64, int64, int64, error) {
 	alloc.mu.Lock()
 	defer alloc.mu.Unlock()
-	return alloc.alloc4Sequence()
+	return alloc.alloc4Sequence(0)
+}
+
+func (alloc *allocator) AllocSeqCacheN(n int64) (int64, int64, int64, error) {
+	alloc.mu.Lock()
+	defer alloc.mu.Unlock()
+	return alloc.alloc4Sequence(n)
 }
 
 func validIncrementAndOffset(increment, offset int64) bool {
@@ -919,7 +929,8 @@ func getAllocatorStatsFromCtx(ctx context.Context) (context.Context, *AllocatorR
 // 3: sequence allocation may have negative growth.
 // 4: sequence allocation batch length can be dissatisfied.
 // 5: sequence batch allocation will be consumed immediately.
-func (alloc *allocator) alloc4Sequence() (min int64, max int64, round int64, err error) {
+// The batch covers n values if n is larger than the size of sequence cache.
+func (alloc *allocator) alloc4Sequence(n int64) (min int64, max int64, round int64, err error) {
 	increment := alloc.sequence.Increment
 	offset := alloc.sequence.Start
 	minValue := alloc.sequence.MinValue
@@ -928,6 +939,9 @@ func (alloc *allocator) alloc4Sequence() (min int64, max int64, round int64, err
 	if !alloc.sequence.Cache {
 		cacheSize = 1
 	}
+	if n > cacheSize {
+		cacheSize = n
+	}
 
 	var newBase, newEnd int64
 	startTime := time.Now()
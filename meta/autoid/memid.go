@@ -147,6 +147,10 @@ func (alloc *inMemoryAllocator) AllocSeqCache() (int64, int64, int64, error) {
 	return 0, 0, 0, errNotImplemented.GenWithStackByArgs()
 }
 
+func (alloc *inMemoryAllocator) AllocSeqCacheN(n int64) (int64, int64, int64, error) {
+	return 0, 0, 0, errNotImplemented.GenWithStackByArgs()
+}
+
 func (alloc *inMemoryAllocator) RebaseSeq(requiredBase int64) (int64, bool, error) {
 	return 0, false, errNotImplemented.GenWithStackByArgs()
 }
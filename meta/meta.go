@@ -15,6 +15,7 @@
 package meta
 
 import (
+	"bytes"
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
@@ -33,6 +34,7 @@ import (
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/structure"
+	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/dbterror"
 	"github.com/pingcap/tidb/util/logutil"
 	"go.uber.org/zap"
@@ -1225,3 +1227,111 @@ func (m *Meta) SetSchemaDiff(diff *model.SchemaDiff) error {
 	metrics.MetaHistogram.WithLabelValues(metrics.SetSchemaDiff, metrics.RetLabel(err)).Observe(time.Since(startTime).Seconds())
 	return errors.Trace(err)
 }
+
+// DecodeKey decodes a meta key and describes what it stores, e.g. {"type":"DB","db_id":5} for the info
+// of a database or {"type":"Table","db_id":5,"table_id":42} for the info of a table.
+// An error is returned if the key isn't a meta key.
+func DecodeKey(key kv.Key) (map[string]interface{}, error) {
+	if !bytes.HasPrefix(key, mMetaPrefix) {
+		return nil, errors.Errorf("invalid meta key: %X", []byte(key))
+	}
+	rest, name, err := codec.DecodeBytes(key[len(mMetaPrefix):], nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rest, tp, err := codec.DecodeUint(rest)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var desc map[string]interface{}
+	switch structure.TypeFlag(tp) {
+	case structure.StringData:
+		desc = decodeStringDataKey(name)
+	case structure.HashMeta:
+		desc = map[string]interface{}{"type": "HashMeta", "key": string(name)}
+	case structure.HashData:
+		var field []byte
+		if rest, field, err = codec.DecodeBytes(rest, nil); err != nil {
+			return nil, errors.Trace(err)
+		}
+		desc = decodeHashDataKey(name, field)
+	case structure.ListMeta:
+		desc = map[string]interface{}{"type": "ListMeta", "key": string(name)}
+	case structure.ListData:
+		var index int64
+		if rest, index, err = codec.DecodeInt(rest); err != nil {
+			return nil, errors.Trace(err)
+		}
+		desc = map[string]interface{}{"type": "ListData", "key": string(name), "index": index}
+	default:
+		return nil, errors.Errorf("invalid meta key flag %c", byte(tp))
+	}
+	if len(rest) != 0 {
+		return nil, errors.Errorf("invalid meta key: %X", []byte(key))
+	}
+	return desc, nil
+}
+
+func decodeStringDataKey(name []byte) map[string]interface{} {
+	switch {
+	case bytes.Equal(name, mNextGlobalIDKey):
+		return map[string]interface{}{"type": "NextGlobalID"}
+	case bytes.Equal(name, mSchemaVersionKey):
+		return map[string]interface{}{"type": "SchemaVersion"}
+	case bytes.Equal(name, mBootstrapKey):
+		return map[string]interface{}{"type": "BootstrapVersion"}
+	case bytes.Equal(name, mPolicyGlobalID):
+		return map[string]interface{}{"type": "PolicyGlobalID"}
+	}
+	if version, ok := parseMetaKeyID(name, mSchemaDiffPrefix); ok {
+		return map[string]interface{}{"type": "SchemaDiff", "schema_version": version}
+	}
+	return map[string]interface{}{"type": "String", "key": string(name)}
+}
+
+func decodeHashDataKey(name, field []byte) map[string]interface{} {
+	switch {
+	case bytes.Equal(name, mDBs):
+		if dbID, ok := parseMetaKeyID(field, mDBPrefix); ok {
+			return map[string]interface{}{"type": "DB", "db_id": dbID}
+		}
+	case bytes.Equal(name, mPolicies):
+		if policyID, ok := parseMetaKeyID(field, mPolicyPrefix); ok {
+			return map[string]interface{}{"type": "Policy", "policy_id": policyID}
+		}
+	case bytes.Equal(name, mDDLJobHistoryKey) && len(field) == 8:
+		return map[string]interface{}{"type": "DDLJobHistory", "job_id": int64(binary.BigEndian.Uint64(field))}
+	default:
+		dbID, ok := parseMetaKeyID(name, mDBPrefix)
+		if !ok {
+			break
+		}
+		for _, f := range []struct {
+			prefix string
+			tp     string
+			idName string
+		}{
+			{mTablePrefix, "Table", "table_id"},
+			{mTableIDPrefix, "AutoTableID", "table_id"},
+			{mIncIDPrefix, "AutoIncrementID", "table_id"},
+			{mRandomIDPrefix, "AutoRandomID", "table_id"},
+			{mSequencePrefix, "SequenceValue", "sequence_id"},
+			{mSeqCyclePrefix, "SequenceCycle", "sequence_id"},
+		} {
+			if id, ok := parseMetaKeyID(field, f.prefix); ok {
+				return map[string]interface{}{"type": f.tp, "db_id": dbID, f.idName: id}
+			}
+		}
+	}
+	return map[string]interface{}{"type": "HashData", "key": string(name), "field": string(field)}
+}
+
+// parseMetaKeyID parses the ID from a key like "DB:5" with the prefix "DB".
+func parseMetaKeyID(key []byte, prefix string) (int64, bool) {
+	s := string(key)
+	if !strings.HasPrefix(s, prefix+":") {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(s[len(prefix)+1:], 10, 64)
+	return id, err == nil
+}
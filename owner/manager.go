@@ -74,6 +74,8 @@ const (
 type DDLOwnerChecker interface {
 	// IsOwner returns whether the ownerManager is the owner.
 	IsOwner() bool
+	// GetOwnerID gets the owner ID.
+	GetOwnerID(ctx context.Context) (string, error)
 }
 
 // ownerManager represents the structure which is used for electing owner.
@@ -182,6 +182,14 @@ const (
 	// For more info, please see AsOfClause.
 	TiDBBoundedStaleness = "tidb_bounded_staleness"
 	TiDBParseTso         = "tidb_parse_tso"
+	// TiDBParseTsoLogical is used to get the logical counter from a TSO, which complements TiDBParseTso.
+	TiDBParseTsoLogical = "tidb_parse_tso_logical"
+	// TiDBDecodeTimeFromRowID is used to get the physical time from a time-ordered rowid like AUTO_RANDOM.
+	TiDBDecodeTimeFromRowID = "tidb_decode_time_from_rowid"
+	// TiDBDecodeTimestampColumn is used to interpret the raw value of a timestamp column stored by TiDB.
+	TiDBDecodeTimestampColumn = "tidb_decode_timestamp_column"
+	// TiDBWaitTxnTS is used to wait until the given TS can be read by the Stale Read.
+	TiDBWaitTxnTS = "tidb_wait_txn_ts"
 
 	// string functions
 	ASCII           = "ascii"
@@ -238,28 +246,59 @@ const (
 	Soundex         = "soundex"
 
 	// information functions
-	Benchmark            = "benchmark"
-	Charset              = "charset"
-	Coercibility         = "coercibility"
-	Collation            = "collation"
-	ConnectionID         = "connection_id"
-	CurrentUser          = "current_user"
-	CurrentRole          = "current_role"
-	Database             = "database"
-	FoundRows            = "found_rows"
-	LastInsertId         = "last_insert_id"
-	RowCount             = "row_count"
-	Schema               = "schema"
-	SessionUser          = "session_user"
-	SystemUser           = "system_user"
-	User                 = "user"
-	Version              = "version"
-	TiDBVersion          = "tidb_version"
-	TiDBIsDDLOwner       = "tidb_is_ddl_owner"
-	TiDBDecodePlan       = "tidb_decode_plan"
-	TiDBDecodeSQLDigests = "tidb_decode_sql_digests"
-	FormatBytes          = "format_bytes"
-	FormatNanoTime       = "format_nano_time"
+	Benchmark                    = "benchmark"
+	Charset                      = "charset"
+	Coercibility                 = "coercibility"
+	Collation                    = "collation"
+	ConnectionID                 = "connection_id"
+	CurrentUser                  = "current_user"
+	CurrentRole                  = "current_role"
+	CurrentRoleJSON              = "current_role_json"
+	Database                     = "database"
+	FoundRows                    = "found_rows"
+	LastInsertId                 = "last_insert_id"
+	RowCount                     = "row_count"
+	Schema                       = "schema"
+	SessionUser                  = "session_user"
+	SystemUser                   = "system_user"
+	User                         = "user"
+	Version                      = "version"
+	VersionCompare               = "version_compare"
+	TiDBVersion                  = "tidb_version"
+	TiDBVersionJSON              = "tidb_version_json"
+	TiDBVersionComment           = "tidb_version_comment"
+	TiDBIsDDLOwner               = "tidb_is_ddl_owner"
+	TiDBDDLOwner                 = "tidb_ddl_owner"
+	TiDBDecodePlan               = "tidb_decode_plan"
+	TiDBDecodePlanStrict         = "tidb_decode_plan_strict"
+	TiDBDecodeSQLDigests         = "tidb_decode_sql_digests"
+	TiDBEncodeSQLDigest          = "tidb_encode_sql_digest"
+	TiDBParseAndExplain          = "tidb_parse_and_explain"
+	TiDBEncodeTimeRangeKeys      = "tidb_encode_time_range_keys"
+	TiDBDecodeLockKey            = "tidb_decode_lock_key"
+	TiDBMVCCInfo                 = "tidb_mvcc_info"
+	TiDBKeyspaceID               = "tidb_keyspace_id"
+	TiDBDecodeRow                = "tidb_decode_row"
+	TiDBCurrentStmtType          = "tidb_current_stmt_type"
+	TiDBDecodeIndexValue         = "tidb_decode_index_value"
+	TiDBTraceID                  = "tidb_trace_id"
+	TiDBDecodeKeyRange           = "tidb_decode_key_range"
+	TiDBEstimateIndexSelectivity = "tidb_estimate_index_selectivity"
+	TiDBCurrentBatchSize         = "tidb_current_batch_size"
+	TiDBDecodeBase64Row          = "tidb_decode_base64_row"
+	TiDBCurrentSQLDigestText     = "tidb_current_sql_digest_text"
+	TiDBPlanDigest               = "tidb_plan_digest"
+	TiDBDecodeMetaKey            = "tidb_decode_meta_key"
+	TiDBCurrentIsolationLevel    = "tidb_current_isolation_level"
+	TiDBCurrentTxnID             = "tidb_current_txn_id"
+	TiDBEstimateCost             = "tidb_estimate_cost"
+	TiDBDecodeAutoRandom         = "tidb_decode_auto_random"
+	TiDBSessionAlive             = "tidb_session_alive"
+	TiDBBenchmark                = "tidb_benchmark"
+	FormatBytes                  = "format_bytes"
+	FormatNanoTime               = "format_nano_time"
+	ParseBytes                   = "parse_bytes"
+	ParseNanoTime                = "parse_nano_time"
 
 	// control functions
 	If     = "if"
@@ -340,17 +379,24 @@ const (
 	JSONKeys          = "json_keys"
 	JSONLength        = "json_length"
 
+	// TiDBJSONValidSchema validates a JSON document against a JSON Schema.
+	TiDBJSONValidSchema = "tidb_json_valid_schema"
+
 	// TiDB internal function.
 	TiDBDecodeKey       = "tidb_decode_key"
+	TiDBDecodeKeyJSON   = "tidb_decode_key_json"
 	TiDBDecodeBase64Key = "tidb_decode_base64_key"
 
 	// MVCC information fetching function.
 	GetMvccInfo = "get_mvcc_info"
 
 	// Sequence function.
-	NextVal = "nextval"
-	LastVal = "lastval"
-	SetVal  = "setval"
+	NextVal  = "nextval"
+	NextValN = "nextval_n"
+	LastVal  = "lastval"
+	SetVal   = "setval"
+	// TiDBGenerateSeries generates a series of integers like a sequence, but without a sequence object.
+	TiDBGenerateSeries = "tidb_generate_series"
 )
 
 type FuncCallExprType int8
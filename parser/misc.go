@@ -482,6 +482,7 @@ var tokenMap = map[string]int{
 	"NEXT_ROW_ID":              next_row_id,
 	"NEXT":                     next,
 	"NEXTVAL":                  nextval,
+	"NEXTVAL_N":                nextvalN,
 	"NO_WRITE_TO_BINLOG":       noWriteToBinLog,
 	"NO":                       no,
 	"NOCACHE":                  nocache,
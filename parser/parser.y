@@ -465,6 +465,7 @@ import (
 	never                 "NEVER"
 	next                  "NEXT"
 	nextval               "NEXTVAL"
+	nextvalN              "NEXTVAL_N"
 	no                    "NO"
 	nocache               "NOCACHE"
 	nocycle               "NOCYCLE"
@@ -6009,6 +6010,7 @@ UnReservedKeyword:
 |	"PER_DB"
 |	"NEXT"
 |	"NEXTVAL"
+|	"NEXTVAL_N"
 |	"LASTVAL"
 |	"SETVAL"
 |	"AGO"
@@ -7273,6 +7275,16 @@ FunctionNameSequence:
 			Args:   []ast.ExprNode{objNameExpr, valueExpr},
 		}
 	}
+|	"NEXTVAL_N" '(' TableName ',' Expression ')'
+	{
+		objNameExpr := &ast.TableNameExpr{
+			Name: $3.(*ast.TableName),
+		}
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr(ast.NextValN),
+			Args:   []ast.ExprNode{objNameExpr, $5},
+		}
+	}
 |	NextValueForSequence
 
 SumExpr:
@@ -10992,6 +11004,7 @@ TraceableStmt:
 |	SetOprStmt
 |	SelectStmt
 |	SelectStmtWithClause
+|	ExecuteStmt
 |	SubSelect
 	{
 		var sel ast.StmtNode
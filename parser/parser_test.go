@@ -1537,10 +1537,16 @@ func TestBuiltin(t *testing.T) {
 
 		{`SELECT tidb_version();`, true, "SELECT TIDB_VERSION()"},
 		{`SELECT tidb_is_ddl_owner();`, true, "SELECT TIDB_IS_DDL_OWNER()"},
+		{`SELECT tidb_ddl_owner();`, true, "SELECT TIDB_DDL_OWNER()"},
 		{`SELECT tidb_decode_plan();`, true, "SELECT TIDB_DECODE_PLAN()"},
 		{`SELECT tidb_decode_key('abc');`, true, "SELECT TIDB_DECODE_KEY(_UTF8MB4'abc')"},
 		{`SELECT tidb_decode_base64_key('abc');`, true, "SELECT TIDB_DECODE_BASE64_KEY(_UTF8MB4'abc')"},
 		{`SELECT tidb_decode_sql_digests('[]');`, true, "SELECT TIDB_DECODE_SQL_DIGESTS(_UTF8MB4'[]')"},
+		{`SELECT tidb_decode_sql_digests('[]', 0, 1);`, true, "SELECT TIDB_DECODE_SQL_DIGESTS(_UTF8MB4'[]', 0, 1)"},
+		{`SELECT tidb_encode_sql_digest('select 1');`, true, "SELECT TIDB_ENCODE_SQL_DIGEST(_UTF8MB4'select 1')"},
+		{`SELECT tidb_mvcc_info('7480');`, true, "SELECT TIDB_MVCC_INFO(_UTF8MB4'7480')"},
+		{`SELECT version_compare('5.7.25-TiDB-v6.1.0', 'v6.0.0');`, true, "SELECT VERSION_COMPARE(_UTF8MB4'5.7.25-TiDB-v6.1.0', _UTF8MB4'v6.0.0')"},
+		{`SELECT tidb_current_txn_id();`, true, "SELECT TIDB_CURRENT_TXN_ID()"},
 		{`SELECT get_mvcc_info('hex', '0xabc');`, true, "SELECT GET_MVCC_INFO(_UTF8MB4'hex', _UTF8MB4'0xabc')"},
 
 		// for time fsp
@@ -2129,6 +2135,11 @@ func TestBuiltin(t *testing.T) {
 		{"select nextval(seq)", true, "SELECT NEXTVAL(`seq`)"},
 		{"select lastval(seq)", true, "SELECT LASTVAL(`seq`)"},
 		{"select setval(seq, 100)", true, "SELECT SETVAL(`seq`, 100)"},
+		{"select nextval_n(seq, 10)", true, "SELECT NEXTVAL_N(`seq`, 10)"},
+		{"select nextval_n(test.seq, 1 + 2)", true, "SELECT NEXTVAL_N(`test`.`seq`, 1+2)"},
+		{"select nextval_n(seq)", false, ""},
+		{"select tidb_generate_series(1, 10)", true, "SELECT TIDB_GENERATE_SERIES(1, 10)"},
+		{"select tidb_generate_series(10, 1, -2)", true, "SELECT TIDB_GENERATE_SERIES(10, 1, -2)"},
 		{"select next value for seq", true, "SELECT NEXTVAL(`seq`)"},
 		{"select next value for sequence", true, "SELECT NEXTVAL(`sequence`)"},
 		{"select NeXt vAluE for seQuEncE2", true, "SELECT NEXTVAL(`seQuEncE2`)"},
@@ -4778,6 +4789,7 @@ func TestTrace(t *testing.T) {
 		{"trace plan select c1 from t1", true, "TRACE PLAN SELECT `c1` FROM `t1`"},
 		{"trace plan target = 'estimation' select c1 from t1", true, "TRACE PLAN TARGET = 'estimation' SELECT `c1` FROM `t1`"},
 		{"trace plan target = 'arandomstring' select c1 from t1", true, "TRACE PLAN TARGET = 'arandomstring' SELECT `c1` FROM `t1`"},
+		{"trace plan execute stmt using @a, @b", true, "TRACE PLAN EXECUTE `stmt` USING @`a`,@`b`"},
 	}
 	RunTest(t, table, false)
 }
@@ -5653,6 +5665,7 @@ func TestWindowFunctions(t *testing.T) {
 
 		// For TSO functions
 		{`select tidb_parse_tso(1)`, true, "SELECT TIDB_PARSE_TSO(1)"},
+		{`select tidb_parse_tso_logical(1)`, true, "SELECT TIDB_PARSE_TSO_LOGICAL(1)"},
 		{`select tidb_bounded_staleness('2015-09-21 00:07:01', NOW())`, true, "SELECT TIDB_BOUNDED_STALENESS(_UTF8MB4'2015-09-21 00:07:01', NOW())"},
 		{`select tidb_bounded_staleness(DATE_SUB(NOW(), INTERVAL 3 SECOND), NOW())`, true, "SELECT TIDB_BOUNDED_STALENESS(DATE_SUB(NOW(), INTERVAL 3 SECOND), NOW())"},
 		{`select tidb_bounded_staleness('2015-09-21 00:07:01', '2021-04-27 11:26:13')`, true, "SELECT TIDB_BOUNDED_STALENESS(_UTF8MB4'2015-09-21 00:07:01', _UTF8MB4'2021-04-27 11:26:13')"},
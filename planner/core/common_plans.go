@@ -33,6 +33,7 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/table"
@@ -46,6 +47,7 @@ import (
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/ranger"
 	"github.com/pingcap/tidb/util/texttree"
+	"github.com/pingcap/tidb/util/tracing"
 	"github.com/tikv/client-go/v2/oracle"
 	"go.uber.org/zap"
 )
@@ -380,6 +382,28 @@ func (e *Execute) setFoundInPlanCache(sctx sessionctx.Context, opt bool) error {
 	return err
 }
 
+// tracePlanCacheParams records the parameter markers of the prepared statement and the values bound to them.
+// The types of the user variables used by execute are a part of the plan cache key, so they are recorded as the
+// parameter types. For the binary protocol, the types are inferred from the values.
+func tracePlanCacheParams(stmtCtx *stmtctx.StatementContext, prepared *ast.Prepared, tps []*types.FieldType) {
+	tracer := &tracing.PlanCacheParamTracer{UseCache: prepared.UseCache, Params: make([]tracing.PlanCacheParamTrace, 0, len(prepared.Params))}
+	for i, p := range prepared.Params {
+		param := p.(*driver.ParamMarkerExpr)
+		tp := types.NewFieldType(mysql.TypeUnspecified)
+		if i < len(tps) {
+			tp = tps[i]
+		} else {
+			types.DefaultParamTypeForValue(param.Datum.GetValue(), tp)
+		}
+		value, err := param.Datum.ToString()
+		if err != nil || param.Datum.IsNull() {
+			value = "NULL"
+		}
+		tracer.AppendParam(param.Order, param.Offset, types.TypeStr(tp.Tp), value)
+	}
+	stmtCtx.PlanCacheParamTrace = tracer
+}
+
 func (e *Execute) getPhysicalPlan(ctx context.Context, sctx sessionctx.Context, is infoschema.InfoSchema, preparedStmt *CachedPrepareStmt) error {
 	var cacheKey kvcache.Key
 	sessVars := sctx.GetSessionVars()
@@ -412,6 +436,9 @@ func (e *Execute) getPhysicalPlan(ctx context.Context, sctx sessionctx.Context,
 			tps[i] = types.NewFieldType(mysql.TypeNull)
 		}
 	}
+	if stmtCtx.EnableOptimizeTrace {
+		tracePlanCacheParams(stmtCtx, prepared, tps)
+	}
 	if prepared.CachedPlan != nil {
 		// Rewriting the expression in the select.where condition  will convert its
 		// type from "paramMarker" to "Constant".When Point Select queries are executed,
@@ -2707,6 +2707,19 @@ func (p *LogicalLimit) exhaustPhysicalPlans(prop *property.PhysicalProperty) ([]
 		return nil, true, nil
 	}
 
+	if p.calcFoundRows {
+		// All the rows of the child are needed to count the found rows, so the limit
+		// can only be executed in TiDB and the child is expected to return all its rows.
+		resultProp := &property.PhysicalProperty{TaskTp: property.RootTaskType, ExpectedCnt: math.MaxFloat64}
+		limit := PhysicalLimit{
+			Offset:        p.Offset,
+			Count:         p.Count,
+			CalcFoundRows: true,
+		}.Init(p.ctx, p.stats, p.blockOffset, resultProp)
+		limit.SetSchema(p.Schema())
+		return []PhysicalPlan{limit}, true, nil
+	}
+
 	allTaskTypes := []property.TaskType{property.CopSingleReadTaskType, property.CopDoubleReadTaskType}
 	if !pushLimitOrTopNForcibly(p) {
 		allTaskTypes = append(allTaskTypes, property.RootTaskType)
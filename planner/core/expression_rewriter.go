@@ -15,9 +15,12 @@
 package core
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"math"
 	"strconv"
 	"strings"
 	"time"
@@ -27,25 +30,30 @@ import (
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/expression/aggregation"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/opcode"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/telemetry"
 	"github.com/pingcap/tidb/types"
+	tjson "github.com/pingcap/tidb/types/json"
 	driver "github.com/pingcap/tidb/types/parser_driver"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/hint"
+	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/sem"
 	"github.com/pingcap/tidb/util/stringutil"
+	"github.com/pingcap/tidb/util/tracing"
 )
 
 // EvalSubqueryFirstRow evaluates incorrelated subqueries once, and get first row.
@@ -157,6 +165,10 @@ func (b *PlanBuilder) getExpressionRewriter(ctx context.Context, p LogicalPlan)
 	if len(b.rewriterPool) < b.rewriterCounter {
 		rewriter = &expressionRewriter{p: p, b: b, sctx: b.ctx, ctx: ctx}
 		rewriter.sctx.SetValue(expression.TiDBDecodeKeyFunctionKey, decodeKeyFromString)
+		rewriter.sctx.SetValue(expression.TiDBDecodeKeyJSONFunctionKey, decodeKeyToJSON)
+		rewriter.sctx.SetValue(expression.TiDBParseAndExplainFunctionKey, parseAndExplain)
+		rewriter.sctx.SetValue(expression.TiDBEstimateIndexSelectivityFunctionKey, estimateIndexSelectivity)
+		rewriter.sctx.SetValue(expression.TiDBEstimateCostFunctionKey, parseAndEstimateCost)
 		b.rewriterPool = append(b.rewriterPool, rewriter)
 		return
 	}
@@ -973,8 +985,9 @@ func (er *expressionRewriter) handleScalarSubquery(ctx context.Context, v *ast.S
 		return v, true
 	}
 	np = er.b.buildMaxOneRow(np)
-	if len(ExtractCorrelatedCols4LogicalPlan(np)) > 0 {
+	if corCols := ExtractCorrelatedCols4LogicalPlan(np); len(corCols) > 0 {
 		er.p = er.b.buildApplyWithJoinType(er.p, np, LeftOuterJoin)
+		er.appendCorrelatedScalarSubqueryTraceStep(np, er.p, corCols)
 		if np.Schema().Len() > 1 {
 			newCols := make([]expression.Expression, 0, np.Schema().Len())
 			for _, col := range np.Schema().Columns {
@@ -992,10 +1005,12 @@ func (er *expressionRewriter) handleScalarSubquery(ctx context.Context, v *ast.S
 		return v, true
 	}
 	// We don't want nth_plan hint to affect separately executed subqueries here, so disable nth_plan temporarily.
-	NthPlanBackup := er.sctx.GetSessionVars().StmtCtx.StmtHints.ForceNthPlan
-	er.sctx.GetSessionVars().StmtCtx.StmtHints.ForceNthPlan = -1
+	// The optimize trace is disabled too, otherwise the trace of the subquery would be mixed with the statement's.
+	stmtCtx := er.sctx.GetSessionVars().StmtCtx
+	NthPlanBackup, enableTraceBackup := stmtCtx.StmtHints.ForceNthPlan, stmtCtx.EnableOptimizeTrace
+	stmtCtx.StmtHints.ForceNthPlan, stmtCtx.EnableOptimizeTrace = -1, false
 	physicalPlan, _, err := DoOptimize(ctx, er.sctx, er.b.optFlag, np)
-	er.sctx.GetSessionVars().StmtCtx.StmtHints.ForceNthPlan = NthPlanBackup
+	stmtCtx.StmtHints.ForceNthPlan, stmtCtx.EnableOptimizeTrace = NthPlanBackup, enableTraceBackup
 	if err != nil {
 		er.err = err
 		return v, true
@@ -1005,6 +1020,7 @@ func (er *expressionRewriter) handleScalarSubquery(ctx context.Context, v *ast.S
 		er.err = err
 		return v, true
 	}
+	var expr expression.Expression
 	if np.Schema().Len() > 1 {
 		newCols := make([]expression.Expression, 0, np.Schema().Len())
 		for i, data := range row {
@@ -1012,21 +1028,67 @@ func (er *expressionRewriter) handleScalarSubquery(ctx context.Context, v *ast.S
 				Value:   data,
 				RetType: np.Schema().Columns[i].GetType()})
 		}
-		expr, err1 := er.newFunction(ast.RowFunc, newCols[0].GetType(), newCols...)
+		var err1 error
+		expr, err1 = er.newFunction(ast.RowFunc, newCols[0].GetType(), newCols...)
 		if err1 != nil {
 			er.err = err1
 			return v, true
 		}
-		er.ctxStackAppend(expr, types.EmptyName)
 	} else {
-		er.ctxStackAppend(&expression.Constant{
+		expr = &expression.Constant{
 			Value:   row[0],
 			RetType: np.Schema().Columns[0].GetType(),
-		}, types.EmptyName)
+		}
 	}
+	er.appendUncorrelatedScalarSubqueryTraceStep(np, expr)
+	er.ctxStackAppend(expr, types.EmptyName)
 	return v, true
 }
 
+// appendCorrelatedScalarSubqueryTraceStep records that the scalar subquery np refers to the outer columns corCols,
+// so it's built as the inner side of the apply.
+func (er *expressionRewriter) appendCorrelatedScalarSubqueryTraceStep(np, apply LogicalPlan, corCols []*expression.CorrelatedColumn) {
+	if !er.sctx.GetSessionVars().StmtCtx.EnableOptimizeTrace {
+		return
+	}
+	buffer := bytes.NewBufferString("[")
+	for i, col := range corCols {
+		if i > 0 {
+			buffer.WriteString(",")
+		}
+		buffer.WriteString(col.Column.String())
+	}
+	buffer.WriteString("]")
+	reason := fmt.Sprintf("the scalar subquery refers to the outer columns%s", buffer.String())
+	action := fmt.Sprintf("%v_%v is built as the inner side of %v_%v, which evaluates it for each outer row unless it's decorrelated",
+		np.TP(), np.ID(), apply.TP(), apply.ID())
+	er.appendScalarSubqueryTraceStep(np, reason, action)
+}
+
+// appendUncorrelatedScalarSubqueryTraceStep records that the scalar subquery np is evaluated once and replaced by
+// the constant expr.
+func (er *expressionRewriter) appendUncorrelatedScalarSubqueryTraceStep(np LogicalPlan, expr expression.Expression) {
+	if !er.sctx.GetSessionVars().StmtCtx.EnableOptimizeTrace {
+		return
+	}
+	reason := "the scalar subquery doesn't refer to any outer column"
+	action := fmt.Sprintf("%v_%v is evaluated once when building the plan and replaced by the constant[%s]",
+		np.TP(), np.ID(), expr.String())
+	er.appendScalarSubqueryTraceStep(np, reason, action)
+}
+
+func (er *expressionRewriter) appendScalarSubqueryTraceStep(np LogicalPlan, reason, action string) {
+	stmtCtx := er.sctx.GetSessionVars().StmtCtx
+	stmtCtx.ScalarSubqueryTraceSteps = append(stmtCtx.ScalarSubqueryTraceSteps, tracing.LogicalRuleOptimizeTraceStep{
+		ID:     np.ID(),
+		TP:     np.TP(),
+		Reason: reason,
+		Action: action,
+	})
+	// The steps are reported by the decorrelate rule.
+	er.b.optFlag |= flagDecorrelate
+}
+
 // Leave implements Visitor interface.
 func (er *expressionRewriter) Leave(originInNode ast.Node) (retNode ast.Node, ok bool) {
 	if er.err != nil {
@@ -1806,9 +1868,9 @@ func (er *expressionRewriter) funcCallToExpression(v *ast.FuncCallExpr) {
 // Now TableName in expression only used by sequence function like nextval(seq).
 // The function arg should be evaluated as a table name rather than normal column name like mysql does.
 func (er *expressionRewriter) toTable(v *ast.TableName) {
-	fullName := v.Name.L
+	fullName := quoteTableNamePart(v.Name.L)
 	if len(v.Schema.L) != 0 {
-		fullName = v.Schema.L + "." + fullName
+		fullName = quoteTableNamePart(v.Schema.L) + "." + fullName
 	}
 	val := &expression.Constant{
 		Value:   types.NewDatum(fullName),
@@ -1817,6 +1879,15 @@ func (er *expressionRewriter) toTable(v *ast.TableName) {
 	er.ctxStackAppend(val, types.EmptyName)
 }
 
+// quoteTableNamePart quotes the part of the qualified table name by backticks if it contains dots, backticks or
+// the spaces around it, which would be misread when the functions parse the name.
+func quoteTableNamePart(part string) string {
+	if !strings.ContainsAny(part, ".`") && strings.TrimSpace(part) == part {
+		return part
+	}
+	return "`" + strings.ReplaceAll(part, "`", "``") + "`"
+}
+
 func (er *expressionRewriter) toColumn(v *ast.ColumnName) {
 	idx, err := expression.FindFieldName(er.names, v)
 	if err != nil {
@@ -1974,10 +2045,45 @@ func hasCurrentDatetimeDefault(col *table.Column) bool {
 }
 
 func decodeKeyFromString(ctx sessionctx.Context, s string) string {
+	ret, ok := decodeKey(ctx, s)
+	if !ok {
+		return s
+	}
+	// TIDB_DECODE_KEY() keeps its original output, which names the fields differently and returns the table ID
+	// of a record key with an int handle as a string.
+	if h, ok := ret["handle"].(int64); ok {
+		ret = map[string]interface{}{"table_id": strconv.FormatInt(ret["table_id"].(int64), 10), "_tidb_rowid": h}
+	}
+	if vals, ok := ret["index_values"]; ok {
+		delete(ret, "index_values")
+		ret["index_vals"] = vals
+	}
+	retStr, err := json.Marshal(ret)
+	if err != nil {
+		ctx.GetSessionVars().StmtCtx.AppendWarning(err)
+		return s
+	}
+	return string(retStr)
+}
+
+// decodeKeyToJSON decodes the key in the same way as decodeKeyFromString, but returns the decoded
+// components as JSON. It returns false if the key can't be decoded.
+func decodeKeyToJSON(ctx sessionctx.Context, s string) (tjson.BinaryJSON, bool) {
+	ret, ok := decodeKey(ctx, s)
+	if !ok {
+		return tjson.BinaryJSON{}, false
+	}
+	return tjson.CreateBinary(ret), true
+}
+
+// decodeKey decodes the hex encoded record/index/table key into its components, i.e. the table_id, and
+// the handle of a record key or the index_id and the index_values of an index key. It returns false with
+// a warning if the key can't be decoded.
+func decodeKey(ctx sessionctx.Context, s string) (map[string]interface{}, bool) {
 	key, err := hex.DecodeString(s)
 	if err != nil {
 		ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("invalid record/index key: %X", key))
-		return s
+		return nil, false
 	}
 	// Auto decode byte if needed.
 	_, bs, err := codec.DecodeBytes(key, nil)
@@ -1987,12 +2093,12 @@ func decodeKeyFromString(ctx sessionctx.Context, s string) string {
 	tableID := tablecodec.DecodeTableID(key)
 	if tableID == 0 {
 		ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("invalid record/index key: %X", key))
-		return s
+		return nil, false
 	}
 	dm := domain.GetDomain(ctx)
 	if dm == nil {
 		ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("domain not found when decoding record/index key: %X", key))
-		return s
+		return nil, false
 	}
 	tbl, _ := dm.InfoSchema().TableByID(tableID)
 	loc := ctx.GetSessionVars().Location()
@@ -2000,48 +2106,44 @@ func decodeKeyFromString(ctx sessionctx.Context, s string) string {
 		ret, err := decodeRecordKey(key, tableID, tbl, loc)
 		if err != nil {
 			ctx.GetSessionVars().StmtCtx.AppendWarning(err)
-			return s
+			return nil, false
 		}
-		return ret
+		return ret, true
 	} else if tablecodec.IsIndexKey(key) {
 		ret, err := decodeIndexKey(key, tableID, tbl, loc)
 		if err != nil {
 			ctx.GetSessionVars().StmtCtx.AppendWarning(err)
-			return s
+			return nil, false
 		}
-		return ret
+		return ret, true
 	} else if tablecodec.IsTableKey(key) {
 		ret, err := decodeTableKey(key, tableID, tbl, loc)
 		if err != nil {
 			ctx.GetSessionVars().StmtCtx.AppendWarning(err)
-			return s
+			return nil, false
 		}
-		return ret
+		return ret, true
 	}
 	ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("invalid record/index key: %X", key))
-	return s
+	return nil, false
 }
 
-func decodeRecordKey(key []byte, tableID int64, tbl table.Table, loc *time.Location) (string, error) {
+func decodeRecordKey(key []byte, tableID int64, tbl table.Table, loc *time.Location) (map[string]interface{}, error) {
 	_, handle, err := tablecodec.DecodeRecordKey(key)
 	if err != nil {
-		return "", errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 	if handle.IsInt() {
 		ret := make(map[string]interface{})
-		ret["table_id"] = strconv.FormatInt(tableID, 10)
-		ret["_tidb_rowid"] = handle.IntValue()
-		retStr, err := json.Marshal(ret)
-		if err != nil {
-			return "", errors.Trace(err)
-		}
-		return string(retStr), nil
+		ret["table_id"] = tableID
+		ret["handle"] = handle.IntValue()
+		return ret, nil
 	}
 	if tbl != nil {
 		tblInfo := tbl.Meta()
 		idxInfo := tables.FindPrimaryIndex(tblInfo)
 		if idxInfo == nil {
-			return "", errors.Trace(errors.Errorf("primary key not found when decoding record key: %X", key))
+			return nil, errors.Trace(errors.Errorf("primary key not found when decoding record key: %X", key))
 		}
 		cols := make(map[int64]*types.FieldType, len(tblInfo.Columns))
 		for _, col := range tblInfo.Columns {
@@ -2053,20 +2155,20 @@ func decodeRecordKey(key []byte, tableID int64, tbl table.Table, loc *time.Locat
 		}
 
 		if len(handleColIDs) != handle.NumCols() {
-			return "", errors.Trace(errors.Errorf("primary key length not match handle columns number in key"))
+			return nil, errors.Trace(errors.Errorf("primary key length not match handle columns number in key"))
 		}
 		datumMap, err := tablecodec.DecodeHandleToDatumMap(handle, handleColIDs, cols, loc, nil)
 		if err != nil {
-			return "", errors.Trace(err)
+			return nil, errors.Trace(err)
 		}
 		ret := make(map[string]interface{})
 		ret["table_id"] = tableID
 		handleRet := make(map[string]interface{})
 		for colID := range datumMap {
 			dt := datumMap[colID]
-			dtStr, err := datumToJSONObject(&dt)
+			dtStr, err := expression.DatumToJSONObject(&dt)
 			if err != nil {
-				return "", errors.Trace(err)
+				return nil, errors.Trace(err)
 			}
 			found := false
 			for _, colInfo := range tblInfo.Columns {
@@ -2077,31 +2179,23 @@ func decodeRecordKey(key []byte, tableID int64, tbl table.Table, loc *time.Locat
 				}
 			}
 			if !found {
-				return "", errors.Trace(errors.Errorf("column not found when decoding record key: %X", key))
+				return nil, errors.Trace(errors.Errorf("column not found when decoding record key: %X", key))
 			}
 		}
 		ret["handle"] = handleRet
-		retStr, err := json.Marshal(ret)
-		if err != nil {
-			return "", errors.Trace(err)
-		}
-		return string(retStr), nil
+		return ret, nil
 	}
 	ret := make(map[string]interface{})
 	ret["table_id"] = tableID
 	ret["handle"] = handle.String()
-	retStr, err := json.Marshal(ret)
-	if err != nil {
-		return "", errors.Trace(err)
-	}
-	return string(retStr), nil
+	return ret, nil
 }
 
-func decodeIndexKey(key []byte, tableID int64, tbl table.Table, loc *time.Location) (string, error) {
+func decodeIndexKey(key []byte, tableID int64, tbl table.Table, loc *time.Location) (map[string]interface{}, error) {
 	if tbl != nil {
 		_, indexID, _, err := tablecodec.DecodeKeyHead(key)
 		if err != nil {
-			return "", errors.Trace(errors.Errorf("invalid record/index key: %X", key))
+			return nil, errors.Trace(errors.Errorf("invalid record/index key: %X", key))
 		}
 		tblInfo := tbl.Meta()
 		var targetIndex *model.IndexInfo
@@ -2112,19 +2206,19 @@ func decodeIndexKey(key []byte, tableID int64, tbl table.Table, loc *time.Locati
 			}
 		}
 		if targetIndex == nil {
-			return "", errors.Trace(errors.Errorf("index not found when decoding index key: %X", key))
+			return nil, errors.Trace(errors.Errorf("index not found when decoding index key: %X", key))
 		}
 		colInfos := tables.BuildRowcodecColInfoForIndexColumns(targetIndex, tblInfo)
 		tps := tables.BuildFieldTypesForIndexColumns(targetIndex, tblInfo)
 		values, err := tablecodec.DecodeIndexKV(key, []byte{0}, len(colInfos), tablecodec.HandleNotNeeded, colInfos)
 		if err != nil {
-			return "", errors.Trace(err)
+			return nil, errors.Trace(err)
 		}
 		ds := make([]types.Datum, 0, len(colInfos))
 		for i := 0; i < len(colInfos); i++ {
 			d, err := tablecodec.DecodeColumnValue(values[i], tps[i], loc)
 			if err != nil {
-				return "", errors.Trace(err)
+				return nil, errors.Trace(err)
 			}
 			ds = append(ds, d)
 		}
@@ -2133,46 +2227,257 @@ func decodeIndexKey(key []byte, tableID int64, tbl table.Table, loc *time.Locati
 		ret["index_id"] = indexID
 		idxValMap := make(map[string]interface{}, len(targetIndex.Columns))
 		for i := 0; i < len(targetIndex.Columns); i++ {
-			dtStr, err := datumToJSONObject(&ds[i])
+			dtStr, err := expression.DatumToJSONObject(&ds[i])
 			if err != nil {
-				return "", errors.Trace(err)
+				return nil, errors.Trace(err)
 			}
 			idxValMap[targetIndex.Columns[i].Name.L] = dtStr
 		}
-		ret["index_vals"] = idxValMap
-		retStr, err := json.Marshal(ret)
-		if err != nil {
-			return "", errors.Trace(err)
-		}
-		return string(retStr), nil
+		ret["index_values"] = idxValMap
+		return ret, nil
 	}
 	_, indexID, indexValues, err := tablecodec.DecodeIndexKey(key)
 	if err != nil {
-		return "", errors.Trace(errors.Errorf("invalid index key: %X", key))
+		return nil, errors.Trace(errors.Errorf("invalid index key: %X", key))
 	}
 	ret := make(map[string]interface{})
 	ret["table_id"] = tableID
 	ret["index_id"] = indexID
-	ret["index_vals"] = strings.Join(indexValues, ", ")
-	retStr, err := json.Marshal(ret)
+	ret["index_values"] = strings.Join(indexValues, ", ")
+	return ret, nil
+}
+
+func decodeTableKey(key []byte, tableID int64, tbl table.Table, loc *time.Location) (map[string]interface{}, error) {
+	return map[string]interface{}{"table_id": tableID}, nil
+}
+
+// explainRowJSON is a row of the plan returned by TIDB_PARSE_AND_EXPLAIN().
+type explainRowJSON struct {
+	ID           string `json:"id"`
+	EstRows      string `json:"estRows"`
+	TaskType     string `json:"task"`
+	AccessObject string `json:"accessObject"`
+	OperatorInfo string `json:"operatorInfo"`
+}
+
+// parseAndExplain parses and optimizes a read-only statement without executing it, and returns
+// its explain rows in JSON.
+func parseAndExplain(ctx sessionctx.Context, sql string) (string, error) {
+	plan, err := parseAndOptimize(ctx, sql, ast.TiDBParseAndExplain)
 	if err != nil {
-		return "", errors.Trace(err)
+		return "", err
+	}
+	rows := GetExplainRowsForPlan(plan)
+	res := make([]explainRowJSON, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, explainRowJSON{
+			ID:           row[0],
+			EstRows:      row[1],
+			TaskType:     row[2],
+			AccessObject: row[3],
+			OperatorInfo: row[4],
+		})
+	}
+	js, err := json.Marshal(res)
+	if err != nil {
+		return "", err
 	}
-	return string(retStr), nil
+	return string(js), nil
 }
 
-func decodeTableKey(key []byte, tableID int64, tbl table.Table, loc *time.Location) (string, error) {
-	ret := map[string]int64{"table_id": tableID}
-	retStr, err := json.Marshal(ret)
+// parseAndEstimateCost returns the estimated cost of the root operator of the plan chosen for the read-only statement.
+func parseAndEstimateCost(ctx sessionctx.Context, sql string) (float64, error) {
+	plan, err := parseAndOptimize(ctx, sql, ast.TiDBEstimateCost)
 	if err != nil {
-		return "", errors.Trace(err)
+		return 0, err
+	}
+	pp, ok := plan.(PhysicalPlan)
+	if !ok {
+		return 0, errors.Errorf("%s doesn't support the plan %s", ast.TiDBEstimateCost, plan.TP())
+	}
+	return pp.Cost(), nil
+}
+
+// parseAndOptimize parses the read-only statement and optimizes it with the current session, the statement is
+// never executed. The privileges are checked by the optimizer as the statement is executed directly.
+func parseAndOptimize(ctx sessionctx.Context, sql string, funcName string) (plan Plan, err error) {
+	vars := ctx.GetSessionVars()
+	p := parser.New()
+	p.SetParserConfig(vars.BuildParserConfig())
+	p.SetSQLMode(vars.SQLMode)
+	charsetInfo, collation := vars.GetCharsetInfo()
+	stmt, err := p.ParseOneStmt(sql, charsetInfo, collation)
+	if err != nil {
+		return nil, err
+	}
+	if !ast.IsReadOnly(stmt) {
+		return nil, errors.Errorf("%s only supports read-only statements", funcName)
+	}
+	if OptimizeAstNode == nil {
+		return nil, errors.Errorf("%s is not supported in this context", funcName)
+	}
+	is := ctx.GetInfoSchema().(infoschema.InfoSchema)
+	err = runWithDetachedStmtCtx(ctx, func() error {
+		if err := Preprocess(ctx, stmt, WithPreprocessorReturn(&PreprocessorReturn{InfoSchema: is})); err != nil {
+			return err
+		}
+		plan, _, err = OptimizeAstNode(context.Background(), ctx, stmt, is)
+		return err
+	})
+	return plan, err
+}
+
+// runWithDetachedStmtCtx runs f with a new statement context, and restores the state of the running statement
+// afterwards. A statement optimized inside a builtin function would otherwise overwrite the hints, the tables,
+// the rewrite phase info and the variables set by SET_VAR hints of the running statement. The warnings are kept.
+func runWithDetachedStmtCtx(ctx sessionctx.Context, f func() error) error {
+	vars := ctx.GetSessionVars()
+	origStmtCtx, origRewritePhaseInfo := vars.StmtCtx, vars.RewritePhaseInfo
+	origFoundInBinding, origFoundInPlanCache := vars.FoundInBinding, vars.FoundInPlanCache
+	origStmtVars := vars.SwapStmtVars(make(map[string]string))
+	sc := &stmtctx.StatementContext{
+		TimeZone:               origStmtCtx.TimeZone,
+		InSelectStmt:           true,
+		InExplainStmt:          true,
+		IgnoreTruncate:         origStmtCtx.IgnoreTruncate,
+		TruncateAsWarning:      origStmtCtx.TruncateAsWarning,
+		OverflowAsWarning:      origStmtCtx.OverflowAsWarning,
+		IgnoreZeroInDate:       origStmtCtx.IgnoreZeroInDate,
+		DividedByZeroAsWarning: origStmtCtx.DividedByZeroAsWarning,
+		IsStaleness:            origStmtCtx.IsStaleness,
+		TaskID:                 stmtctx.AllocateTaskID(),
+	}
+	sc.InitMemTracker(memory.LabelForSQLText, -1)
+	sc.InitDiskTracker(memory.LabelForSQLText, -1)
+	vars.StmtCtx = sc
+	defer func() {
+		origStmtCtx.AppendWarnings(sc.GetWarnings())
+		vars.StmtCtx, vars.RewritePhaseInfo = origStmtCtx, origRewritePhaseInfo
+		vars.FoundInBinding, vars.FoundInPlanCache = origFoundInBinding, origFoundInPlanCache
+		vars.SwapStmtVars(origStmtVars)
+	}()
+	return f()
+}
+
+// selectivityPredicateChecker checks that the predicate of TIDB_ESTIMATE_INDEX_SELECTIVITY() only consists of the
+// columns, the constants and the operators on them.
+type selectivityPredicateChecker struct {
+	valid bool
+}
+
+// Enter implements Visitor interface.
+func (c *selectivityPredicateChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case *ast.BinaryOperationExpr, *ast.UnaryOperationExpr, *ast.ParenthesesExpr, *ast.BetweenExpr,
+		*ast.IsNullExpr, *ast.IsTruthExpr, *ast.PatternLikeExpr, *ast.ColumnNameExpr, *ast.ColumnName, ast.ValueExpr:
+		return in, false
+	case *ast.PatternInExpr:
+		if x.Sel == nil {
+			return in, false
+		}
+	}
+	c.valid = false
+	return in, true
+}
+
+// Leave implements Visitor interface.
+func (c *selectivityPredicateChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, c.valid
+}
+
+// isSingleExprSelect checks that the SELECT only has a single unnamed field, without any other clause.
+func isSingleExprSelect(sel *ast.SelectStmt) bool {
+	if sel.Kind != ast.SelectStmtKindSelect || sel.Fields == nil || len(sel.Fields.Fields) != 1 {
+		return false
+	}
+	field := sel.Fields.Fields[0]
+	if field.WildCard != nil || field.Expr == nil || field.AsName.L != "" {
+		return false
 	}
-	return string(retStr), nil
+	return !sel.Distinct && sel.From == nil && sel.Where == nil && sel.GroupBy == nil && sel.Having == nil &&
+		len(sel.WindowSpecs) == 0 && sel.OrderBy == nil && sel.Limit == nil && sel.LockInfo == nil &&
+		len(sel.TableHints) == 0 && sel.SelectIntoOpt == nil && sel.With == nil && !sel.IsInBraces
 }
 
-func datumToJSONObject(d *types.Datum) (interface{}, error) {
-	if d.IsNull() {
-		return nil, nil
+// estimateIndexSelectivity returns the estimated fraction of the rows of the table which are read by the range scan
+// on the index, if the predicate is used as the where condition of the table. The estimation is the same as the one
+// used by the optimizer to decide the access path.
+func estimateIndexSelectivity(ctx sessionctx.Context, dbName string, tblInfo *model.TableInfo, idxInfo *model.IndexInfo, predicate string) (float64, error) {
+	vars := ctx.GetSessionVars()
+	p := parser.New()
+	p.SetParserConfig(vars.BuildParserConfig())
+	p.SetSQLMode(vars.SQLMode)
+	charsetInfo, collation := vars.GetCharsetInfo()
+	// The predicate is parsed as the only field of a SELECT without any other clause, so nothing but the expression
+	// is taken from it. The parse error is not returned, since its position is relative to the wrapped statement.
+	predStmt, err := p.ParseOneStmt("SELECT "+predicate, charsetInfo, collation)
+	if err != nil {
+		return 0, errors.Errorf("invalid predicate: '%s'", predicate)
+	}
+	predSel, ok := predStmt.(*ast.SelectStmt)
+	if !ok || !isSingleExprSelect(predSel) {
+		return 0, errors.Errorf("invalid predicate: '%s'", predicate)
+	}
+	cond := predSel.Fields.Fields[0].Expr
+	// Only the columns and the constants are allowed in the predicate, the subqueries would be executed without
+	// the privilege check when the plan is built.
+	checker := &selectivityPredicateChecker{valid: true}
+	cond.Accept(checker)
+	if !checker.valid {
+		return 0, errors.Errorf("invalid predicate: '%s', only columns and constants are allowed", predicate)
+	}
+	tn := &ast.TableName{Schema: model.NewCIStr(dbName), Name: tblInfo.Name}
+	stmt := &ast.SelectStmt{
+		SelectStmtOpts: &ast.SelectStmtOpts{SQLCache: true},
+		Fields:         &ast.FieldList{Fields: []*ast.SelectField{{WildCard: &ast.WildCardField{}}}},
+		From:           &ast.TableRefsClause{TableRefs: &ast.Join{Left: &ast.TableSource{Source: tn}}},
+		Where:          cond,
+		Kind:           ast.SelectStmtKindSelect,
+	}
+	is := ctx.GetInfoSchema().(infoschema.InfoSchema)
+	var lp LogicalPlan
+	err = runWithDetachedStmtCtx(ctx, func() error {
+		if err := Preprocess(ctx, stmt, WithPreprocessorReturn(&PreprocessorReturn{InfoSchema: is})); err != nil {
+			return err
+		}
+		builder, _ := NewPlanBuilder().Init(ctx, is, &hint.BlockHintProcessor{})
+		plan, err := builder.Build(context.Background(), stmt)
+		if err != nil {
+			return err
+		}
+		var ok bool
+		if lp, ok = plan.(LogicalPlan); !ok {
+			return errors.Errorf("invalid predicate: '%s'", predicate)
+		}
+		lp, err = (&ppdSolver{}).optimize(context.Background(), lp, defaultLogicalOptimizeOption())
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	// The predicate must be a filter on the table only, so the plan is a DataSource with the projection above it.
+	var ds *DataSource
+	for ds == nil {
+		switch x := lp.(type) {
+		case *LogicalProjection, *LogicalSelection:
+			lp = x.Children()[0]
+		case *DataSource:
+			ds = x
+		default:
+			return 0, errors.Errorf("invalid predicate: '%s'", predicate)
+		}
+	}
+	if _, err = ds.recursiveDeriveStats(nil); err != nil {
+		return 0, err
+	}
+	for _, path := range ds.possibleAccessPaths {
+		if path.Index == nil || path.Index.ID != idxInfo.ID {
+			continue
+		}
+		if ds.statisticTable.Count == 0 {
+			return 0, nil
+		}
+		return math.Min(path.CountAfterAccess/float64(ds.statisticTable.Count), 1), nil
 	}
-	return d.ToString()
+	return 0, errors.Errorf("index %s is not available for the estimation", idxInfo.Name.O)
 }
@@ -762,6 +762,7 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 	}()
 
 	cntPlan = 0
+	costTracer := newCandidateCostTracer(ds, prop)
 	for _, candidate := range candidates {
 		path := candidate.path
 		if path.PartialIndexPaths != nil {
@@ -769,6 +770,7 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 			if err != nil {
 				return nil, 0, err
 			}
+			costTracer.record("index merge", idxMergeTask)
 			if !idxMergeTask.invalid() {
 				cntPlan += 1
 				planCounter.Dec(1)
@@ -796,18 +798,32 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 			}, cntPlan, nil
 		}
 		canConvertPointGet := len(path.Ranges) > 0 && path.StoreType == kv.TiKV && ds.isPointGetConvertableSchema()
+		// pointGetMissReason tells why a handle or unique index path on TiKV isn't converted to [batch] point get.
+		var pointGetMissReason string
+		if !canConvertPointGet && path.StoreType == kv.TiKV {
+			pointGetMissReason = "not all the columns of the table are public"
+		}
 
 		if canConvertPointGet && expression.MaybeOverOptimized4PlanCache(ds.ctx, path.AccessConds) {
 			canConvertPointGet = ds.canConvertToPointGetForPlanCache(path)
+			if !canConvertPointGet {
+				pointGetMissReason = "the ranges built from the parameters may not be points when the cached plan is reused"
+			}
 		}
 
 		if canConvertPointGet && !path.IsIntHandlePath {
 			// We simply do not build [batch] point get for prefix indexes. This can be optimized.
 			canConvertPointGet = path.Index.Unique && !path.Index.HasPrefixIndex()
+			if path.Index.Unique && !canConvertPointGet {
+				pointGetMissReason = fmt.Sprintf("index[%s] has prefix columns", path.Index.Name.O)
+			}
 			// If any range cannot cover all columns of the index, we cannot build [batch] point get.
 			idxColsLen := len(path.Index.Columns)
 			for _, ran := range path.Ranges {
 				if len(ran.LowVal) != idxColsLen {
+					if canConvertPointGet {
+						pointGetMissReason = fmt.Sprintf("the ranges don't cover all the columns of index[%s]", path.Index.Name.O)
+					}
 					canConvertPointGet = false
 					break
 				}
@@ -818,6 +834,7 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 			// We do not build [batch] point get for dynamic table partitions now. This can be optimized.
 			if ds.ctx.GetSessionVars().UseDynamicPartitionPrune() {
 				canConvertPointGet = false
+				pointGetMissReason = "the dynamic partition pruning is enabled"
 			}
 			if canConvertPointGet && len(path.Ranges) > 1 {
 				// We can only build batch point get for hash partitions on a simple column now. This is
@@ -827,6 +844,7 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 				hashPartColName = getHashPartitionColumnName(ds.ctx, tblInfo)
 				if hashPartColName == nil {
 					canConvertPointGet = false
+					pointGetMissReason = "batch point get only supports the tables hash partitioned by a column"
 				}
 			}
 			if canConvertPointGet {
@@ -835,6 +853,7 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 				for _, col := range ds.schema.Columns {
 					if col.ID == model.ExtraPidColID {
 						canConvertPointGet = false
+						pointGetMissReason = "the partition ID column is needed"
 						break
 					}
 				}
@@ -846,6 +865,7 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 				if !ran.IsPointNonNullable(ds.ctx) {
 					// unique indexes can have duplicated NULL rows so we cannot use PointGet if there is NULL
 					allRangeIsPoint = false
+					pointGetMissReason = fmt.Sprintf("the ranges%v aren't all points without NULL", path.Ranges)
 					break
 				}
 			}
@@ -856,6 +876,7 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 				} else {
 					pointGetTask = ds.convertToBatchPointGet(prop, candidate, hashPartColName)
 				}
+				costTracer.record(pointGetPathName(path), pointGetTask)
 				if !pointGetTask.invalid() {
 					cntPlan += 1
 					planCounter.Dec(1)
@@ -869,6 +890,10 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 				}
 			}
 		}
+		// Only the handle and unique index paths restricted by some predicates are near misses of [batch] point get.
+		if pointGetMissReason != "" && len(path.AccessConds) > 0 && (path.IsIntHandlePath || path.Index.Unique) {
+			costTracer.recordPointGetMiss(path, pointGetMissReason)
+		}
 		if path.IsTablePath() {
 			if ds.preferStoreType&preferTiFlash != 0 && path.StoreType == kv.TiKV {
 				continue
@@ -885,6 +910,7 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 			if err != nil {
 				return nil, 0, err
 			}
+			costTracer.record(candidatePathName(path), tblTask)
 			if !tblTask.invalid() {
 				cntPlan += 1
 				planCounter.Dec(1)
@@ -905,6 +931,7 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 		if err != nil {
 			return nil, 0, err
 		}
+		costTracer.record(candidatePathName(path), idxTask)
 		if !idxTask.invalid() {
 			cntPlan += 1
 			planCounter.Dec(1)
@@ -916,6 +943,7 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 			return t, cntPlan, nil
 		}
 	}
+	costTracer.appendTraceStep(t)
 
 	return
 }
@@ -4982,3 +4982,32 @@ func (s *testIntegrationSerialSuite) TestIssue30271(c *C) {
 	tk.MustQuery("select * from t where (a>'a' and b='a') or (b = 'A' and a < 'd') order by a,c;").Check(testkit.Rows("b a 1", "b A 2", "c a 3"))
 
 }
+
+func (s *testIntegrationSuite) TestSemiJoinDistinctEliminate(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int, b int)")
+	tk.MustExec("create table t2(c int, d int)")
+	tk.MustExec("insert into t1 values (1, 1), (2, 2), (3, 3), (4, null)")
+	tk.MustExec("insert into t2 values (1, 1), (1, 1), (2, 2), (2, 3)")
+
+	// The DISTINCT on the inner side of the semi join doesn't change the result.
+	for _, distinct := range []string{"distinct", ""} {
+		tk.MustQuery(fmt.Sprintf("select a from t1 where b in (select %s c from t2) order by a", distinct)).Check(testkit.Rows("1", "2"))
+		tk.MustQuery(fmt.Sprintf("select a from t1 where b not in (select %s c from t2) order by a", distinct)).Check(testkit.Rows("3"))
+		tk.MustQuery(fmt.Sprintf("select a, b in (select %s c from t2), b not in (select %s c from t2) from t1 order by a", distinct, distinct)).Check(testkit.Rows(
+			"1 1 0", "2 1 0", "3 0 1", "4 <nil> <nil>"))
+		tk.MustQuery(fmt.Sprintf("select a from t1 where b in (select %s c from t2 where t2.d = t1.a) order by a", distinct)).Check(testkit.Rows("1", "2"))
+		tk.MustQuery(fmt.Sprintf("select a from t1 where b not in (select %s c from t2 where t2.d = t1.a) order by a", distinct)).Check(testkit.Rows("3", "4"))
+	}
+
+	// NOT IN returns NULL for every outer row once the inner side contains NULL.
+	tk.MustExec("insert into t2 values (null, 4), (null, 4)")
+	for _, distinct := range []string{"distinct", ""} {
+		tk.MustQuery(fmt.Sprintf("select a from t1 where b not in (select %s c from t2)", distinct)).Check(testkit.Rows())
+		tk.MustQuery(fmt.Sprintf("select a, b not in (select %s c from t2) from t1 order by a", distinct)).Check(testkit.Rows(
+			"1 0", "2 0", "3 <nil>", "4 <nil>"))
+		tk.MustQuery(fmt.Sprintf("select a from t1 where b not in (select %s c from t2 where t2.d = t1.a) order by a", distinct)).Check(testkit.Rows("3"))
+	}
+}
@@ -1440,6 +1440,18 @@ func (b *PlanBuilder) buildSetOpr(ctx context.Context, setOpr *ast.SetOprStmt) (
 		}
 	}
 
+	// Only the outermost set operation counts the found rows for SQL_CALC_FOUND_ROWS,
+	// which MySQL takes from its first SELECT.
+	calcFoundRows := false
+	if first, ok := setOpr.SelectList.Selects[0].(*ast.SelectStmt); ok && !b.inSetOprBranch && b.getSelectOffset() == -1 {
+		calcFoundRows = first.SelectStmtOpts != nil && first.SelectStmtOpts.CalcFoundRows
+	}
+	oldInSetOprBranch := b.inSetOprBranch
+	b.inSetOprBranch = true
+	defer func() {
+		b.inSetOprBranch = oldInSetOprBranch
+	}()
+
 	// Because INTERSECT has higher precedence than UNION and EXCEPT. We build it first.
 	selectPlans := make([]LogicalPlan, 0, len(setOpr.SelectList.Selects))
 	afterSetOprs := make([]*ast.SetOprType, 0, len(setOpr.SelectList.Selects))
@@ -1491,7 +1503,7 @@ func (b *PlanBuilder) buildSetOpr(ctx context.Context, setOpr *ast.SetOprStmt) (
 	}
 
 	if setOpr.Limit != nil {
-		setOprPlan, err = b.buildLimit(setOprPlan, setOpr.Limit)
+		setOprPlan, err = b.buildLimit(setOprPlan, setOpr.Limit, calcFoundRows)
 		if err != nil {
 			return nil, err
 		}
@@ -1851,7 +1863,9 @@ func extractLimitCountOffset(ctx sessionctx.Context, limit *ast.Limit) (count ui
 	return count, offset, nil
 }
 
-func (b *PlanBuilder) buildLimit(src LogicalPlan, limit *ast.Limit) (LogicalPlan, error) {
+// buildLimit builds the LogicalLimit for the limit clause. If calcFoundRows is true, the
+// statement carries SQL_CALC_FOUND_ROWS, so the limit has to count all the rows of its child.
+func (b *PlanBuilder) buildLimit(src LogicalPlan, limit *ast.Limit, calcFoundRows bool) (LogicalPlan, error) {
 	b.optFlag = b.optFlag | flagPushDownTopN
 	var (
 		offset, count uint64
@@ -1864,15 +1878,16 @@ func (b *PlanBuilder) buildLimit(src LogicalPlan, limit *ast.Limit) (LogicalPlan
 	if count > math.MaxUint64-offset {
 		count = math.MaxUint64 - offset
 	}
-	if offset+count == 0 {
+	if offset+count == 0 && !calcFoundRows {
 		tableDual := LogicalTableDual{RowCount: 0}.Init(b.ctx, b.getSelectOffset())
 		tableDual.schema = src.Schema()
 		tableDual.names = src.OutputNames()
 		return tableDual, nil
 	}
 	li := LogicalLimit{
-		Offset: offset,
-		Count:  count,
+		Offset:        offset,
+		Count:         count,
+		calcFoundRows: calcFoundRows,
 	}.Init(b.ctx, b.getSelectOffset())
 	if hint := b.TableHints(); hint != nil {
 		li.limitHints = hint.limitHints
@@ -3509,14 +3524,6 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p L
 	}
 	noopFuncsMode := b.ctx.GetSessionVars().NoopFuncsMode
 	if sel.SelectStmtOpts != nil {
-		if sel.SelectStmtOpts.CalcFoundRows && noopFuncsMode != variable.OnInt {
-			err = expression.ErrFunctionsNoopImpl.GenWithStackByArgs("SQL_CALC_FOUND_ROWS")
-			if noopFuncsMode == variable.OffInt {
-				return nil, err
-			}
-			// NoopFuncsMode is Warn, append an error
-			b.ctx.GetSessionVars().StmtCtx.AppendWarning(err)
-		}
 		origin := b.inStraightJoin
 		b.inStraightJoin = sel.SelectStmtOpts.StraightJoin
 		defer func() { b.inStraightJoin = origin }()
@@ -3743,7 +3750,10 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p L
 	}
 
 	if sel.Limit != nil {
-		p, err = b.buildLimit(p, sel.Limit)
+		// Only the outermost query block counts the found rows for SQL_CALC_FOUND_ROWS,
+		// the branches of a set operation leave it to the LIMIT of the set operation.
+		calcFoundRows := sel.SelectStmtOpts != nil && sel.SelectStmtOpts.CalcFoundRows && b.getSelectOffset() <= 1 && !b.inSetOprBranch
+		p, err = b.buildLimit(p, sel.Limit, calcFoundRows)
 		if err != nil {
 			return nil, err
 		}
@@ -4718,7 +4728,7 @@ func (b *PlanBuilder) buildUpdate(ctx context.Context, update *ast.UpdateStmt) (
 		}
 	}
 	if update.Limit != nil {
-		p, err = b.buildLimit(p, update.Limit)
+		p, err = b.buildLimit(p, update.Limit, false)
 		if err != nil {
 			return nil, err
 		}
@@ -5058,7 +5068,7 @@ func (b *PlanBuilder) buildDelete(ctx context.Context, delete *ast.DeleteStmt) (
 	}
 
 	if delete.Limit != nil {
-		p, err = b.buildLimit(p, delete.Limit)
+		p, err = b.buildLimit(p, delete.Limit, false)
 		if err != nil {
 			return nil, err
 		}
@@ -6351,7 +6361,7 @@ func (b *PlanBuilder) buildRecursiveCTE(ctx context.Context, cte ast.ResultSetNo
 		cInfo.recurLP = recurPart
 		// Only need to handle limit if x is SetOprStmt.
 		if x.Limit != nil {
-			limit, err := b.buildLimit(cInfo.seedLP, x.Limit)
+			limit, err := b.buildLimit(cInfo.seedLP, x.Limit, false)
 			if err != nil {
 				return err
 			}
@@ -19,8 +19,13 @@ import (
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/hint"
 	"github.com/pingcap/tidb/util/testleak"
+	"github.com/pingcap/tidb/util/tracing"
 )
 
 func (s *testPlanSuite) TestLogicalOptimizeWithTraceEnabled(c *C) {
@@ -80,12 +85,67 @@ func (s *testPlanSuite) TestLogicalOptimizeWithTraceEnabled(c *C) {
 
 func (s *testPlanSuite) TestSingleRuleTraceStep(c *C) {
 	defer testleak.AfterTest(c)()
+	// The uncorrelated scalar subqueries are evaluated by the executor when building the plan, which is mocked here.
+	evalSubqueryFirstRowBackup := EvalSubqueryFirstRow
+	EvalSubqueryFirstRow = func(context.Context, PhysicalPlan, infoschema.InfoSchema, sessionctx.Context) ([]types.Datum, error) {
+		return []types.Datum{types.NewIntDatum(10)}, nil
+	}
+	defer func() {
+		EvalSubqueryFirstRow = evalSubqueryFirstRowBackup
+	}()
 	tt := []struct {
 		sql             string
 		flags           []uint64
 		assertRuleName  string
 		assertRuleSteps []assertTraceStep
 	}{
+		{
+			// The IN-subquery is rewritten to a join with a DISTINCT on its inner side, which is redundant with the DISTINCT of the subquery.
+			sql:            "select * from t where b in (select distinct c from t)",
+			flags:          []uint64{flagBuildKeyInfo, flagEliminateAgg},
+			assertRuleName: "aggregation_eliminate",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "[test.t.c] is a unique key",
+					assertAction: "aggregation is simplified to a projection",
+				},
+			},
+		},
+		{
+			sql:            "select a from (select a, b, c from t union all select a, b, d from t) tmp",
+			flags:          []uint64{flagPrunColumns},
+			assertRuleName: "column_prune",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "the parent of Projection_6 only uses its columns[test.t.a]",
+					assertAction: "the columns[test.t.b,test.t.c] of Projection_6 are pruned, and the columns[test.t.a] are kept",
+				},
+				{
+					assertReason: "the parent of Projection_2 only uses its columns[test.t.a]",
+					assertAction: "the columns[test.t.b,test.t.c] of Projection_2 are pruned, and the columns[test.t.a] are kept",
+				},
+				{
+					assertReason: "the parent of DataSource_1 only uses its columns[test.t.a]",
+					assertAction: "the columns[test.t.b,test.t.c,test.t.d,test.t.e,test.t.c_str,test.t.d_str,test.t.e_str,test.t.f,test.t.g,test.t.h,test.t.i_date] of DataSource_1 are pruned, and the columns[test.t.a] are kept",
+				},
+				{
+					assertReason: "the parent of Projection_7 only uses its columns[test.t.a]",
+					assertAction: "the columns[test.t.b,test.t.d] of Projection_7 are pruned, and the columns[test.t.a] are kept",
+				},
+				{
+					assertReason: "the parent of Projection_4 only uses its columns[test.t.a]",
+					assertAction: "the columns[test.t.b,test.t.d] of Projection_4 are pruned, and the columns[test.t.a] are kept",
+				},
+				{
+					assertReason: "the parent of DataSource_3 only uses its columns[test.t.a]",
+					assertAction: "the columns[test.t.b,test.t.c,test.t.d,test.t.e,test.t.c_str,test.t.d_str,test.t.e_str,test.t.f,test.t.g,test.t.h,test.t.i_date] of DataSource_3 are pruned, and the columns[test.t.a] are kept",
+				},
+				{
+					assertReason: "the parent of Union_5 only uses its columns[Column#25]",
+					assertAction: "the columns[Column#26,Column#27] of Union_5 are pruned, and the columns[Column#25] are kept",
+				},
+			},
+		},
 		{
 			sql:            "select min(distinct a) from t group by a",
 			flags:          []uint64{flagBuildKeyInfo, flagEliminateAgg},
@@ -101,6 +161,28 @@ func (s *testPlanSuite) TestSingleRuleTraceStep(c *C) {
 				},
 			},
 		},
+		{
+			sql:            "select count(distinct a) from t group by b",
+			flags:          []uint64{flagBuildKeyInfo, flagEliminateAgg},
+			assertRuleName: "aggregation_eliminate",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "[test.t.a] is a unique key, so the arguments of count(distinct ...) have no duplicated values",
+					assertAction: "count(distinct ...) is simplified to count(...)",
+				},
+			},
+		},
+		{
+			sql:            "select sum(distinct f) from t group by b",
+			flags:          []uint64{flagBuildKeyInfo, flagEliminateAgg},
+			assertRuleName: "aggregation_eliminate",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "[test.t.f] is a unique key, so the arguments of sum(distinct ...) have no duplicated values",
+					assertAction: "sum(distinct ...) is simplified to sum(...)",
+				},
+			},
+		},
 		{
 			sql:            "select 1+num from (select 1+a as num from t) t1;",
 			flags:          []uint64{flagEliminateProjection},
@@ -149,11 +231,11 @@ func (s *testPlanSuite) TestSingleRuleTraceStep(c *C) {
 			assertRuleSteps: []assertTraceStep{
 				{
 					assertAction: "add sort[8],add limit[9] during eliminating agg[4] max function",
-					assertReason: "agg[4] has only one function[max] without group by, the columns in agg[4] should be sorted",
+					assertReason: "agg[4] has only one function[max] without group by, the columns in agg[4] are NOT NULL so no selection is needed to filter NULL out, the columns in agg[4] should be sorted",
 				},
 				{
 					assertAction: "add sort[10],add limit[11] during eliminating agg[6] min function",
-					assertReason: "agg[6] has only one function[min] without group by, the columns in agg[6] should be sorted",
+					assertReason: "agg[6] has only one function[min] without group by, the columns in agg[6] are NOT NULL so no selection is needed to filter NULL out, the columns in agg[6] should be sorted",
 				},
 				{
 					assertAction: "agg[2] splited into aggs[4,6], and add joins[12] to connect them during eliminating agg[2] multi min/max functions",
@@ -161,6 +243,28 @@ func (s *testPlanSuite) TestSingleRuleTraceStep(c *C) {
 				},
 			},
 		},
+		{
+			sql:            "select max(a), max(b) from t",
+			flags:          []uint64{flagBuildKeyInfo, flagPrunColumns, flagMaxMinEliminate},
+			assertRuleName: "max_min_eliminate",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertAction: "agg[2] isn't split into single min/max aggs, so its multi min/max functions aren't eliminated",
+					assertReason: "the column[test.t.b] of function[max] in agg[2] can't benefit from any index/primary key",
+				},
+			},
+		},
+		{
+			sql:            "select max(a) from t",
+			flags:          []uint64{flagBuildKeyInfo, flagPrunColumns, flagMaxMinEliminate},
+			assertRuleName: "max_min_eliminate",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertAction: "add sort[4],add limit[5] during eliminating agg[2] max function",
+					assertReason: "agg[2] has only one function[max] without group by, the columns in agg[2] are NOT NULL so no selection is needed to filter NULL out, the columns in agg[2] should be sorted",
+				},
+			},
+		},
 		{
 			sql:            "select max(e) from t",
 			flags:          []uint64{flagBuildKeyInfo, flagPrunColumns, flagMaxMinEliminate},
@@ -172,6 +276,464 @@ func (s *testPlanSuite) TestSingleRuleTraceStep(c *C) {
 				},
 			},
 		},
+		{
+			sql:            "select a from t t1 where t1.b > (select t2.b from t t2 where t2.c = t1.c order by t2.b limit 1)",
+			flags:          []uint64{flagBuildKeyInfo, flagDecorrelate},
+			assertRuleName: "decorrelate",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "the scalar subquery refers to the outer columns[test.t.c]",
+					assertAction: "MaxOneRow_8 is built as the inner side of Apply_9, which evaluates it for each outer row unless it's decorrelated",
+				},
+				{
+					assertReason: "Limit_7 returns at most one row",
+					assertAction: "MaxOneRow_8 in the inner side of Apply_9 is removed",
+				},
+				{
+					assertReason: "Limit_7 in the inner side of Apply_9 can't be pulled up, which blocks the decorrelation",
+					assertAction: "Apply_9 is kept and not decorrelated",
+				},
+			},
+		},
+		{
+			sql:            "select * from t where a = 1 and b = a",
+			flags:          []uint64{flagPredicatePushDown},
+			assertRuleName: "constant_propagation",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "the conditions[eq(test.t.a, 1),eq(test.t.b, test.t.a)] of DataSource_1 contain equalities to constants or columns, so the constants can be propagated into the other conditions",
+					assertAction: "the conditions[eq(test.t.a, 1),eq(test.t.b, test.t.a)] are rewritten to [eq(test.t.a, 1),eq(test.t.b, 1)]",
+				},
+			},
+		},
+		{
+			sql:            "select * from t where a = 1 and b = a and b = 2",
+			flags:          []uint64{flagPredicatePushDown},
+			assertRuleName: "constant_propagation",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "the conditions[eq(test.t.a, 1),eq(test.t.b, test.t.a),eq(test.t.b, 2)] of DataSource_1 contain equalities to constants or columns, so the constants can be propagated into the other conditions",
+					assertAction: "the conditions[eq(test.t.a, 1),eq(test.t.b, test.t.a),eq(test.t.b, 2)] are constant after the propagation, so they are folded into [0]",
+				},
+			},
+		},
+		{
+			sql:            "select * from t where a = 1 and b > 1 and (a = 1 or b = 2)",
+			flags:          []uint64{flagPredicatePushDown},
+			assertRuleName: "constant_propagation",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "the conditions[eq(test.t.a, 1),gt(test.t.b, 1),or(eq(test.t.a, 1), eq(test.t.b, 2))] of DataSource_1 contain equalities to constants or columns, so the constants can be propagated into the other conditions",
+					assertAction: "the conditions[eq(test.t.a, 1),gt(test.t.b, 1),or(eq(test.t.a, 1), eq(test.t.b, 2))] are rewritten to [eq(test.t.a, 1),gt(test.t.b, 1),or(1, eq(test.t.b, 2))]",
+				},
+			},
+		},
+		{
+			sql:            "select t1.a from t t1 left join t t2 on t1.a = t2.a where t2.b is null or t2.c > 1",
+			flags:          []uint64{flagPredicatePushDown},
+			assertRuleName: "predicate_push_down",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "the where conditions[or(isnull(test.t.b), gt(test.t.c, 1))] refer to the inner side of Join_3 and are not null-rejecting, so they must be evaluated on the null-extended rows",
+					assertAction: "the where conditions[or(isnull(test.t.b), gt(test.t.c, 1))] are kept above Join_3 instead of being moved into its on clause",
+				},
+			},
+		},
+		{
+			sql:            "select t1.a from t t1 left join t t2 on t1.a = t2.a where t2.b > 1",
+			flags:          []uint64{flagPredicatePushDown},
+			assertRuleName: "predicate_push_down",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "the where condition[gt(test.t.b, 1)] is null-rejecting on the inner side of Join_3, so the null-extended rows are filtered out anyway",
+					assertAction: "Join_3 is converted from left outer join to inner join, and the where conditions can be moved into its on clause",
+				},
+				{
+					assertReason: "the predicates[gt(test.t.b, 1)] only refer to the columns of DataSource_2 and can be evaluated by the storage layer",
+					assertAction: "the predicates[gt(test.t.b, 1)] are pushed down into DataSource_2",
+				},
+			},
+		},
+		{
+			sql:            "select * from (select a from t union all select c_str from t) t1",
+			flags:          []uint64{flagEliminateProjection},
+			assertRuleName: "projection_eliminate",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "Proj[2]'s Exprs are all Columns",
+					assertAction: "Proj[2] is eliminated",
+				},
+				{
+					assertReason: "Proj[4]'s Exprs are all Columns",
+					assertAction: "Proj[4] is eliminated",
+				},
+			},
+		},
+		{
+			sql:            "select * from (select a from t union all select c_str from t) t1",
+			flags:          []uint64{flagEliminateProjection},
+			assertRuleName: "union_cast",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "the types[int(11)] of the columns[test.t.a] in Union[5]'s child 0 differ from the union result types[varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin]",
+					assertAction: "Proj[6] casts the columns[test.t.a] to the union result types",
+				},
+				{
+					assertReason: "the types[varchar(5) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin] of the columns[test.t.c_str] in Union[5]'s child 1 differ from the union result types[varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin]",
+					assertAction: "Proj[7] casts the columns[test.t.c_str] to the union result types",
+				},
+			},
+		},
+		{
+			sql:            "select * from (select cast(a as char(20)) from t union all select c_str from t) t1",
+			flags:          []uint64{flagEliminateProjection},
+			assertRuleName: "union_cast",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "the types[varchar(5) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin] of the columns[test.t.c_str] in Union[5]'s child 1 differ from the union result types[varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin]",
+					assertAction: "Proj[7] casts the columns[test.t.c_str] to the union result types",
+				},
+			},
+		},
+		{
+			sql:            "select /*+ force_index(t, nosuch) */ * from t force index(c_d_e) where b > 1",
+			flags:          []uint64{flagPredicatePushDown},
+			assertRuleName: "predicate_push_down",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "the predicates[gt(test.t.b, 1)] only refer to the columns of DataSource_1 and can be evaluated by the storage layer",
+					assertAction: "the predicates[gt(test.t.b, 1)] are pushed down into DataSource_1",
+				},
+				{
+					assertReason: "the index[c_d_e] is available, but no pushed down predicate refers to its first column[c], so it can only be scanned fully",
+					assertAction: "the index hint[FORCE INDEX(c_d_e)] on table[t] is honored",
+				},
+				{
+					assertReason: "the index[nosuch] doesn't exist in table[t]",
+					assertAction: "the index hint[force_index(test.t, nosuch)] on table[t] is ignored",
+				},
+			},
+		},
+		{
+			sql:            "select (select max(b) from t) from t",
+			flags:          []uint64{flagDecorrelate},
+			assertRuleName: "decorrelate",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "the scalar subquery doesn't refer to any outer column",
+					assertAction: "MaxOneRow_7 is evaluated once when building the plan and replaced by the constant[10]",
+				},
+			},
+		},
+		{
+			sql:            "select (select max(b) from t t2 where t2.c = t.c) from t",
+			flags:          []uint64{flagDecorrelate},
+			assertRuleName: "decorrelate",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "the scalar subquery refers to the outer columns[test.t.c]",
+					assertAction: "MaxOneRow_8 is built as the inner side of Apply_9, which evaluates it for each outer row unless it's decorrelated",
+				},
+				{
+					assertReason: "the inner side of Apply_9 may return more than one row, so MaxOneRow_8 can't be removed",
+					assertAction: "Apply_9 is kept and not decorrelated",
+				},
+			},
+		},
+		{
+			sql:            "select a from t where a > 1 and b < 2",
+			flags:          []uint64{flagPredicatePushDown},
+			assertRuleName: "predicate_push_down",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "the predicates[gt(test.t.a, 1),lt(test.t.b, 2)] only refer to the columns of DataSource_1 and can be evaluated by the storage layer",
+					assertAction: "the predicates[gt(test.t.a, 1),lt(test.t.b, 2)] are pushed down into DataSource_1",
+				},
+			},
+		},
+		{
+			sql:            "select a from t where b > found_rows() and c = 1",
+			flags:          []uint64{flagPredicatePushDown},
+			assertRuleName: "predicate_push_down",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "the predicates[eq(test.t.c, 1)] only refer to the columns of DataSource_1 and can be evaluated by the storage layer",
+					assertAction: "the predicates[eq(test.t.c, 1)] are pushed down into DataSource_1",
+				},
+				{
+					assertReason: "the predicates[gt(test.t.b, found_rows())] can't be evaluated by the storage layer",
+					assertAction: "the predicates[gt(test.t.b, found_rows())] are kept in the selection above DataSource_1",
+				},
+			},
+		},
+		{
+			sql:            "select * from (select b, count(*) as cnt from t group by b) t1 where t1.b > 1 and t1.cnt > 1",
+			flags:          []uint64{flagPredicatePushDown},
+			assertRuleName: "predicate_push_down",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "the predicates[gt(test.t.b, 1)] only refer to the group by columns of Aggregation_2, so filtering the input rows removes the same groups",
+					assertAction: "the predicates[gt(test.t.b, 1)] are pushed down below Aggregation_2",
+				},
+				{
+					assertReason: "the predicates[gt(test.t.b, 1)] only refer to the columns of DataSource_1 and can be evaluated by the storage layer",
+					assertAction: "the predicates[gt(test.t.b, 1)] are pushed down into DataSource_1",
+				},
+			},
+		},
+		{
+			sql:            "select t1.b from t t1 left join t t2 on t1.a = t2.a",
+			flags:          []uint64{flagBuildKeyInfo, flagEliminateOuterJoin},
+			assertRuleName: "outer_join_eliminate",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "the parent of Join_3 only uses the columns[test.t.b] of its outer side, and the join keys[test.t.a] of its inner side are unique, so each outer row matches at most one inner row",
+					assertAction: "Join_3 is eliminated, and its left child DataSource_1 is kept",
+				},
+			},
+		},
+		{
+			sql:            "select t2.b from t t1 right join t t2 on t1.f = t2.b",
+			flags:          []uint64{flagBuildKeyInfo, flagEliminateOuterJoin},
+			assertRuleName: "outer_join_eliminate",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "the parent of Join_3 only uses the columns[test.t.b] of its outer side, and the join keys[test.t.f] of its inner side are unique, so each outer row matches at most one inner row",
+					assertAction: "Join_3 is eliminated, and its right child DataSource_2 is kept",
+				},
+			},
+		},
+		{
+			sql:            "select count(distinct t1.b) from t t1 left join t t2 on t1.b = t2.b",
+			flags:          []uint64{flagBuildKeyInfo, flagPrunColumns, flagEliminateOuterJoin},
+			assertRuleName: "outer_join_eliminate",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "the aggregate functions above Join_3 only use the columns[test.t.b] of its outer side and are duplicate agnostic, so the duplicated outer rows don't change the result",
+					assertAction: "Join_3 is eliminated, and its left child DataSource_1 is kept",
+				},
+			},
+		},
+		{
+			sql:            "select * from t order by a limit 10",
+			flags:          []uint64{flagPushDownTopN},
+			assertRuleName: "topn_push_down",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "Sort_3 is directly below a limit, so the limit and the sort can be computed by a single topN",
+					assertAction: "the limit and Sort_3 are combined into TopN_5 with the ByItems[test.t.a]",
+				},
+				{
+					assertReason: "Projection_2 doesn't change the number of rows, and the ByItems of TopN_5 can be substituted by its expressions",
+					assertAction: "TopN_5 is pushed down below Projection_2",
+				},
+			},
+		},
+		{
+			sql:            "select t1.b from t t1 left join t t2 on t1.b = t2.b order by t1.a limit 10",
+			flags:          []uint64{flagPushDownTopN},
+			assertRuleName: "topn_push_down",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "Sort_5 is directly below a limit, so the limit and the sort can be computed by a single topN",
+					assertAction: "the limit and Sort_5 are combined into TopN_8 with the ByItems[test.t.a]",
+				},
+				{
+					assertReason: "Projection_4 doesn't change the number of rows, and the ByItems of TopN_8 can be substituted by its expressions",
+					assertAction: "TopN_8 is pushed down below Projection_4",
+				},
+				{
+					assertReason: "the ByItems of TopN_8 only refer to the columns of the outer side of Join_3, and every outer row is kept by the join",
+					assertAction: "TopN_9 is added below Join_3 as the parent of its left child DataSource_1",
+				},
+			},
+		},
+		{
+			sql:            "select a + 1 from t limit 10",
+			flags:          []uint64{flagPushDownTopN},
+			assertRuleName: "topn_push_down",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "Projection_2 doesn't change the number of rows, and the limit TopN_4 has no ByItems to be substituted by its expressions",
+					assertAction: "TopN_4 is pushed down below Projection_2",
+				},
+			},
+		},
+		{
+			sql:            "select t1.b from t t1 left join t t2 on t1.b = t2.b limit 10",
+			flags:          []uint64{flagPushDownTopN},
+			assertRuleName: "topn_push_down",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "Projection_4 doesn't change the number of rows, and the limit TopN_6 has no ByItems to be substituted by its expressions",
+					assertAction: "TopN_6 is pushed down below Projection_4",
+				},
+				{
+					assertReason: "the limit TopN_6 has no ByItems, and every outer row of Join_3 is kept by the join",
+					assertAction: "TopN_7 is added below Join_3 as the parent of its left child DataSource_1",
+				},
+			},
+		},
+		{
+			sql:            "select * from ((select a from t) union all (select b from t)) tmp limit 5",
+			flags:          []uint64{flagPushDownTopN},
+			assertRuleName: "limit_push_down",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "Union_5 is a UNION ALL which keeps the duplicated rows, so each child only needs to return its first 5 rows, which doesn't hold for a UNION DISTINCT since the duplicated rows are removed",
+					assertAction: "the limit 5 is duplicated as TopN_11 below Union_5 as the parent of its child Projection_6",
+				},
+				{
+					assertReason: "Union_5 is a UNION ALL which keeps the duplicated rows, so each child only needs to return its first 5 rows, which doesn't hold for a UNION DISTINCT since the duplicated rows are removed",
+					assertAction: "the limit 5 is duplicated as TopN_13 below Union_5 as the parent of its child Projection_7",
+				},
+			},
+		},
+		{
+			sql:            "select a + 1 from (select a from t union all select b from t) tmp order by a limit 5, 3",
+			flags:          []uint64{flagPushDownTopN},
+			assertRuleName: "topn_push_down",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "Sort_9 is directly below a limit, so the limit and the sort can be computed by a single topN",
+					assertAction: "the limit and Sort_9 are combined into TopN_12 with the ByItems[Column#25]",
+				},
+				{
+					assertReason: "Projection_8 doesn't change the number of rows, and the ByItems of TopN_12 can be substituted by its expressions",
+					assertAction: "TopN_12 is pushed down below Projection_8",
+				},
+				{
+					assertReason: "Union_5 is a UNION ALL which keeps the duplicated rows, so its first 8 rows must come from the first 8 rows of each child",
+					assertAction: "TopN_13 is added below Union_5 as the parent of its child Projection_6",
+				},
+				{
+					assertReason: "Projection_6 doesn't change the number of rows, and the ByItems of TopN_13 can be substituted by its expressions",
+					assertAction: "TopN_13 is pushed down below Projection_6",
+				},
+				{
+					assertReason: "Projection_2 doesn't change the number of rows, and the ByItems of TopN_13 can be substituted by its expressions",
+					assertAction: "TopN_13 is pushed down below Projection_2",
+				},
+				{
+					assertReason: "Union_5 is a UNION ALL which keeps the duplicated rows, so its first 8 rows must come from the first 8 rows of each child",
+					assertAction: "TopN_14 is added below Union_5 as the parent of its child Projection_7",
+				},
+				{
+					assertReason: "Projection_7 doesn't change the number of rows, and the ByItems of TopN_14 can be substituted by its expressions",
+					assertAction: "TopN_14 is pushed down below Projection_7",
+				},
+				{
+					assertReason: "Projection_4 doesn't change the number of rows, and the ByItems of TopN_14 can be substituted by its expressions",
+					assertAction: "TopN_14 is pushed down below Projection_4",
+				},
+			},
+		},
+		{
+			sql:            "select a from t where b > 1",
+			flags:          []uint64{flagPrunColumns},
+			assertRuleName: "column_prune",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "the parent of DataSource_1 only uses its columns[test.t.a,test.t.b]",
+					assertAction: "the columns[test.t.c,test.t.d,test.t.e,test.t.c_str,test.t.d_str,test.t.e_str,test.t.f,test.t.g,test.t.h,test.t.i_date] of DataSource_1 are pruned, and the columns[test.t.a,test.t.b] are kept",
+				},
+			},
+		},
+		{
+			sql:            "select b from (select b, max(c) from t group by b) x",
+			flags:          []uint64{flagPrunColumns},
+			assertRuleName: "column_prune",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "the parent of Projection_3 only uses its columns[test.t.b]",
+					assertAction: "the columns[Column#13] of Projection_3 are pruned, and the columns[test.t.b] are kept",
+				},
+				{
+					assertReason: "the parent of Aggregation_2 only uses its columns[firstrow(test.t.b)]",
+					assertAction: "the columns[max(test.t.c),firstrow(test.t.a),firstrow(test.t.c),firstrow(test.t.d),firstrow(test.t.e),firstrow(test.t.c_str),firstrow(test.t.d_str),firstrow(test.t.e_str),firstrow(test.t.f),firstrow(test.t.g),firstrow(test.t.h),firstrow(test.t.i_date)] of Aggregation_2 are pruned, and the columns[firstrow(test.t.b),count(1)] are kept",
+				},
+				{
+					assertReason: "the parent of DataSource_1 only uses its columns[test.t.b]",
+					assertAction: "the columns[test.t.a,test.t.c,test.t.d,test.t.e,test.t.c_str,test.t.d_str,test.t.e_str,test.t.f,test.t.g,test.t.h,test.t.i_date] of DataSource_1 are pruned, and the columns[test.t.b] are kept",
+				},
+			},
+		},
+		{
+			sql:            "select * from t where a in (select b from t t2 where t2.c = t.d)",
+			flags:          []uint64{flagBuildKeyInfo, flagDecorrelate},
+			assertRuleName: "decorrelate",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "Projection_5 in the inner side of Apply_6 doesn't change the number of rows",
+					assertAction: "Projection_5 is removed, and its expressions[test.t.b] are substituted into the join conditions of Apply_6",
+				},
+				{
+					assertReason: "the conditions[eq(test.t.c, test.t.d)] of Selection_4 in the inner side of Apply_6 refer to the outer columns[test.t.d], which are available to its join conditions",
+					assertAction: "Selection_4 is pulled up, and its conditions are added into the join conditions of Apply_6",
+				},
+				{
+					assertReason: "the inner side of Apply_6 doesn't refer to any column of its outer side",
+					assertAction: "Apply_6 is decorrelated into a semi join",
+				},
+			},
+		},
+		{
+			sql:            "select (select sum(b) from t t2 where t2.c = t1.c) from t t1",
+			flags:          []uint64{flagBuildKeyInfo, flagDecorrelate},
+			assertRuleName: "decorrelate",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "the scalar subquery refers to the outer columns[test.t.c]",
+					assertAction: "MaxOneRow_8 is built as the inner side of Apply_9, which evaluates it for each outer row unless it's decorrelated",
+				},
+				{
+					assertReason: "Projection_7 returns at most one row",
+					assertAction: "MaxOneRow_8 in the inner side of Apply_9 is removed",
+				},
+				{
+					assertReason: "Projection_7 in the inner side of Apply_9 doesn't change the number of rows",
+					assertAction: "Projection_7 is pulled up above Apply_9",
+				},
+				{
+					assertReason: "Apply_9 has no join condition and its outer side has a unique key, and the aggregate functions of Aggregation_6 return null for empty input",
+					assertAction: "Aggregation_6 is pulled up above Apply_9 and grouped by the unique key[test.t.f] of DataSource_1, and Apply_9 becomes a left outer join",
+				},
+				{
+					assertReason: "the conditions[eq(test.t.c, test.t.c)] of Selection_5 in the inner side of Apply_9 refer to the outer columns[test.t.c], which are available to its join conditions",
+					assertAction: "Selection_5 is pulled up, and its conditions are added into the join conditions of Apply_9",
+				},
+				{
+					assertReason: "the inner side of Apply_9 doesn't refer to any column of its outer side",
+					assertAction: "Apply_9 is decorrelated into a left outer join",
+				},
+			},
+		},
+		{
+			sql:            "select * from t t1, t t2, t t3 where t1.a = t2.a and t2.b = t3.b and t3.c = 1",
+			flags:          []uint64{flagPredicatePushDown, flagJoinReOrder},
+			assertRuleName: "join_reorder",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "the join group has 3 nodes, more than tidb_opt_join_reorder_threshold(0), so the greedy algorithm is used, and the estimated costs of the candidate join orders are [(t3*t2):10022.5, ((t3*t2)*t1):20038.125]",
+					assertAction: "the join order of Join_5 is changed from ((t1*t2)*t3) to ((t3*t2)*t1)",
+				},
+			},
+		},
+		{
+			sql:            "select * from t t1 join t t2 on t1.a = t2.a left join t t3 on t2.b = t3.b join t t4 on t2.c = t4.c",
+			flags:          []uint64{flagPredicatePushDown, flagJoinReOrder},
+			assertRuleName: "join_reorder",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "the join group has 2 nodes, more than tidb_opt_join_reorder_threshold(0), so the greedy algorithm is used, and the estimated costs of the candidate join orders are [(t1*t2):32500]",
+					assertAction: "the join order of Join_3 is kept as (t1*t2)",
+				},
+				{
+					assertReason: "the join group has 2 nodes, more than tidb_opt_join_reorder_threshold(0), so the greedy algorithm is used, and the estimated costs of the candidate join orders are [(t4*Join_6):87656.25]",
+					assertAction: "the join order of Join_8 is changed from (Join_6*t4) to (t4*Join_6)",
+				},
+			},
+		},
 	}
 
 	for i, tc := range tt {
@@ -197,19 +759,78 @@ func (s *testPlanSuite) TestSingleRuleTraceStep(c *C) {
 		c.Assert(err, IsNil)
 		_, ok := p.(*LogicalProjection)
 		c.Assert(ok, IsTrue)
-		otrace := sctx.GetSessionVars().StmtCtx.LogicalOptimizeTrace
-		c.Assert(otrace, NotNil)
-		assert := false
-		for _, step := range otrace.Steps {
-			if step.RuleName == tc.assertRuleName {
-				assert = true
-				for i, ruleStep := range step.Steps {
-					c.Assert(ruleStep.Action, Equals, tc.assertRuleSteps[i].assertAction)
-					c.Assert(ruleStep.Reason, Equals, tc.assertRuleSteps[i].assertReason)
-				}
-			}
-		}
-		c.Assert(assert, IsTrue)
+		checkRuleTraceSteps(c, sctx.GetSessionVars().StmtCtx.LogicalOptimizeTrace, tc.assertRuleName, tc.assertRuleSteps, comment)
+	}
+}
+
+func (s *testPlanSuite) TestPartitionProcessorTraceStep(c *C) {
+	defer testleak.AfterTest(c)()
+	definitions := []model.PartitionDefinition{
+		{ID: 41, Name: model.NewCIStr("p1"), LessThan: []string{"16"}},
+		{ID: 42, Name: model.NewCIStr("p2"), LessThan: []string{"32"}},
+		{ID: 43, Name: model.NewCIStr("p3"), LessThan: []string{"64"}},
+		{ID: 44, Name: model.NewCIStr("p4"), LessThan: []string{"maxvalue"}},
+	}
+	is := MockPartitionInfoSchema(definitions)
+	tt := []struct {
+		sql             string
+		assertRuleSteps []assertTraceStep
+	}{
+		{
+			sql: "select * from t where ptn < 20",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "the partitions of DataSource_1 are pruned by the conditions[lt(test.t.ptn, 20)]",
+					assertAction: "the partitions[p3,p4] of DataSource_1 are pruned, and the partitions[p1,p2] are kept",
+				},
+			},
+		},
+		{
+			sql: "select * from t",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "DataSource_1 has no conditions to prune the partitions",
+					assertAction: "all the partitions[p1,p2,p3,p4] of DataSource_1 are kept",
+				},
+			},
+		},
+		{
+			sql: "select * from t partition (p1, p3) where ptn > 20",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "the partitions of DataSource_1 are pruned by the conditions[gt(test.t.ptn, 20)], and only the partitions[p1,p3] are specified by the PARTITION clause",
+					assertAction: "the partitions[p1,p2,p4] of DataSource_1 are pruned, and the partitions[p3] are kept",
+				},
+			},
+		},
+		{
+			sql: "select * from t partition (p1) where ptn > 20",
+			assertRuleSteps: []assertTraceStep{
+				{
+					assertReason: "the partitions of DataSource_1 are pruned by the conditions[gt(test.t.ptn, 20)], and only the partitions[p1] are specified by the PARTITION clause",
+					assertAction: "all the partitions of DataSource_1 are pruned, and DataSource_1 is replaced by TableDual_5",
+				},
+			},
+		},
+	}
+
+	for i, tc := range tt {
+		sql := tc.sql
+		comment := Commentf("case:%v sql:%s", i, sql)
+		stmt, err := s.ParseOneStmt(sql, "", "")
+		c.Assert(err, IsNil, comment)
+		err = Preprocess(s.ctx, stmt, WithPreprocessorReturn(&PreprocessorReturn{InfoSchema: is}))
+		c.Assert(err, IsNil, comment)
+		sctx := MockContext()
+		sctx.GetSessionVars().StmtCtx.EnableOptimizeTrace = true
+		builder, _ := NewPlanBuilder().Init(sctx, is, &hint.BlockHintProcessor{})
+		domain.GetDomain(sctx).MockInfoCacheAndLoadInfoSchema(is)
+		ctx := context.TODO()
+		p, err := builder.Build(ctx, stmt)
+		c.Assert(err, IsNil, comment)
+		p, err = logicalOptimize(ctx, flagPredicatePushDown|flagPartitionProcessor, p.(LogicalPlan))
+		c.Assert(err, IsNil, comment)
+		checkRuleTraceSteps(c, sctx.GetSessionVars().StmtCtx.LogicalOptimizeTrace, "partition_prune", tc.assertRuleSteps, comment)
 	}
 }
 
@@ -217,3 +838,20 @@ type assertTraceStep struct {
 	assertReason string
 	assertAction string
 }
+
+// checkRuleTraceSteps checks the trace steps recorded under the rule named ruleName.
+func checkRuleTraceSteps(c *C, otrace *tracing.LogicalOptimizeTracer, ruleName string, assertSteps []assertTraceStep, comment CommentInterface) {
+	c.Assert(otrace, NotNil, comment)
+	assert := false
+	for _, step := range otrace.Steps {
+		if step.RuleName == ruleName {
+			assert = true
+			c.Assert(step.Steps, HasLen, len(assertSteps), comment)
+			for i, ruleStep := range step.Steps {
+				c.Assert(ruleStep.Action, Equals, assertSteps[i].assertAction, comment)
+				c.Assert(ruleStep.Reason, Equals, assertSteps[i].assertReason, comment)
+			}
+		}
+	}
+	c.Assert(assert, IsTrue, comment)
+}
@@ -773,7 +773,11 @@ func (ds *DataSource) deriveCommonHandleTablePathStats(path *util.AccessPath, co
 	// If the `CountAfterAccess` is less than `stats.RowCount`, there must be some inconsistent stats info.
 	// We prefer the `stats.RowCount` because it could use more stats info to calculate the selectivity.
 	if path.CountAfterAccess < ds.stats.RowCount && !isIm {
-		path.CountAfterAccess = math.Min(ds.stats.RowCount/SelectionFactor, float64(ds.statisticTable.Count))
+		estimated := ds.stats.RowCount / SelectionFactor
+		path.CountAfterAccess = math.Min(estimated, float64(ds.statisticTable.Count))
+		if estimated > path.CountAfterAccess {
+			appendCountAfterAccessClampedTraceStep(ds, path, estimated)
+		}
 	}
 	return nil
 }
@@ -852,7 +856,11 @@ func (ds *DataSource) deriveTablePathStats(path *util.AccessPath, conds []expres
 	// If the `CountAfterAccess` is less than `stats.RowCount`, there must be some inconsistent stats info.
 	// We prefer the `stats.RowCount` because it could use more stats info to calculate the selectivity.
 	if path.CountAfterAccess < ds.stats.RowCount && !isIm {
-		path.CountAfterAccess = math.Min(ds.stats.RowCount/SelectionFactor, float64(ds.statisticTable.Count))
+		estimated := ds.stats.RowCount / SelectionFactor
+		path.CountAfterAccess = math.Min(estimated, float64(ds.statisticTable.Count))
+		if estimated > path.CountAfterAccess {
+			appendCountAfterAccessClampedTraceStep(ds, path, estimated)
+		}
 	}
 	return err
 }
@@ -939,7 +947,11 @@ func (ds *DataSource) deriveIndexPathStats(path *util.AccessPath, conds []expres
 	// If the `CountAfterAccess` is less than `stats.RowCount`, there must be some inconsistent stats info.
 	// We prefer the `stats.RowCount` because it could use more stats info to calculate the selectivity.
 	if path.CountAfterAccess < ds.stats.RowCount && !isIm {
-		path.CountAfterAccess = math.Min(ds.stats.RowCount/SelectionFactor, float64(ds.statisticTable.Count))
+		estimated := ds.stats.RowCount / SelectionFactor
+		path.CountAfterAccess = math.Min(estimated, float64(ds.statisticTable.Count))
+		if estimated > path.CountAfterAccess {
+			appendCountAfterAccessClampedTraceStep(ds, path, estimated)
+		}
 	}
 	if path.IndexFilters != nil {
 		selectivity, _, err := ds.tableStats.HistColl.Selectivity(ds.ctx, path.IndexFilters, nil)
@@ -1046,6 +1058,10 @@ type LogicalLimit struct {
 	Offset     uint64
 	Count      uint64
 	limitHints limitHintInfo
+
+	// calcFoundRows indicates that the statement carries SQL_CALC_FOUND_ROWS, so the limit
+	// can't be pushed down or converted, and it has to count all the rows of its child.
+	calcFoundRows bool
 }
 
 // extraPIDInfo is used by SelectLock on partitioned table, the TableReader need
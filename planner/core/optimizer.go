@@ -108,12 +108,20 @@ func (op *logicalOptimizeOp) appendBeforeRuleOptimize(index int, name string, be
 }
 
 func (op *logicalOptimizeOp) appendStepToCurrent(id int, tp, reason, action string) {
-	if op.tracer == nil {
+	if op == nil || op.tracer == nil {
 		return
 	}
 	op.tracer.AppendRuleTracerStepToCurrent(id, tp, reason, action)
 }
 
+// appendStepToSubRule records a step of the sub rule named name, which is applied by the current rule.
+func (op *logicalOptimizeOp) appendStepToSubRule(name string, id int, tp, reason, action string) {
+	if op == nil || op.tracer == nil {
+		return
+	}
+	op.tracer.AppendRuleTracerStepToSubRule(name, id, tp, reason, action)
+}
+
 func (op *logicalOptimizeOp) recordFinalLogicalPlan(final LogicalPlan) {
 	if op.tracer == nil {
 		return
@@ -435,6 +443,14 @@ func isLogicalRuleDisabled(r logicalOptRule) bool {
 }
 
 func physicalOptimize(logic LogicalPlan, planCounter *PlanCounterTp) (PhysicalPlan, float64, error) {
+	stmtCtx := logic.SCtx().GetSessionVars().StmtCtx
+	// The tracer is created before deriving stats, since the adjustments of the estimation are traced too.
+	if stmtCtx.EnableOptimizeTrace && stmtCtx.PhysicalOptimizeTrace == nil {
+		stmtCtx.PhysicalOptimizeTrace = &tracing.PhysicalOptimizeTracer{
+			Steps: make([]tracing.PhysicalOptimizeTraceStep, 0),
+		}
+	}
+
 	if _, err := logic.recursiveDeriveStats(nil); err != nil {
 		return nil, 0, err
 	}
@@ -446,19 +462,22 @@ func physicalOptimize(logic LogicalPlan, planCounter *PlanCounterTp) (PhysicalPl
 		ExpectedCnt: math.MaxFloat64,
 	}
 
-	logic.SCtx().GetSessionVars().StmtCtx.TaskMapBakTS = 0
+	stmtCtx.TaskMapBakTS = 0
 	t, _, err := logic.findBestTask(prop, planCounter)
 	if err != nil {
 		return nil, 0, err
 	}
 	if *planCounter > 0 {
-		logic.SCtx().GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("The parameter of nth_plan() is out of range."))
+		stmtCtx.AppendWarning(errors.Errorf("The parameter of nth_plan() is out of range."))
 	}
 	if t.invalid() {
 		return nil, 0, ErrInternal.GenWithStackByArgs("Can't find a proper physical plan for this query")
 	}
 
 	err = t.plan().ResolveIndices()
+	if err == nil {
+		tracePhysicalPlan(t.plan())
+	}
 	return t.plan(), t.cost(), err
 }
 
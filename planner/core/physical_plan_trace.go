@@ -0,0 +1,582 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/planner/property"
+	"github.com/pingcap/tidb/planner/util"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/memory"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+// appendPhysicalTraceStep records a physical optimize step for p if the optimize trace is enabled.
+func appendPhysicalTraceStep(p Plan, reason, action string) {
+	tracer := p.SCtx().GetSessionVars().StmtCtx.PhysicalOptimizeTrace
+	if tracer == nil {
+		return
+	}
+	tracer.AppendStep(p.ID(), p.TP(), reason, action)
+}
+
+// tracePhysicalPlan walks the final physical plan and records the decisions which are
+// only visible once the whole plan is determined.
+func tracePhysicalPlan(p PhysicalPlan) {
+	if p.SCtx().GetSessionVars().StmtCtx.PhysicalOptimizeTrace == nil {
+		return
+	}
+	switch x := p.(type) {
+	case *PhysicalIndexReader:
+		if is, ok := x.IndexPlans[0].(*PhysicalIndexScan); ok {
+			appendIndexSingleReadTraceStep(x, is)
+			appendIndexRangeTraceSteps(x, is, pushedDownFilters(x.IndexPlans), nil)
+			appendIndexScanDirectionTraceStep(x, is)
+		}
+	case *PhysicalIndexLookUpReader:
+		is, ok1 := x.IndexPlans[0].(*PhysicalIndexScan)
+		ts, ok2 := x.TablePlans[0].(*PhysicalTableScan)
+		if ok1 && ok2 {
+			appendIndexDoubleReadTraceStep(x, is, ts)
+			appendIndexRangeTraceSteps(x, is, pushedDownFilters(x.IndexPlans), pushedDownFilters(x.TablePlans))
+			appendIndexScanDirectionTraceStep(x, is)
+		}
+	case *PhysicalStreamAgg:
+		if len(x.GroupByItems) > 0 {
+			if is := findOrderedIndexScan(x.children[0]); is != nil {
+				appendStreamAggByIndexTraceStep(x, is)
+			}
+		}
+		appendDistinctAggPushDownTraceStep(&x.basePhysicalAgg)
+		appendTwoPhaseAggTraceStep(&x.basePhysicalAgg)
+	case *PhysicalTopN:
+		if partial, storeType := findPushedDownTopN(x.children[0]); partial != nil {
+			appendTwoPhaseTopNTraceStep(x, partial, storeType)
+		}
+	case *PhysicalHashAgg:
+		appendHashAggSpillTraceStep(x)
+		appendDistinctAggPushDownTraceStep(&x.basePhysicalAgg)
+		appendTwoPhaseAggTraceStep(&x.basePhysicalAgg)
+	case *PointGetPlan:
+		appendPointGetTraceStep(x)
+	case *BatchPointGetPlan:
+		appendBatchPointGetTraceStep(x)
+	case *PhysicalTableReader:
+		// The plan running in the MPP tasks isn't a child of the reader, so it's walked separately.
+		if _, ok := x.tablePlan.(*PhysicalExchangeSender); ok {
+			traceMPPPlan(x.tablePlan)
+		}
+	}
+	for _, child := range p.Children() {
+		tracePhysicalPlan(child)
+	}
+}
+
+// traceMPPPlan walks the plan running in the MPP tasks and records the decisions about the data shuffling.
+func traceMPPPlan(p PhysicalPlan) {
+	if agg, ok := p.(*PhysicalHashAgg); ok {
+		appendMPPAggExchangeTraceStep(agg)
+	}
+	for _, child := range p.Children() {
+		traceMPPPlan(child)
+	}
+}
+
+// appendMPPAggExchangeTraceStep records the partition keys of the exchange which shuffles the rows to the MPP
+// aggregation agg, so that the rows of the same group are aggregated in the same MPP task.
+func appendMPPAggExchangeTraceStep(agg *PhysicalHashAgg) {
+	receiver, ok := agg.children[0].(*PhysicalExchangeReceiver)
+	if !ok {
+		return
+	}
+	sender, ok := receiver.children[0].(*PhysicalExchangeSender)
+	if !ok || sender.ExchangeType != tipb.ExchangeType_Hash {
+		return
+	}
+	child := sender.children[0]
+	reason := fmt.Sprintf("the rows of the same group must be in the same MPP task for %v_%v to aggregate them", agg.TP(), agg.ID())
+	if _, ok := child.(*PhysicalHashAgg); ok {
+		reason = fmt.Sprintf("the partial results of the same group must be in the same MPP task for %v_%v to merge them", agg.TP(), agg.ID())
+	}
+	action := fmt.Sprintf("%v_%v shuffles the rows of %v_%v to %v_%v by the hash partition keys %s",
+		sender.TP(), sender.ID(), child.TP(), child.ID(), agg.TP(), agg.ID(), property.ExplainColumnList(sender.HashCols))
+	appendPhysicalTraceStep(agg, reason, action)
+}
+
+// appendPointGetTraceStep records that the row is read by the handle or the unique index value directly, which is
+// the fastest access method.
+func appendPointGetTraceStep(p *PointGetPlan) {
+	var reason, action string
+	if p.IndexInfo == nil {
+		reason = fmt.Sprintf("the predicates%v restrict the handle to a single value", p.AccessConditions)
+		action = fmt.Sprintf("%v_%v reads the row by handle %s directly", p.TP(), p.ID(), p.Handle)
+	} else {
+		reason = fmt.Sprintf("the predicates%v restrict all the columns of unique index[%s] to a single value", p.AccessConditions, p.IndexInfo.Name.O)
+		action = fmt.Sprintf("%v_%v reads the row by the value[%s] of index[%s] directly", p.TP(), p.ID(), types.DatumsToStrNoErr(p.IndexValues), p.IndexInfo.Name.O)
+	}
+	appendPhysicalTraceStep(p, reason, action)
+}
+
+// appendBatchPointGetTraceStep records that the rows are read by the handles or the unique index values directly.
+func appendBatchPointGetTraceStep(p *BatchPointGetPlan) {
+	values := bytes.NewBufferString("[")
+	var reason, action string
+	if p.IndexInfo == nil {
+		for i, handle := range p.Handles {
+			if i > 0 {
+				values.WriteString(",")
+			}
+			values.WriteString(handle.String())
+		}
+		values.WriteString("]")
+		reason = fmt.Sprintf("the predicates%v restrict the handle to %d values", p.AccessConditions, len(p.Handles))
+		action = fmt.Sprintf("%v_%v reads the rows by the handles%s directly", p.TP(), p.ID(), values.String())
+	} else {
+		for i, idxVals := range p.IndexValues {
+			if i > 0 {
+				values.WriteString(",")
+			}
+			values.WriteString(types.DatumsToStrNoErr(idxVals))
+		}
+		values.WriteString("]")
+		reason = fmt.Sprintf("the predicates%v restrict all the columns of unique index[%s] to %d values", p.AccessConditions, p.IndexInfo.Name.O, len(p.IndexValues))
+		action = fmt.Sprintf("%v_%v reads the rows by the values%s of index[%s] directly", p.TP(), p.ID(), values.String(), p.IndexInfo.Name.O)
+	}
+	appendPhysicalTraceStep(p, reason, action)
+}
+
+func appendIndexSingleReadTraceStep(reader *PhysicalIndexReader, is *PhysicalIndexScan) {
+	reason := fmt.Sprintf("index[%s] covers all the needed columns", is.Index.Name.O)
+	action := fmt.Sprintf("%v_%v reads index[%s] only, no table lookup is needed", reader.TP(), reader.ID(), is.Index.Name.O)
+	appendPhysicalTraceStep(reader, reason, action)
+}
+
+func appendIndexDoubleReadTraceStep(reader *PhysicalIndexLookUpReader, is *PhysicalIndexScan, ts *PhysicalTableScan) {
+	reason := bytes.NewBufferString(fmt.Sprintf("index[%s] doesn't cover the columns[", is.Index.Name.O))
+	for i, col := range nonCoveredColumns(is, ts) {
+		if i > 0 {
+			reason.WriteString(",")
+		}
+		reason.WriteString(col)
+	}
+	reason.WriteString("]")
+	action := fmt.Sprintf("%v_%v reads index[%s] and then looks up the table rows by handle", reader.TP(), reader.ID(), is.Index.Name.O)
+	appendPhysicalTraceStep(reader, reason.String(), action)
+}
+
+// appendIndexRangeTraceSteps records for each predicate pushed down to the index reader whether it's converted into
+// the ranges of the index, or kept as a filter on the index rows or the table rows after scanning.
+func appendIndexRangeTraceSteps(reader PhysicalPlan, is *PhysicalIndexScan, indexFilters, tableFilters []expression.Expression) {
+	idx := is.Index.Name.O
+	for _, cond := range is.AccessCondition {
+		reason := fmt.Sprintf("the predicate[%s] restricts the prefix columns of index[%s]", cond, idx)
+		action := fmt.Sprintf("the predicate[%s] is converted into the ranges of index[%s] scanned by %v_%v", cond, idx, reader.TP(), reader.ID())
+		appendPhysicalTraceStep(reader, reason, action)
+	}
+	for _, cond := range indexFilters {
+		reason := fmt.Sprintf("the predicate[%s] can't be converted into the ranges of index[%s], but it only needs the columns of the index", cond, idx)
+		action := fmt.Sprintf("the predicate[%s] is kept as a filter on the index rows read by %v_%v", cond, reader.TP(), reader.ID())
+		appendPhysicalTraceStep(reader, reason, action)
+	}
+	for _, cond := range tableFilters {
+		reason := fmt.Sprintf("the predicate[%s] needs the columns not covered by index[%s]", cond, idx)
+		action := fmt.Sprintf("the predicate[%s] is kept as a filter on the table rows looked up by %v_%v", cond, reader.TP(), reader.ID())
+		appendPhysicalTraceStep(reader, reason, action)
+	}
+}
+
+// pushedDownFilters returns the conditions of the selections in the flattened cop plans.
+func pushedDownFilters(plans []PhysicalPlan) []expression.Expression {
+	var conds []expression.Expression
+	for _, p := range plans {
+		if sel, ok := p.(*PhysicalSelection); ok {
+			conds = append(conds, sel.Conditions...)
+		}
+	}
+	return conds
+}
+
+// appendIndexScanDirectionTraceStep records the direction in which the index is scanned when the index provides the
+// order required by the parent, so that no sort is needed.
+func appendIndexScanDirectionTraceStep(reader PhysicalPlan, is *PhysicalIndexScan) {
+	if !is.KeepOrder {
+		return
+	}
+	order, direction := "ascending", "forward"
+	if is.Desc {
+		order, direction = "descending", "backward"
+	}
+	reason := fmt.Sprintf("the required order can be provided by the %s order of index[%s]", order, is.Index.Name.O)
+	action := fmt.Sprintf("%v_%v scans index[%s] %s to keep the order, so no sort is needed", reader.TP(), reader.ID(), is.Index.Name.O, direction)
+	appendPhysicalTraceStep(reader, reason, action)
+}
+
+func appendStreamAggByIndexTraceStep(agg *PhysicalStreamAgg, is *PhysicalIndexScan) {
+	reason := bytes.NewBufferString(fmt.Sprintf("index[%s] provides the order of the group by items[", is.Index.Name.O))
+	for i, item := range agg.GroupByItems {
+		if i > 0 {
+			reason.WriteString(",")
+		}
+		reason.WriteString(item.String())
+	}
+	reason.WriteString("]")
+	action := fmt.Sprintf("%v_%v aggregates the ordered rows in stream without building a hash table", agg.TP(), agg.ID())
+	appendPhysicalTraceStep(agg, reason.String(), action)
+}
+
+func appendTwoPhaseTopNTraceStep(topN, partial *PhysicalTopN, storeType kv.StoreType) {
+	reason := bytes.NewBufferString("the order by items[")
+	for i, item := range topN.ByItems {
+		if i > 0 {
+			reason.WriteString(",")
+		}
+		reason.WriteString(item.String())
+	}
+	reason.WriteString(fmt.Sprintf("] can be evaluated in %s, so each region only needs to return its first %v rows", storeType.Name(), partial.Count))
+	action := fmt.Sprintf("%v_%v is pushed down as %v_%v with limit %v on each region, and %v_%v merges the results with offset %v, limit %v at root",
+		topN.TP(), topN.ID(), partial.TP(), partial.ID(), partial.Count, topN.TP(), topN.ID(), topN.Offset, topN.Count)
+	appendPhysicalTraceStep(topN, reason.String(), action)
+}
+
+// appendHashAggSpillTraceStep records whether the hash table of a root HashAgg is anticipated to be spilled to disk,
+// by comparing the estimated memory usage of the hash table with the memory quota of the query.
+func appendHashAggSpillTraceStep(agg *PhysicalHashAgg) {
+	sessVars := agg.SCtx().GetSessionVars()
+	groups := agg.statsInfo().RowCount
+	memUsage := int64(groups * getAvgRowSize(agg.statsInfo(), agg.schema))
+	quota := sessVars.MemQuotaQuery
+	if quota <= 0 || memUsage <= quota {
+		reason := fmt.Sprintf("the estimated memory usage %s of the hash table of %v_%v for %.2f groups is within the memory quota %s of the query",
+			memory.FormatBytes(memUsage), agg.TP(), agg.ID(), groups, memory.FormatBytes(quota))
+		action := fmt.Sprintf("%v_%v is not anticipated to spill to disk", agg.TP(), agg.ID())
+		appendPhysicalTraceStep(agg, reason, action)
+		return
+	}
+	reason := fmt.Sprintf("the estimated memory usage %s of the hash table of %v_%v for %.2f groups exceeds the memory quota %s of the query",
+		memory.FormatBytes(memUsage), agg.TP(), agg.ID(), groups, memory.FormatBytes(quota))
+	var action string
+	if cause := hashAggSpillDisabledCause(agg); cause != "" {
+		action = fmt.Sprintf("%v_%v can't spill to disk because %s, so the query may exceed the memory quota", agg.TP(), agg.ID(), cause)
+	} else {
+		action = fmt.Sprintf("%v_%v is anticipated to spill to disk", agg.TP(), agg.ID())
+	}
+	appendPhysicalTraceStep(agg, reason, action)
+}
+
+// hashAggSpillDisabledCause returns why the HashAgg can't spill to disk, or an empty string if it can.
+// Only the unparallel execution of HashAgg supports spilling.
+func hashAggSpillDisabledCause(agg *PhysicalHashAgg) string {
+	sessVars := agg.SCtx().GetSessionVars()
+	if !config.GetGlobalConfig().OOMUseTmpStorage {
+		return "oom-use-tmp-storage is disabled"
+	}
+	if !sessVars.TrackAggregateMemoryUsage {
+		return "tidb_track_aggregate_memory_usage is disabled"
+	}
+	for _, aggFunc := range agg.AggFuncs {
+		if aggFunc.HasDistinct || len(aggFunc.OrderByItems) > 0 {
+			return ""
+		}
+	}
+	if finalCon, partialCon := sessVars.HashAggFinalConcurrency(), sessVars.HashAggPartialConcurrency(); finalCon <= 0 || partialCon <= 0 || finalCon == 1 && partialCon == 1 {
+		return ""
+	}
+	return "it's executed in parallel"
+}
+
+// appendDistinctAggPushDownTraceStep records whether the distinct aggregate functions of the root aggregation agg are
+// pushed down to the cop task, along with the estimated NDV of the columns the cop task has to deduplicate.
+func appendDistinctAggPushDownTraceStep(agg *basePhysicalAgg) {
+	funcs := bytes.NewBufferString("[")
+	for _, aggFunc := range agg.AggFuncs {
+		if !aggFunc.HasDistinct {
+			continue
+		}
+		if funcs.Len() > 1 {
+			funcs.WriteString(",")
+		}
+		funcs.WriteString(aggFunc.String())
+	}
+	if funcs.Len() == 1 {
+		return
+	}
+	funcs.WriteString("]")
+	child := agg.children[0]
+	cols := expression.ExtractColumnsFromExpressions(nil, agg.GroupByItems, nil)
+	for _, aggFunc := range agg.AggFuncs {
+		if aggFunc.HasDistinct {
+			cols = expression.ExtractColumnsFromExpressions(cols, aggFunc.Args, nil)
+		}
+	}
+	partial := findPushedDownAgg(child)
+	if partial != nil {
+		// The distinct arguments are added to the group by items of the partial aggregation.
+		child = partial.children[0]
+		cols = expression.ExtractColumnsFromExpressions(nil, partial.GroupByItems, nil)
+	}
+	if child.Schema().ColumnsIndices(cols) == nil {
+		return
+	}
+	ndv, rowCount := getColsNDV(cols, child.Schema(), child.statsInfo()), child.statsInfo().RowCount
+	var reason, action string
+	switch {
+	case partial != nil:
+		reason = fmt.Sprintf("each region deduplicates its rows by the group by items and the distinct arguments before returning them, whose estimated NDV is %.2f for %.2f input rows",
+			ndv, rowCount)
+		action = fmt.Sprintf("the distinct aggregate functions%s of %v_%v are pushed down to the cop task as %v_%v",
+			funcs.String(), agg.TP(), agg.ID(), partial.TP(), partial.ID())
+	case !agg.ctx.GetSessionVars().AllowDistinctAggPushDown:
+		reason = fmt.Sprintf("tidb_opt_distinct_agg_push_down is disabled, though the estimated NDV of the group by items and the distinct arguments is %.2f for %.2f input rows",
+			ndv, rowCount)
+		action = fmt.Sprintf("the distinct aggregate functions%s of %v_%v are evaluated at root", funcs.String(), agg.TP(), agg.ID())
+	default:
+		reason = fmt.Sprintf("the cop task with the distinct aggregate functions isn't chosen, the estimated NDV of the group by items and the distinct arguments is %.2f for %.2f input rows",
+			ndv, rowCount)
+		action = fmt.Sprintf("the distinct aggregate functions%s of %v_%v are evaluated at root", funcs.String(), agg.TP(), agg.ID())
+	}
+	appendPhysicalTraceStep(agg, reason, action)
+}
+
+// appendTwoPhaseAggTraceStep records the estimated cost of the partial aggregation pushed down to the cop task and
+// the final aggregation kept at root to merge the partial results.
+func appendTwoPhaseAggTraceStep(agg *basePhysicalAgg) {
+	for _, aggFunc := range agg.AggFuncs {
+		// The aggregation pushed down for the distinct aggregate functions only deduplicates the rows instead of
+		// computing the partial results, which is recorded by appendDistinctAggPushDownTraceStep.
+		if aggFunc.HasDistinct {
+			return
+		}
+	}
+	reader := agg.children[0]
+	partial := findPushedDownAgg(reader)
+	if partial == nil {
+		return
+	}
+	partialInput, finalInput := partial.children[0].statsInfo().RowCount, reader.statsInfo().RowCount
+	reason := bytes.NewBufferString("the aggregate functions[")
+	for i, aggFunc := range agg.AggFuncs {
+		if i > 0 {
+			reason.WriteString(",")
+		}
+		reason.WriteString(aggFunc.String())
+	}
+	reason.WriteString(fmt.Sprintf("] can be computed as partial results on each region, the estimated cost is %.2f for %v_%v on %.2f rows in the cop task and %.2f for %v_%v on %.2f rows at root",
+		aggCost(partial.self, partialInput, false), partial.TP(), partial.ID(), partialInput,
+		aggCost(agg.self, finalInput, true), agg.TP(), agg.ID(), finalInput))
+	action := fmt.Sprintf("%v_%v is pushed down as %v_%v to compute the partial results, and %v_%v merges them at root",
+		agg.TP(), agg.ID(), partial.TP(), partial.ID(), agg.TP(), agg.ID())
+	appendPhysicalTraceStep(agg, reason.String(), action)
+}
+
+// aggCost returns the cost of the aggregation agg for the inputRows, which is either a HashAgg or a StreamAgg.
+func aggCost(agg PhysicalPlan, inputRows float64, isRoot bool) float64 {
+	switch x := agg.(type) {
+	case *PhysicalHashAgg:
+		return x.GetCost(inputRows, isRoot, false)
+	case *PhysicalStreamAgg:
+		return x.GetCost(inputRows, isRoot)
+	}
+	return 0
+}
+
+// findPushedDownAgg returns the partial aggregation on the top of the cop plans of the reader p.
+func findPushedDownAgg(p PhysicalPlan) *basePhysicalAgg {
+	var copPlan PhysicalPlan
+	switch x := p.(type) {
+	case *PhysicalTableReader:
+		copPlan = x.tablePlan
+	case *PhysicalIndexReader:
+		copPlan = x.indexPlan
+	}
+	switch x := copPlan.(type) {
+	case *PhysicalHashAgg:
+		return &x.basePhysicalAgg
+	case *PhysicalStreamAgg:
+		return &x.basePhysicalAgg
+	}
+	return nil
+}
+
+// findPushedDownTopN returns the partial TopN on the top of the cop plans of the reader p, and
+// the store it's pushed to.
+func findPushedDownTopN(p PhysicalPlan) (*PhysicalTopN, kv.StoreType) {
+	switch x := p.(type) {
+	case *PhysicalTableReader:
+		if topN, ok := x.tablePlan.(*PhysicalTopN); ok {
+			return topN, x.StoreType
+		}
+	case *PhysicalIndexReader:
+		if topN, ok := x.indexPlan.(*PhysicalTopN); ok {
+			return topN, kv.TiKV
+		}
+	case *PhysicalIndexLookUpReader:
+		if topN, ok := x.indexPlan.(*PhysicalTopN); ok {
+			return topN, kv.TiKV
+		}
+		if topN, ok := x.tablePlan.(*PhysicalTopN); ok {
+			return topN, kv.TiKV
+		}
+	}
+	return nil, kv.TiKV
+}
+
+// findOrderedIndexScan returns the index scan which keeps order and provides the rows of p
+// without any sort in between.
+func findOrderedIndexScan(p PhysicalPlan) *PhysicalIndexScan {
+	switch x := p.(type) {
+	case *PhysicalProjection, *PhysicalSelection:
+		return findOrderedIndexScan(x.Children()[0])
+	case *PhysicalIndexReader:
+		if is, ok := x.IndexPlans[0].(*PhysicalIndexScan); ok && is.KeepOrder {
+			return is
+		}
+	case *PhysicalIndexLookUpReader:
+		if is, ok := x.IndexPlans[0].(*PhysicalIndexScan); ok && is.KeepOrder {
+			return is
+		}
+	}
+	return nil
+}
+
+// nonCoveredColumns returns the columns read by the table side of a double read which can not be
+// fetched from the index. A prefix index column doesn't cover the column.
+func nonCoveredColumns(is *PhysicalIndexScan, ts *PhysicalTableScan) []string {
+	covered := make(map[int64]struct{}, len(is.Index.Columns)+1)
+	for _, idxCol := range is.Index.Columns {
+		if idxCol.Length == types.UnspecifiedLength {
+			covered[is.Table.Columns[idxCol.Offset].ID] = struct{}{}
+		}
+	}
+	if pkCol := is.Table.GetPkColInfo(); is.Table.PKIsHandle && pkCol != nil {
+		covered[pkCol.ID] = struct{}{}
+	}
+	if is.Table.IsCommonHandle {
+		for _, idxCol := range tables.FindPrimaryIndex(is.Table).Columns {
+			covered[is.Table.Columns[idxCol.Offset].ID] = struct{}{}
+		}
+	}
+	cols := make([]string, 0, len(ts.schema.Columns))
+	for _, col := range ts.schema.Columns {
+		if col.ID == model.ExtraHandleID {
+			continue
+		}
+		if _, ok := covered[col.ID]; !ok {
+			cols = append(cols, col.String())
+		}
+	}
+	return cols
+}
+
+// candidateCostTracer records the cost of every candidate access path of a DataSource, so that the
+// trace shows them side by side with the chosen one.
+type candidateCostTracer struct {
+	ds    *DataSource
+	names []string
+	tasks []task
+}
+
+// newCandidateCostTracer returns nil if the optimize trace is disabled. Only the property without
+// any requirement is traced, since it's the one which decides the plan of a simple query.
+func newCandidateCostTracer(ds *DataSource, prop *property.PhysicalProperty) *candidateCostTracer {
+	if ds.ctx.GetSessionVars().StmtCtx.PhysicalOptimizeTrace == nil {
+		return nil
+	}
+	if prop.TaskTp != property.RootTaskType || !prop.IsEmpty() {
+		return nil
+	}
+	return &candidateCostTracer{ds: ds}
+}
+
+func (c *candidateCostTracer) record(name string, t task) {
+	if c == nil || t.invalid() {
+		return
+	}
+	c.names = append(c.names, name)
+	c.tasks = append(c.tasks, t)
+}
+
+func (c *candidateCostTracer) appendTraceStep(best task) {
+	if c == nil || len(c.tasks) < 2 {
+		return
+	}
+	reason := bytes.NewBufferString("")
+	winner := ""
+	for i, t := range c.tasks {
+		if i > 0 {
+			reason.WriteString(", ")
+		}
+		reason.WriteString(fmt.Sprintf("%s cost %.2f", c.names[i], t.cost()))
+		if t == best {
+			winner = c.names[i]
+			reason.WriteString(" (chosen)")
+		}
+	}
+	if winner == "" {
+		return
+	}
+	action := fmt.Sprintf("%v_%v reads the table by %s, which has the lowest cost", c.ds.TP(), c.ds.ID(), winner)
+	appendPhysicalTraceStep(c.ds, reason.String(), action)
+}
+
+// recordPointGetMiss records why the handle or unique index path isn't converted to [batch] point get.
+func (c *candidateCostTracer) recordPointGetMiss(path *util.AccessPath, reason string) {
+	if c == nil {
+		return
+	}
+	action := fmt.Sprintf("%v_%v can't use %s", c.ds.TP(), c.ds.ID(), pointGetPathName(path))
+	// The DataSource may be optimized for several properties without any requirement, e.g. with and without
+	// the enforcer allowed, and the miss of the same path only needs to be recorded once.
+	for _, step := range c.ds.ctx.GetSessionVars().StmtCtx.PhysicalOptimizeTrace.Steps {
+		if step.ID == c.ds.ID() && step.Reason == reason && step.Action == action {
+			return
+		}
+	}
+	appendPhysicalTraceStep(c.ds, reason, action)
+}
+
+// appendCountAfterAccessClampedTraceStep records that the estimated row count of the access path exceeds the table
+// row count, so it is clamped to the table row count.
+func appendCountAfterAccessClampedTraceStep(ds *DataSource, path *util.AccessPath, estimated float64) {
+	reason := fmt.Sprintf("the estimated row count %.2f of %s exceeds the row count %v of the table",
+		estimated, candidatePathName(path), ds.statisticTable.Count)
+	action := fmt.Sprintf("the estimated row count of %s is clamped from %.2f to %.2f", candidatePathName(path), estimated, path.CountAfterAccess)
+	appendPhysicalTraceStep(ds, reason, action)
+}
+
+// candidatePathName returns the name of the access path used in the optimize trace.
+func candidatePathName(path *util.AccessPath) string {
+	if path.IsTablePath() {
+		if path.StoreType == kv.TiFlash {
+			return "table scan on tiflash"
+		}
+		return "table scan"
+	}
+	return fmt.Sprintf("index[%s]", path.Index.Name.O)
+}
+
+// pointGetPathName returns the name of the access path converted to [batch] point get.
+func pointGetPathName(path *util.AccessPath) string {
+	if path.IsIntHandlePath {
+		return "point get on handle"
+	}
+	return fmt.Sprintf("point get on index[%s]", path.Index.Name.O)
+}
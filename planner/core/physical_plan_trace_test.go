@@ -0,0 +1,396 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/planner/util"
+	"github.com/pingcap/tidb/util/hint"
+	"github.com/pingcap/tidb/util/testleak"
+)
+
+func (s *testPlanSuite) TestPhysicalOptimizeTraceStep(c *C) {
+	defer testleak.AfterTest(c)()
+	tt := []struct {
+		sql                 string
+		memQuota            int64
+		distinctAggPushDown bool
+		tiflash             bool
+		assertSteps         []assertTraceStep
+	}{
+		{
+			sql: "select /*+ use_index(t, c_d_e) */ c, d from t where c > 1",
+			assertSteps: []assertTraceStep{
+				{
+					assertReason: "the ranges don't cover all the columns of index[c_d_e]",
+					assertAction: "DataSource_1 can't use point get on index[c_d_e]",
+				},
+				{
+					assertReason: "index[c_d_e] covers all the needed columns",
+					assertAction: "IndexReader_6 reads index[c_d_e] only, no table lookup is needed",
+				},
+				{
+					assertReason: "the predicate[gt(test.t.c, 1)] restricts the prefix columns of index[c_d_e]",
+					assertAction: "the predicate[gt(test.t.c, 1)] is converted into the ranges of index[c_d_e] scanned by IndexReader_6",
+				},
+			},
+		},
+		{
+			sql: "select /*+ use_index(t, c_d_e) */ c, d, b from t where c > 1",
+			assertSteps: []assertTraceStep{
+				{
+					assertReason: "the ranges don't cover all the columns of index[c_d_e]",
+					assertAction: "DataSource_1 can't use point get on index[c_d_e]",
+				},
+				{
+					assertReason: "index[c_d_e] doesn't cover the columns[test.t.b]",
+					assertAction: "IndexLookUp_7 reads index[c_d_e] and then looks up the table rows by handle",
+				},
+				{
+					assertReason: "the predicate[gt(test.t.c, 1)] restricts the prefix columns of index[c_d_e]",
+					assertAction: "the predicate[gt(test.t.c, 1)] is converted into the ranges of index[c_d_e] scanned by IndexLookUp_7",
+				},
+			},
+		},
+		{
+			sql: "select /*+ use_index(t, c_d_e), stream_agg() */ c, count(*) from t group by c",
+			assertSteps: []assertTraceStep{
+				{
+					assertReason: "index[c_d_e] provides the order of the group by items[test.t.c]",
+					assertAction: "StreamAgg_14 aggregates the ordered rows in stream without building a hash table",
+				},
+				{
+					assertReason: "the aggregate functions[count(Column#16),firstrow(test.t.c)] can be computed as partial results on each region, the estimated cost is 30000.00 for StreamAgg_8 on 10000.00 rows in the cop task and 26400.00 for StreamAgg_14 on 8000.00 rows at root",
+					assertAction: "StreamAgg_14 is pushed down as StreamAgg_8 to compute the partial results, and StreamAgg_14 merges them at root",
+				},
+				{
+					assertReason: "index[c_d_e] covers all the needed columns",
+					assertAction: "IndexReader_15 reads index[c_d_e] only, no table lookup is needed",
+				},
+				{
+					assertReason: "the required order can be provided by the ascending order of index[c_d_e]",
+					assertAction: "IndexReader_15 scans index[c_d_e] forward to keep the order, so no sort is needed",
+				},
+			},
+		},
+		{
+			sql: "select b from t where g = 1",
+			assertSteps: []assertTraceStep{
+				{
+					assertReason: "table scan cost 121012.17, index[g] cost 256.93 (chosen)",
+					assertAction: "DataSource_1 reads the table by index[g], which has the lowest cost",
+				},
+				{
+					assertReason: "index[g] doesn't cover the columns[test.t.b]",
+					assertAction: "IndexLookUp_10 reads index[g] and then looks up the table rows by handle",
+				},
+				{
+					assertReason: "the predicate[eq(test.t.g, 1)] restricts the prefix columns of index[g]",
+					assertAction: "the predicate[eq(test.t.g, 1)] is converted into the ranges of index[g] scanned by IndexLookUp_10",
+				},
+			},
+		},
+		{
+			sql: "select * from t order by b limit 10",
+			assertSteps: []assertTraceStep{
+				{
+					assertReason: "the order by items[test.t.b] can be evaluated in tikv, so each region only needs to return its first 10 rows",
+					assertAction: "TopN_7 is pushed down as TopN_13 with limit 10 on each region, and TopN_7 merges the results with offset 0, limit 10 at root",
+				},
+			},
+		},
+		{
+			sql: "select * from t where f > 1 and f < 3",
+			assertSteps: []assertTraceStep{
+				{
+					assertReason: "the predicates[eq(test.t.f, 2)] restrict all the columns of unique index[f] to a single value",
+					assertAction: "Point_Get_5 reads the row by the value[2] of index[f] directly",
+				},
+			},
+		},
+		{
+			sql:      "select /*+ hash_agg() */ b, count(distinct c) from t group by b",
+			memQuota: 1024,
+			assertSteps: []assertTraceStep{
+				{
+					assertReason: "the estimated memory usage 125 KB of the hash table of HashAgg_5 for 8000.00 groups exceeds the memory quota 1024 Bytes of the query",
+					assertAction: "HashAgg_5 is anticipated to spill to disk",
+				},
+				{
+					assertReason: "tidb_opt_distinct_agg_push_down is disabled, though the estimated NDV of the group by items and the distinct arguments is 8000.00 for 10000.00 input rows",
+					assertAction: "the distinct aggregate functions[count(distinct test.t.c)] of HashAgg_5 are evaluated at root",
+				},
+			},
+		},
+		{
+			sql:      "select /*+ hash_agg() */ b, count(c) from t group by b",
+			memQuota: 1024,
+			assertSteps: []assertTraceStep{
+				{
+					assertReason: "the estimated memory usage 125 KB of the hash table of HashAgg_9 for 8000.00 groups exceeds the memory quota 1024 Bytes of the query",
+					assertAction: "HashAgg_9 can't spill to disk because it's executed in parallel, so the query may exceed the memory quota",
+				},
+				{
+					assertReason: "the aggregate functions[count(Column#14),firstrow(test.t.b)] can be computed as partial results on each region, the estimated cost is 30008.00 for HashAgg_5 on 10000.00 rows in the cop task and 5329.00 for HashAgg_9 on 8000.00 rows at root",
+					assertAction: "HashAgg_9 is pushed down as HashAgg_5 to compute the partial results, and HashAgg_9 merges them at root",
+				},
+			},
+		},
+		{
+			sql:                 "select count(distinct d) from t where c = 1",
+			distinctAggPushDown: true,
+			assertSteps: []assertTraceStep{
+				{
+					assertReason: "the estimated memory usage 8 Bytes of the hash table of HashAgg_9 for 1.00 groups is within the memory quota 1024 MB of the query",
+					assertAction: "HashAgg_9 is not anticipated to spill to disk",
+				},
+				{
+					assertReason: "each region deduplicates its rows by the group by items and the distinct arguments before returning them, whose estimated NDV is 8.00 for 10.00 input rows",
+					assertAction: "the distinct aggregate functions[count(distinct test.t.d)] of HashAgg_9 are pushed down to the cop task as HashAgg_6",
+				},
+				{
+					assertReason: "index[c_d_e] covers all the needed columns",
+					assertAction: "IndexReader_10 reads index[c_d_e] only, no table lookup is needed",
+				},
+				{
+					assertReason: "the predicate[eq(test.t.c, 1)] restricts the prefix columns of index[c_d_e]",
+					assertAction: "the predicate[eq(test.t.c, 1)] is converted into the ranges of index[c_d_e] scanned by IndexReader_10",
+				},
+			},
+		},
+		{
+			sql: "select count(distinct d) from t where c = 1",
+			assertSteps: []assertTraceStep{
+				{
+					assertReason: "the ranges don't cover all the columns of index[c_d_e]",
+					assertAction: "DataSource_1 can't use point get on index[c_d_e]",
+				},
+				{
+					assertReason: "tidb_opt_distinct_agg_push_down is disabled, though the estimated NDV of the group by items and the distinct arguments is 8.00 for 10.00 input rows",
+					assertAction: "the distinct aggregate functions[count(distinct test.t.d)] of StreamAgg_7 are evaluated at root",
+				},
+				{
+					assertReason: "index[c_d_e] covers all the needed columns",
+					assertAction: "IndexReader_11 reads index[c_d_e] only, no table lookup is needed",
+				},
+				{
+					assertReason: "the predicate[eq(test.t.c, 1)] restricts the prefix columns of index[c_d_e]",
+					assertAction: "the predicate[eq(test.t.c, 1)] is converted into the ranges of index[c_d_e] scanned by IndexReader_11",
+				},
+			},
+		},
+		{
+			sql: "select /*+ use_index(t, c_d_e) */ c from t order by c desc limit 5",
+			assertSteps: []assertTraceStep{
+				{
+					assertReason: "index[c_d_e] covers all the needed columns",
+					assertAction: "IndexReader_17 reads index[c_d_e] only, no table lookup is needed",
+				},
+				{
+					assertReason: "the required order can be provided by the descending order of index[c_d_e]",
+					assertAction: "IndexReader_17 scans index[c_d_e] backward to keep the order, so no sort is needed",
+				},
+			},
+		},
+		{
+			sql: "select /*+ use_index(t, c_d_e) */ * from t where c = 1 and e > 2 and b < 3",
+			assertSteps: []assertTraceStep{
+				{
+					assertReason: "the ranges don't cover all the columns of index[c_d_e]",
+					assertAction: "DataSource_1 can't use point get on index[c_d_e]",
+				},
+				{
+					assertReason: "index[c_d_e] doesn't cover the columns[test.t.b,test.t.c_str,test.t.d_str,test.t.e_str,test.t.f,test.t.g,test.t.h,test.t.i_date]",
+					assertAction: "IndexLookUp_9 reads index[c_d_e] and then looks up the table rows by handle",
+				},
+				{
+					assertReason: "the predicate[eq(test.t.c, 1)] restricts the prefix columns of index[c_d_e]",
+					assertAction: "the predicate[eq(test.t.c, 1)] is converted into the ranges of index[c_d_e] scanned by IndexLookUp_9",
+				},
+				{
+					assertReason: "the predicate[gt(test.t.e, 2)] can't be converted into the ranges of index[c_d_e], but it only needs the columns of the index",
+					assertAction: "the predicate[gt(test.t.e, 2)] is kept as a filter on the index rows read by IndexLookUp_9",
+				},
+				{
+					assertReason: "the predicate[lt(test.t.b, 3)] needs the columns not covered by index[c_d_e]",
+					assertAction: "the predicate[lt(test.t.b, 3)] is kept as a filter on the table rows looked up by IndexLookUp_9",
+				},
+			},
+		},
+		{
+			sql:     "select b, count(*) from t group by b",
+			tiflash: true,
+			assertSteps: []assertTraceStep{
+				{
+					assertReason: "the partial results of the same group must be in the same MPP task for HashAgg_23 to merge them",
+					assertAction: "ExchangeSender_24 shuffles the rows of HashAgg_8 to HashAgg_23 by the hash partition keys [name: test.t.b, collate: N/A]",
+				},
+			},
+		},
+		{
+			sql: "select * from t where a = 1",
+			assertSteps: []assertTraceStep{
+				{
+					assertReason: "the predicates[eq(test.t.a, 1)] restrict the handle to a single value",
+					assertAction: "Point_Get_5 reads the row by handle 1 directly",
+				},
+			},
+		},
+		{
+			sql: "select * from t where a in (1, 2, 3)",
+			assertSteps: []assertTraceStep{
+				{
+					assertReason: "the predicates[in(test.t.a, 1, 2, 3)] restrict the handle to 3 values",
+					assertAction: "Batch_Point_Get_5 reads the rows by the handles[1,2,3] directly",
+				},
+			},
+		},
+		{
+			sql: "select * from t where f = 1",
+			assertSteps: []assertTraceStep{
+				{
+					assertReason: "the predicates[eq(test.t.f, 1)] restrict all the columns of unique index[f] to a single value",
+					assertAction: "Point_Get_5 reads the row by the value[1] of index[f] directly",
+				},
+			},
+		},
+		{
+			sql: "select * from t where a > 1",
+			assertSteps: []assertTraceStep{
+				{
+					assertReason: "the ranges[(1,+inf]] aren't all points without NULL",
+					assertAction: "DataSource_1 can't use point get on handle",
+				},
+			},
+		},
+		{
+			sql: "select * from t where f > 1",
+			assertSteps: []assertTraceStep{
+				{
+					assertReason: "the ranges[(1,+inf]] aren't all points without NULL",
+					assertAction: "DataSource_1 can't use point get on index[f]",
+				},
+				{
+					assertReason: "the ranges don't cover all the columns of index[f_g]",
+					assertAction: "DataSource_1 can't use point get on index[f_g]",
+				},
+				{
+					assertReason: "table scan cost 142668.00, index[f] cost 113369.28 (chosen), index[f_g] cost 118174.83",
+					assertAction: "DataSource_1 reads the table by index[f], which has the lowest cost",
+				},
+				{
+					assertReason: "index[f] doesn't cover the columns[test.t.b,test.t.c,test.t.d,test.t.e,test.t.c_str,test.t.d_str,test.t.e_str,test.t.g,test.t.h,test.t.i_date]",
+					assertAction: "IndexLookUp_10 reads index[f] and then looks up the table rows by handle",
+				},
+				{
+					assertReason: "the predicate[gt(test.t.f, 1)] restricts the prefix columns of index[f]",
+					assertAction: "the predicate[gt(test.t.f, 1)] is converted into the ranges of index[f] scanned by IndexLookUp_10",
+				},
+			},
+		},
+	}
+
+	// The table t with a TiFlash replica lives in its own infoschema, so the shared one isn't changed.
+	tiflashTbl := MockSignedTable()
+	tiflashTbl.TiFlashReplica = &model.TiFlashReplicaInfo{Count: 1, Available: true}
+	tiflashIS := infoschema.MockInfoSchema([]*model.TableInfo{tiflashTbl, MockUnsignedTable(), MockView(), MockNoPKTable()})
+	for i, tc := range tt {
+		is := s.is
+		if tc.tiflash {
+			is = tiflashIS
+		}
+		sql := tc.sql
+		comment := Commentf("case:%v sql:%s", i, sql)
+		stmt, err := s.ParseOneStmt(sql, "", "")
+		c.Assert(err, IsNil, comment)
+		err = Preprocess(s.ctx, stmt, WithPreprocessorReturn(&PreprocessorReturn{InfoSchema: is}))
+		c.Assert(err, IsNil, comment)
+		sctx := MockContext()
+		sctx.GetSessionVars().StmtCtx.EnableOptimizeTrace = true
+		sctx.GetSessionVars().SnapshotInfoschema = is
+		if tc.memQuota > 0 {
+			sctx.GetSessionVars().MemQuotaQuery = tc.memQuota
+			sctx.GetSessionVars().TrackAggregateMemoryUsage = true
+		}
+		sctx.GetSessionVars().AllowDistinctAggPushDown = tc.distinctAggPushDown
+		if tc.tiflash {
+			sctx.GetSessionVars().IsolationReadEngines = map[kv.StoreType]struct{}{kv.TiFlash: {}}
+		}
+		builder, _ := NewPlanBuilder().Init(sctx, is, &hint.BlockHintProcessor{})
+		domain.GetDomain(sctx).MockInfoCacheAndLoadInfoSchema(is)
+		ctx := context.TODO()
+		p, err := builder.Build(ctx, stmt)
+		c.Assert(err, IsNil, comment)
+		p, err = logicalOptimize(ctx, builder.optFlag|flagPrunColumns|flagPredicatePushDown, p.(LogicalPlan))
+		c.Assert(err, IsNil, comment)
+		_, _, err = physicalOptimize(p.(LogicalPlan), &PlanCounterDisabled)
+		c.Assert(err, IsNil, comment)
+		otrace := sctx.GetSessionVars().StmtCtx.PhysicalOptimizeTrace
+		c.Assert(otrace, NotNil, comment)
+		c.Assert(otrace.Steps, HasLen, len(tc.assertSteps), comment)
+		for j, step := range otrace.Steps {
+			c.Assert(step.Reason, Equals, tc.assertSteps[j].assertReason, comment)
+			c.Assert(step.Action, Equals, tc.assertSteps[j].assertAction, comment)
+		}
+	}
+}
+
+func (s *testPlanSuite) TestCountAfterAccessClampTraceStep(c *C) {
+	defer testleak.AfterTest(c)()
+	stmt, err := s.ParseOneStmt("select * from t where f > 1 and f < 3", "", "")
+	c.Assert(err, IsNil)
+	err = Preprocess(s.ctx, stmt, WithPreprocessorReturn(&PreprocessorReturn{InfoSchema: s.is}))
+	c.Assert(err, IsNil)
+	sctx := MockContext()
+	sctx.GetSessionVars().StmtCtx.EnableOptimizeTrace = true
+	sctx.GetSessionVars().SnapshotInfoschema = s.is
+	builder, _ := NewPlanBuilder().Init(sctx, s.is, &hint.BlockHintProcessor{})
+	domain.GetDomain(sctx).MockInfoCacheAndLoadInfoSchema(s.is)
+	ctx := context.TODO()
+	p, err := builder.Build(ctx, stmt)
+	c.Assert(err, IsNil)
+	lp, err := logicalOptimize(ctx, builder.optFlag|flagPrunColumns|flagPredicatePushDown, p.(LogicalPlan))
+	c.Assert(err, IsNil)
+	_, _, err = physicalOptimize(lp, &PlanCounterDisabled)
+	c.Assert(err, IsNil)
+	for len(lp.Children()) > 0 {
+		lp = lp.Children()[0]
+	}
+	ds, ok := lp.(*DataSource)
+	c.Assert(ok, IsTrue)
+	var path *util.AccessPath
+	for _, candidate := range ds.possibleAccessPaths {
+		if candidate.Index != nil && candidate.Index.Name.L == "f" {
+			path = candidate
+		}
+	}
+	c.Assert(path, NotNil)
+
+	// Pretend the filters keep almost all the rows, so the row count derived from them exceeds the table row count.
+	otrace := sctx.GetSessionVars().StmtCtx.PhysicalOptimizeTrace
+	otrace.Steps = otrace.Steps[:0]
+	ds.stats.RowCount = 9000
+	ds.deriveIndexPathStats(path, ds.pushedDownConds, false)
+	c.Assert(path.CountAfterAccess, Equals, float64(10000))
+	c.Assert(otrace.Steps, HasLen, 1)
+	c.Assert(otrace.Steps[0].Reason, Equals, "the estimated row count 11250.00 of index[f] exceeds the row count 10000 of the table")
+	c.Assert(otrace.Steps[0].Action, Equals, "the estimated row count of index[f] is clamped from 11250.00 to 10000.00")
+}
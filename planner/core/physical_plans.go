@@ -969,6 +969,9 @@ type PhysicalLimit struct {
 
 	Offset uint64
 	Count  uint64
+
+	// CalcFoundRows indicates that the limit counts all the rows of its child for SQL_CALC_FOUND_ROWS.
+	CalcFoundRows bool
 }
 
 // Clone implements PhysicalPlan interface.
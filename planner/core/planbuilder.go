@@ -474,6 +474,9 @@ type PlanBuilder struct {
 	// inStraightJoin represents whether the current "SELECT" statement has
 	// "STRAIGHT_JOIN" option.
 	inStraightJoin bool
+	// inSetOprBranch represents whether the builder is building the branches
+	// of a set operation.
+	inSetOprBranch bool
 
 	// handleHelper records the handle column position for tables. Delete/Update/SelectLock/UnionScan may need this information.
 	// It collects the information by the following procedure:
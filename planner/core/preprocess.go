@@ -273,7 +273,7 @@ func (p *preprocessor) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
 	case *ast.FuncCastExpr:
 		p.checkFuncCastExpr(node)
 	case *ast.FuncCallExpr:
-		if node.FnName.L == ast.NextVal || node.FnName.L == ast.LastVal || node.FnName.L == ast.SetVal {
+		if node.FnName.L == ast.NextVal || node.FnName.L == ast.NextValN || node.FnName.L == ast.LastVal || node.FnName.L == ast.SetVal {
 			p.flag |= inSequenceFunction
 		}
 
@@ -529,7 +529,7 @@ func (p *preprocessor) Leave(in ast.Node) (out ast.Node, ok bool) {
 			}
 		}
 
-		if x.FnName.L == ast.NextVal || x.FnName.L == ast.LastVal || x.FnName.L == ast.SetVal {
+		if x.FnName.L == ast.NextVal || x.FnName.L == ast.NextValN || x.FnName.L == ast.LastVal || x.FnName.L == ast.SetVal {
 			p.flag &= ^inSequenceFunction
 		}
 	case *ast.RepairTableStmt:
@@ -124,8 +124,14 @@ func appendAggregationEliminateTraceStep(agg *LogicalAggregation, uniqueKey expr
 
 func appendDistinctEliminateTraceStep(agg *LogicalAggregation, uniqueKey expression.KeyInfo, af *aggregation.AggFuncDesc,
 	opt *logicalOptimizeOp) {
-	opt.appendStepToCurrent(agg.ID(), agg.TP(),
-		fmt.Sprintf("%s is a unique key", uniqueKey.String()),
+	reason := fmt.Sprintf("%s is a unique key", uniqueKey.String())
+	switch af.Name {
+	case ast.AggFuncMax, ast.AggFuncMin:
+	default:
+		// The results of other functions like count and sum depend on the duplicated values, so explain why they're unaffected.
+		reason += fmt.Sprintf(", so the arguments of %s(distinct ...) have no duplicated values", af.Name)
+	}
+	opt.appendStepToCurrent(agg.ID(), agg.TP(), reason,
 		fmt.Sprintf("%s(distinct ...) is simplified to %s(...)", af.Name, af.Name))
 }
 
@@ -15,7 +15,9 @@
 package core
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/expression/aggregation"
@@ -30,10 +32,57 @@ type columnPruner struct {
 }
 
 func (s *columnPruner) optimize(ctx context.Context, lp LogicalPlan, opt *logicalOptimizeOp) (LogicalPlan, error) {
-	err := lp.PruneColumns(lp.Schema().Columns)
+	err := lp.PruneColumns(lp.Schema().Columns, opt)
 	return lp, err
 }
 
+// appendColumnPruneTraceStep records the columns pruned from p, since its parent only uses part of them.
+// The pruned items are collected from the end of the schema, so they are reversed here.
+func appendColumnPruneTraceStep(p LogicalPlan, parentUsedCols []*expression.Column, prunedItems []fmt.Stringer, opt *logicalOptimizeOp) {
+	if opt == nil || opt.tracer == nil || len(prunedItems) == 0 {
+		return
+	}
+	itemString := func(i int) string {
+		switch x := p.(type) {
+		case *LogicalProjection:
+			return x.Exprs[i].String()
+		case *LogicalAggregation:
+			return x.AggFuncs[i].String()
+		}
+		return p.Schema().Columns[i].String()
+	}
+	pruned, kept, used := bytes.NewBufferString("["), bytes.NewBufferString("["), bytes.NewBufferString("[")
+	for i := len(prunedItems) - 1; i >= 0; i-- {
+		if pruned.Len() > 1 {
+			pruned.WriteString(",")
+		}
+		pruned.WriteString(prunedItems[i].String())
+	}
+	usedList := expression.GetUsedList(parentUsedCols, p.Schema())
+	for i := range p.Schema().Columns {
+		buffers := []*bytes.Buffer{kept}
+		if usedList[i] {
+			buffers = append(buffers, used)
+		}
+		for _, buffer := range buffers {
+			if buffer.Len() > 1 {
+				buffer.WriteString(",")
+			}
+			buffer.WriteString(itemString(i))
+		}
+	}
+	pruned.WriteString("]")
+	kept.WriteString("]")
+	used.WriteString("]")
+	reason := fmt.Sprintf("the parent of %v_%v only uses its columns%s", p.TP(), p.ID(), used.String())
+	if used.Len() == 2 {
+		reason = fmt.Sprintf("the parent of %v_%v uses none of its columns", p.TP(), p.ID())
+	}
+	action := fmt.Sprintf("the columns%s of %v_%v are pruned, and the columns%s are kept",
+		pruned.String(), p.TP(), p.ID(), kept.String())
+	opt.appendStepToCurrent(p.ID(), p.TP(), reason, action)
+}
+
 // ExprsHasSideEffects checks if any of the expressions has side effects.
 func ExprsHasSideEffects(exprs []expression.Expression) bool {
 	for _, expr := range exprs {
@@ -63,40 +112,45 @@ func exprHasSetVarOrSleep(expr expression.Expression) bool {
 
 // PruneColumns implements LogicalPlan interface.
 // If any expression has SetVar function or Sleep function, we do not prune it.
-func (p *LogicalProjection) PruneColumns(parentUsedCols []*expression.Column) error {
+func (p *LogicalProjection) PruneColumns(parentUsedCols []*expression.Column, opt *logicalOptimizeOp) error {
 	child := p.children[0]
 	used := expression.GetUsedList(parentUsedCols, p.schema)
 
+	var prunedItems []fmt.Stringer
 	for i := len(used) - 1; i >= 0; i-- {
 		if !used[i] && !exprHasSetVarOrSleep(p.Exprs[i]) {
+			prunedItems = append(prunedItems, p.Exprs[i])
 			p.schema.Columns = append(p.schema.Columns[:i], p.schema.Columns[i+1:]...)
 			p.Exprs = append(p.Exprs[:i], p.Exprs[i+1:]...)
 		}
 	}
+	appendColumnPruneTraceStep(p, parentUsedCols, prunedItems, opt)
 	selfUsedCols := make([]*expression.Column, 0, len(p.Exprs))
 	selfUsedCols = expression.ExtractColumnsFromExpressions(selfUsedCols, p.Exprs, nil)
-	return child.PruneColumns(selfUsedCols)
+	return child.PruneColumns(selfUsedCols, opt)
 }
 
 // PruneColumns implements LogicalPlan interface.
-func (p *LogicalSelection) PruneColumns(parentUsedCols []*expression.Column) error {
+func (p *LogicalSelection) PruneColumns(parentUsedCols []*expression.Column, opt *logicalOptimizeOp) error {
 	child := p.children[0]
 	parentUsedCols = expression.ExtractColumnsFromExpressions(parentUsedCols, p.Conditions, nil)
-	return child.PruneColumns(parentUsedCols)
+	return child.PruneColumns(parentUsedCols, opt)
 }
 
 // PruneColumns implements LogicalPlan interface.
-func (la *LogicalAggregation) PruneColumns(parentUsedCols []*expression.Column) error {
+func (la *LogicalAggregation) PruneColumns(parentUsedCols []*expression.Column, opt *logicalOptimizeOp) error {
 	child := la.children[0]
 	used := expression.GetUsedList(parentUsedCols, la.Schema())
 
 	allFirstRow := true
 	allRemainFirstRow := true
+	var prunedItems []fmt.Stringer
 	for i := len(used) - 1; i >= 0; i-- {
 		if la.AggFuncs[i].Name != ast.AggFuncFirstRow {
 			allFirstRow = false
 		}
 		if !used[i] && !ExprsHasSideEffects(la.AggFuncs[i].Args) {
+			prunedItems = append(prunedItems, la.AggFuncs[i])
 			la.schema.Columns = append(la.schema.Columns[:i], la.schema.Columns[i+1:]...)
 			la.AggFuncs = append(la.AggFuncs[:i], la.AggFuncs[i+1:]...)
 		} else if la.AggFuncs[i].Name != ast.AggFuncFirstRow {
@@ -149,7 +203,8 @@ func (la *LogicalAggregation) PruneColumns(parentUsedCols []*expression.Column)
 			la.GroupByItems = []expression.Expression{expression.NewOne()}
 		}
 	}
-	err := child.PruneColumns(selfUsedCols)
+	appendColumnPruneTraceStep(la, parentUsedCols, prunedItems, opt)
+	err := child.PruneColumns(selfUsedCols, opt)
 	if err != nil {
 		return err
 	}
@@ -193,27 +248,27 @@ func pruneByItems(old []*util.ByItems) (new []*util.ByItems, parentUsedCols []*e
 // PruneColumns implements LogicalPlan interface.
 // If any expression can view as a constant in execution stage, such as correlated column, constant,
 // we do prune them. Note that we can't prune the expressions contain non-deterministic functions, such as rand().
-func (ls *LogicalSort) PruneColumns(parentUsedCols []*expression.Column) error {
+func (ls *LogicalSort) PruneColumns(parentUsedCols []*expression.Column, opt *logicalOptimizeOp) error {
 	child := ls.children[0]
 	var cols []*expression.Column
 	ls.ByItems, cols = pruneByItems(ls.ByItems)
 	parentUsedCols = append(parentUsedCols, cols...)
-	return child.PruneColumns(parentUsedCols)
+	return child.PruneColumns(parentUsedCols, opt)
 }
 
 // PruneColumns implements LogicalPlan interface.
 // If any expression can view as a constant in execution stage, such as correlated column, constant,
 // we do prune them. Note that we can't prune the expressions contain non-deterministic functions, such as rand().
-func (lt *LogicalTopN) PruneColumns(parentUsedCols []*expression.Column) error {
+func (lt *LogicalTopN) PruneColumns(parentUsedCols []*expression.Column, opt *logicalOptimizeOp) error {
 	child := lt.children[0]
 	var cols []*expression.Column
 	lt.ByItems, cols = pruneByItems(lt.ByItems)
 	parentUsedCols = append(parentUsedCols, cols...)
-	return child.PruneColumns(parentUsedCols)
+	return child.PruneColumns(parentUsedCols, opt)
 }
 
 // PruneColumns implements LogicalPlan interface.
-func (p *LogicalUnionAll) PruneColumns(parentUsedCols []*expression.Column) error {
+func (p *LogicalUnionAll) PruneColumns(parentUsedCols []*expression.Column, opt *logicalOptimizeOp) error {
 	used := expression.GetUsedList(parentUsedCols, p.schema)
 	hasBeenUsed := false
 	for i := range used {
@@ -227,7 +282,7 @@ func (p *LogicalUnionAll) PruneColumns(parentUsedCols []*expression.Column) erro
 		copy(parentUsedCols, p.schema.Columns)
 	}
 	for _, child := range p.Children() {
-		err := child.PruneColumns(parentUsedCols)
+		err := child.PruneColumns(parentUsedCols, opt)
 		if err != nil {
 			return err
 		}
@@ -236,11 +291,14 @@ func (p *LogicalUnionAll) PruneColumns(parentUsedCols []*expression.Column) erro
 	if hasBeenUsed {
 		// keep the schema of LogicalUnionAll same as its children's
 		used := expression.GetUsedList(p.children[0].Schema().Columns, p.schema)
+		var prunedItems []fmt.Stringer
 		for i := len(used) - 1; i >= 0; i-- {
 			if !used[i] {
+				prunedItems = append(prunedItems, p.schema.Columns[i])
 				p.schema.Columns = append(p.schema.Columns[:i], p.schema.Columns[i+1:]...)
 			}
 		}
+		appendColumnPruneTraceStep(p, parentUsedCols, prunedItems, opt)
 		// It's possible that the child operator adds extra columns to the schema.
 		// Currently, (*LogicalAggregation).PruneColumns() might do this.
 		// But we don't need such columns, so we add an extra Projection to prune this column when this happened.
@@ -263,17 +321,17 @@ func (p *LogicalUnionAll) PruneColumns(parentUsedCols []*expression.Column) erro
 }
 
 // PruneColumns implements LogicalPlan interface.
-func (p *LogicalUnionScan) PruneColumns(parentUsedCols []*expression.Column) error {
+func (p *LogicalUnionScan) PruneColumns(parentUsedCols []*expression.Column, opt *logicalOptimizeOp) error {
 	for i := 0; i < p.handleCols.NumCols(); i++ {
 		parentUsedCols = append(parentUsedCols, p.handleCols.GetCol(i))
 	}
 	condCols := expression.ExtractColumnsFromExpressions(nil, p.conditions, nil)
 	parentUsedCols = append(parentUsedCols, condCols...)
-	return p.children[0].PruneColumns(parentUsedCols)
+	return p.children[0].PruneColumns(parentUsedCols, opt)
 }
 
 // PruneColumns implements LogicalPlan interface.
-func (ds *DataSource) PruneColumns(parentUsedCols []*expression.Column) error {
+func (ds *DataSource) PruneColumns(parentUsedCols []*expression.Column, opt *logicalOptimizeOp) error {
 	used := expression.GetUsedList(parentUsedCols, ds.schema)
 
 	exprCols := expression.ExtractColumnsFromExpressions(nil, ds.allConds, nil)
@@ -281,8 +339,10 @@ func (ds *DataSource) PruneColumns(parentUsedCols []*expression.Column) error {
 
 	originSchemaColumns := ds.schema.Columns
 	originColumns := ds.Columns
+	var prunedItems []fmt.Stringer
 	for i := len(used) - 1; i >= 0; i-- {
 		if !used[i] && !exprUsed[i] {
+			prunedItems = append(prunedItems, ds.schema.Columns[i])
 			ds.schema.Columns = append(ds.schema.Columns[:i], ds.schema.Columns[i+1:]...)
 			ds.Columns = append(ds.Columns[:i], ds.Columns[i+1:]...)
 		}
@@ -311,11 +371,12 @@ func (ds *DataSource) PruneColumns(parentUsedCols []*expression.Column) error {
 	if ds.handleCols != nil && ds.handleCols.IsInt() && ds.schema.ColumnIndex(ds.handleCols.GetCol(0)) == -1 {
 		ds.handleCols = nil
 	}
+	appendColumnPruneTraceStep(ds, parentUsedCols, prunedItems, opt)
 	return nil
 }
 
 // PruneColumns implements LogicalPlan interface.
-func (p *LogicalMemTable) PruneColumns(parentUsedCols []*expression.Column) error {
+func (p *LogicalMemTable) PruneColumns(parentUsedCols []*expression.Column, opt *logicalOptimizeOp) error {
 	switch p.TableInfo.Name.O {
 	case infoschema.TableStatementsSummary,
 		infoschema.TableStatementsSummaryHistory,
@@ -332,25 +393,31 @@ func (p *LogicalMemTable) PruneColumns(parentUsedCols []*expression.Column) erro
 		return nil
 	}
 	used := expression.GetUsedList(parentUsedCols, p.schema)
+	var prunedItems []fmt.Stringer
 	for i := len(used) - 1; i >= 0; i-- {
 		if !used[i] && p.schema.Len() > 1 {
+			prunedItems = append(prunedItems, p.schema.Columns[i])
 			p.schema.Columns = append(p.schema.Columns[:i], p.schema.Columns[i+1:]...)
 			p.names = append(p.names[:i], p.names[i+1:]...)
 			p.Columns = append(p.Columns[:i], p.Columns[i+1:]...)
 		}
 	}
+	appendColumnPruneTraceStep(p, parentUsedCols, prunedItems, opt)
 	return nil
 }
 
 // PruneColumns implements LogicalPlan interface.
-func (p *LogicalTableDual) PruneColumns(parentUsedCols []*expression.Column) error {
+func (p *LogicalTableDual) PruneColumns(parentUsedCols []*expression.Column, opt *logicalOptimizeOp) error {
 	used := expression.GetUsedList(parentUsedCols, p.Schema())
 
+	var prunedItems []fmt.Stringer
 	for i := len(used) - 1; i >= 0; i-- {
 		if !used[i] {
+			prunedItems = append(prunedItems, p.schema.Columns[i])
 			p.schema.Columns = append(p.schema.Columns[:i], p.schema.Columns[i+1:]...)
 		}
 	}
+	appendColumnPruneTraceStep(p, parentUsedCols, prunedItems, opt)
 	return nil
 }
 
@@ -384,16 +451,16 @@ func (p *LogicalJoin) mergeSchema() {
 }
 
 // PruneColumns implements LogicalPlan interface.
-func (p *LogicalJoin) PruneColumns(parentUsedCols []*expression.Column) error {
+func (p *LogicalJoin) PruneColumns(parentUsedCols []*expression.Column, opt *logicalOptimizeOp) error {
 	leftCols, rightCols := p.extractUsedCols(parentUsedCols)
 
-	err := p.children[0].PruneColumns(leftCols)
+	err := p.children[0].PruneColumns(leftCols, opt)
 	if err != nil {
 		return err
 	}
 	addConstOneForEmptyProjection(p.children[0])
 
-	err = p.children[1].PruneColumns(rightCols)
+	err = p.children[1].PruneColumns(rightCols, opt)
 	if err != nil {
 		return err
 	}
@@ -409,10 +476,10 @@ func (p *LogicalJoin) PruneColumns(parentUsedCols []*expression.Column) error {
 }
 
 // PruneColumns implements LogicalPlan interface.
-func (la *LogicalApply) PruneColumns(parentUsedCols []*expression.Column) error {
+func (la *LogicalApply) PruneColumns(parentUsedCols []*expression.Column, opt *logicalOptimizeOp) error {
 	leftCols, rightCols := la.extractUsedCols(parentUsedCols)
 
-	err := la.children[1].PruneColumns(rightCols)
+	err := la.children[1].PruneColumns(rightCols, opt)
 	if err != nil {
 		return err
 	}
@@ -423,7 +490,7 @@ func (la *LogicalApply) PruneColumns(parentUsedCols []*expression.Column) error
 		leftCols = append(leftCols, &col.Column)
 	}
 
-	err = la.children[0].PruneColumns(leftCols)
+	err = la.children[0].PruneColumns(leftCols, opt)
 	if err != nil {
 		return err
 	}
@@ -434,9 +501,9 @@ func (la *LogicalApply) PruneColumns(parentUsedCols []*expression.Column) error
 }
 
 // PruneColumns implements LogicalPlan interface.
-func (p *LogicalLock) PruneColumns(parentUsedCols []*expression.Column) error {
+func (p *LogicalLock) PruneColumns(parentUsedCols []*expression.Column, opt *logicalOptimizeOp) error {
 	if !IsSelectForUpdateLockType(p.Lock.LockType) {
-		return p.baseLogicalPlan.PruneColumns(parentUsedCols)
+		return p.baseLogicalPlan.PruneColumns(parentUsedCols, opt)
 	}
 
 	if len(p.partitionedTable) > 0 {
@@ -451,11 +518,11 @@ func (p *LogicalLock) PruneColumns(parentUsedCols []*expression.Column) error {
 			}
 		}
 	}
-	return p.children[0].PruneColumns(parentUsedCols)
+	return p.children[0].PruneColumns(parentUsedCols, opt)
 }
 
 // PruneColumns implements LogicalPlan interface.
-func (p *LogicalWindow) PruneColumns(parentUsedCols []*expression.Column) error {
+func (p *LogicalWindow) PruneColumns(parentUsedCols []*expression.Column, opt *logicalOptimizeOp) error {
 	windowColumns := p.GetWindowResultColumns()
 	cnt := 0
 	for _, col := range parentUsedCols {
@@ -473,7 +540,7 @@ func (p *LogicalWindow) PruneColumns(parentUsedCols []*expression.Column) error
 	}
 	parentUsedCols = parentUsedCols[:cnt]
 	parentUsedCols = p.extractUsedCols(parentUsedCols)
-	err := p.children[0].PruneColumns(parentUsedCols)
+	err := p.children[0].PruneColumns(parentUsedCols, opt)
 	if err != nil {
 		return err
 	}
@@ -499,14 +566,14 @@ func (p *LogicalWindow) extractUsedCols(parentUsedCols []*expression.Column) []*
 }
 
 // PruneColumns implements LogicalPlan interface.
-func (p *LogicalLimit) PruneColumns(parentUsedCols []*expression.Column) error {
+func (p *LogicalLimit) PruneColumns(parentUsedCols []*expression.Column, opt *logicalOptimizeOp) error {
 	if len(parentUsedCols) == 0 { // happens when LIMIT appears in UPDATE.
 		return nil
 	}
 
 	savedUsedCols := make([]*expression.Column, len(parentUsedCols))
 	copy(savedUsedCols, parentUsedCols)
-	if err := p.children[0].PruneColumns(parentUsedCols); err != nil {
+	if err := p.children[0].PruneColumns(parentUsedCols, opt); err != nil {
 		return err
 	}
 	p.schema = nil
@@ -16,6 +16,7 @@ package core
 
 import (
 	"context"
+	"fmt"
 	"math"
 
 	"github.com/pingcap/tidb/expression"
@@ -120,6 +121,7 @@ func (s *decorrelateSolver) aggDefaultValueMap(agg *LogicalAggregation) map[int]
 
 // optimize implements logicalOptRule interface.
 func (s *decorrelateSolver) optimize(ctx context.Context, p LogicalPlan, opt *logicalOptimizeOp) (LogicalPlan, error) {
+	appendScalarSubqueryTraceSteps(p, opt)
 	if apply, ok := p.(*LogicalApply); ok {
 		outerPlan := apply.children[0]
 		innerPlan := apply.children[1]
@@ -129,9 +131,11 @@ func (s *decorrelateSolver) optimize(ctx context.Context, p LogicalPlan, opt *lo
 			join := &apply.LogicalJoin
 			join.self = join
 			p = join
+			appendApplySimplifiedTraceStep(apply, opt)
 		} else if sel, ok := innerPlan.(*LogicalSelection); ok {
 			// If the inner plan is a selection, we add this condition to join predicates.
 			// Notice that no matter what kind of join is, it's always right.
+			appendSelectionPullUpTraceStep(apply, sel, opt)
 			newConds := make([]expression.Expression, 0, len(sel.Conditions))
 			for _, cond := range sel.Conditions {
 				newConds = append(newConds, cond.Decorrelate(outerPlan.Schema()))
@@ -142,6 +146,7 @@ func (s *decorrelateSolver) optimize(ctx context.Context, p LogicalPlan, opt *lo
 			return s.optimize(ctx, p, opt)
 		} else if m, ok := innerPlan.(*LogicalMaxOneRow); ok {
 			if m.children[0].MaxOneRow() {
+				appendMaxOneRowRemovedTraceStep(apply, m, opt)
 				innerPlan = m.children[0]
 				apply.SetChildren(outerPlan, innerPlan)
 				return s.optimize(ctx, p, opt)
@@ -151,6 +156,7 @@ func (s *decorrelateSolver) optimize(ctx context.Context, p LogicalPlan, opt *lo
 				proj.Exprs[i] = expr.Decorrelate(outerPlan.Schema())
 			}
 			apply.columnSubstitute(proj.Schema(), proj.Exprs)
+			appendProjectionPullUpTraceStep(apply, proj, opt)
 			innerPlan = proj.children[0]
 			apply.SetChildren(outerPlan, innerPlan)
 			if apply.JoinType != SemiJoin && apply.JoinType != LeftOuterSemiJoin && apply.JoinType != AntiSemiJoin && apply.JoinType != AntiLeftOuterSemiJoin {
@@ -167,6 +173,7 @@ func (s *decorrelateSolver) optimize(ctx context.Context, p LogicalPlan, opt *lo
 			return s.optimize(ctx, p, opt)
 		} else if agg, ok := innerPlan.(*LogicalAggregation); ok {
 			if apply.canPullUpAgg() && agg.canPullUp() {
+				appendAggPullUpTraceStep(apply, agg, opt)
 				innerPlan = agg.children[0]
 				apply.JoinType = LeftOuterJoin
 				apply.SetChildren(outerPlan, innerPlan)
@@ -270,6 +277,7 @@ func (s *decorrelateSolver) optimize(ctx context.Context, p LogicalPlan, opt *lo
 							agg.SetChildren(sel.children[0])
 						}
 						defaultValueMap := s.aggDefaultValueMap(agg)
+						appendAggEqCondPullUpTraceStep(apply, agg, eqCondWithCorCol, len(defaultValueMap) > 0, opt)
 						// We should use it directly, rather than building a projection.
 						if len(defaultValueMap) > 0 {
 							proj := LogicalProjection{}.Init(agg.ctx, agg.blockOffset)
@@ -292,10 +300,14 @@ func (s *decorrelateSolver) optimize(ctx context.Context, p LogicalPlan, opt *lo
 		} else if sort, ok := innerPlan.(*LogicalSort); ok {
 			// Since we only pull up Selection, Projection, Aggregation, MaxOneRow,
 			// the top level Sort has no effect on the subquery's result.
+			appendSortRemovedTraceStep(apply, sort, opt)
 			innerPlan = sort.children[0]
 			apply.SetChildren(outerPlan, innerPlan)
 			return s.optimize(ctx, p, opt)
 		}
+		if len(apply.CorCols) > 0 {
+			appendApplyKeptTraceStep(apply, innerPlan, opt)
+		}
 	}
 	newChildren := make([]LogicalPlan, 0, len(p.Children()))
 	for _, child := range p.Children() {
@@ -309,6 +321,125 @@ func (s *decorrelateSolver) optimize(ctx context.Context, p LogicalPlan, opt *lo
 	return p, nil
 }
 
+// appendScalarSubqueryTraceSteps reports how the scalar subqueries are evaluated, which is decided when building the plan.
+// The steps are reported only once, though the rule is applied recursively.
+func appendScalarSubqueryTraceSteps(p LogicalPlan, opt *logicalOptimizeOp) {
+	stmtCtx := p.SCtx().GetSessionVars().StmtCtx
+	for _, step := range stmtCtx.ScalarSubqueryTraceSteps {
+		opt.appendStepToCurrent(step.ID, step.TP, step.Reason, step.Action)
+	}
+	stmtCtx.ScalarSubqueryTraceSteps = nil
+}
+
+func appendApplyKeptTraceStep(apply *LogicalApply, innerPlan LogicalPlan, opt *logicalOptimizeOp) {
+	var reason string
+	switch x := innerPlan.(type) {
+	case *LogicalMaxOneRow:
+		reason = fmt.Sprintf("the inner side of %v_%v may return more than one row, so %v_%v can't be removed",
+			apply.TP(), apply.ID(), x.TP(), x.ID())
+	case *LogicalAggregation:
+		if !apply.canPullUpAgg() {
+			reason = fmt.Sprintf("%v_%v can't be pulled up, because %v_%v is not a left outer join or its outer side has no unique key",
+				x.TP(), x.ID(), apply.TP(), apply.ID())
+		} else {
+			reason = fmt.Sprintf("%v_%v can't be pulled up, because it has group by items or an aggregate function whose result is not null for empty input",
+				x.TP(), x.ID())
+		}
+	default:
+		reason = fmt.Sprintf("%v_%v in the inner side of %v_%v can't be pulled up, which blocks the decorrelation",
+			innerPlan.TP(), innerPlan.ID(), apply.TP(), apply.ID())
+	}
+	opt.appendStepToCurrent(apply.ID(), apply.TP(), reason,
+		fmt.Sprintf("%v_%v is kept and not decorrelated", apply.TP(), apply.ID()))
+}
+
+func appendApplySimplifiedTraceStep(apply *LogicalApply, opt *logicalOptimizeOp) {
+	reason := fmt.Sprintf("the inner side of %v_%v doesn't refer to any column of its outer side", apply.TP(), apply.ID())
+	action := fmt.Sprintf("%v_%v is decorrelated into a %v", apply.TP(), apply.ID(), apply.JoinType)
+	opt.appendStepToCurrent(apply.ID(), apply.TP(), reason, action)
+}
+
+// appendSelectionPullUpTraceStep should be called before decorrelating the conditions of sel, which is done in place.
+func appendSelectionPullUpTraceStep(apply *LogicalApply, sel *LogicalSelection, opt *logicalOptimizeOp) {
+	if opt.tracer == nil {
+		return
+	}
+	var corCols []*expression.Column
+	for _, cond := range sel.Conditions {
+		for _, corCol := range expression.ExtractCorColumns(cond) {
+			corCols = append(corCols, &corCol.Column)
+		}
+	}
+	reason := fmt.Sprintf("the conditions%s of %v_%v in the inner side of %v_%v can be evaluated as its join conditions",
+		predicatesString(sel.Conditions), sel.TP(), sel.ID(), apply.TP(), apply.ID())
+	if len(corCols) > 0 {
+		reason = fmt.Sprintf("the conditions%s of %v_%v in the inner side of %v_%v refer to the outer columns%s, which are available to its join conditions",
+			predicatesString(sel.Conditions), sel.TP(), sel.ID(), apply.TP(), apply.ID(), distinctColumnsString(corCols))
+	}
+	action := fmt.Sprintf("%v_%v is pulled up, and its conditions are added into the join conditions of %v_%v",
+		sel.TP(), sel.ID(), apply.TP(), apply.ID())
+	opt.appendStepToCurrent(sel.ID(), sel.TP(), reason, action)
+}
+
+func appendMaxOneRowRemovedTraceStep(apply *LogicalApply, m *LogicalMaxOneRow, opt *logicalOptimizeOp) {
+	child := m.children[0]
+	reason := fmt.Sprintf("%v_%v returns at most one row", child.TP(), child.ID())
+	action := fmt.Sprintf("%v_%v in the inner side of %v_%v is removed", m.TP(), m.ID(), apply.TP(), apply.ID())
+	opt.appendStepToCurrent(m.ID(), m.TP(), reason, action)
+}
+
+func appendProjectionPullUpTraceStep(apply *LogicalApply, proj *LogicalProjection, opt *logicalOptimizeOp) {
+	if opt.tracer == nil {
+		return
+	}
+	reason := fmt.Sprintf("%v_%v in the inner side of %v_%v doesn't change the number of rows", proj.TP(), proj.ID(), apply.TP(), apply.ID())
+	var action string
+	switch apply.JoinType {
+	case SemiJoin, LeftOuterSemiJoin, AntiSemiJoin, AntiLeftOuterSemiJoin:
+		action = fmt.Sprintf("%v_%v is removed, and its expressions%s are substituted into the join conditions of %v_%v",
+			proj.TP(), proj.ID(), predicatesString(proj.Exprs), apply.TP(), apply.ID())
+	default:
+		action = fmt.Sprintf("%v_%v is pulled up above %v_%v", proj.TP(), proj.ID(), apply.TP(), apply.ID())
+	}
+	opt.appendStepToCurrent(proj.ID(), proj.TP(), reason, action)
+}
+
+func appendAggPullUpTraceStep(apply *LogicalApply, agg *LogicalAggregation, opt *logicalOptimizeOp) {
+	if opt.tracer == nil {
+		return
+	}
+	outerPlan := apply.children[0]
+	reason := fmt.Sprintf("%v_%v has no join condition and its outer side has a unique key, and the aggregate functions of %v_%v return null for empty input",
+		apply.TP(), apply.ID(), agg.TP(), agg.ID())
+	action := fmt.Sprintf("%v_%v is pulled up above %v_%v and grouped by the unique key%s of %v_%v, and %v_%v becomes a left outer join",
+		agg.TP(), agg.ID(), apply.TP(), apply.ID(), distinctColumnsString(outerPlan.Schema().Keys[0]), outerPlan.TP(), outerPlan.ID(), apply.TP(), apply.ID())
+	opt.appendStepToCurrent(agg.ID(), agg.TP(), reason, action)
+}
+
+func appendAggEqCondPullUpTraceStep(apply *LogicalApply, agg *LogicalAggregation, eqConds []*expression.ScalarFunction, addIfNull bool, opt *logicalOptimizeOp) {
+	if opt.tracer == nil {
+		return
+	}
+	conds := make([]expression.Expression, 0, len(eqConds))
+	for _, cond := range eqConds {
+		conds = append(conds, cond)
+	}
+	reason := fmt.Sprintf("only the equal conditions%s below %v_%v refer to the outer side of %v_%v",
+		predicatesString(conds), agg.TP(), agg.ID(), apply.TP(), apply.ID())
+	action := fmt.Sprintf("the equal conditions%s are pulled up as the join keys of %v_%v, and the group by items of %v_%v become%s",
+		predicatesString(conds), apply.TP(), apply.ID(), agg.TP(), agg.ID(), predicatesString(agg.GroupByItems))
+	if addIfNull {
+		action += ", a projection is added to fill the default values of the aggregate functions for the unmatched outer rows"
+	}
+	opt.appendStepToCurrent(agg.ID(), agg.TP(), reason, action)
+}
+
+func appendSortRemovedTraceStep(apply *LogicalApply, sort *LogicalSort, opt *logicalOptimizeOp) {
+	reason := fmt.Sprintf("the order of the rows from %v_%v doesn't affect the result of the subquery", sort.TP(), sort.ID())
+	action := fmt.Sprintf("%v_%v in the inner side of %v_%v is removed", sort.TP(), sort.ID(), apply.TP(), apply.ID())
+	opt.appendStepToCurrent(sort.ID(), sort.TP(), reason, action)
+}
+
 func (*decorrelateSolver) name() string {
 	return "decorrelate"
 }
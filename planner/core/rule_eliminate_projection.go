@@ -21,6 +21,7 @@ import (
 
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
 )
 
@@ -163,6 +164,10 @@ func (pe *projectionEliminator) eliminate(p LogicalPlan, replace map[string]*exp
 	} else if _, isWindow := p.(*LogicalWindow); isWindow {
 		childFlag = true
 	}
+	if union, isUnion := p.(*LogicalUnionAll); isUnion {
+		// The projections of the children are recorded before they are merged with the projections below them.
+		appendUnionCastTraceStep(union, opt)
+	}
 	for i, child := range p.Children() {
 		p.Children()[i] = pe.eliminate(child, replace, childFlag, opt)
 	}
@@ -319,3 +324,49 @@ func appendProjEliminateTraceStep(proj *LogicalProjection, opt *logicalOptimizeO
 	action := fmt.Sprintf("Proj[%v] is eliminated", proj.ID())
 	opt.appendStepToCurrent(proj.ID(), proj.TP(), reason, action)
 }
+
+// unionCastRuleName is the rule name of the trace steps of the casts which unify the types of the children of a
+// union. The casts are added to the projections above the children when building the union, and are recorded by the
+// projection_eliminate rule which handles these projections.
+const unionCastRuleName = "union_cast"
+
+// appendUnionCastTraceStep records the casts kept in the projections under the union, which unify the types of
+// the children of the union.
+func appendUnionCastTraceStep(union *LogicalUnionAll, opt *logicalOptimizeOp) {
+	for i, child := range union.Children() {
+		// The projections added to unify the types are built with AvoidColumnEvaluator, while the casts in the
+		// other projections are written in the query.
+		proj, ok := child.(*LogicalProjection)
+		if !ok || !proj.AvoidColumnEvaluator {
+			continue
+		}
+		cols := bytes.NewBufferString("")
+		srcTps := bytes.NewBufferString("")
+		dstTps := bytes.NewBufferString("")
+		for j, expr := range proj.Exprs {
+			sf, ok := expr.(*expression.ScalarFunction)
+			if !ok || sf.FuncName.L != ast.Cast {
+				continue
+			}
+			arg, dstTp := sf.GetArgs()[0], union.Schema().Columns[j].RetType
+			if arg.GetType().Equal(dstTp) {
+				continue
+			}
+			if cols.Len() > 0 {
+				cols.WriteString(",")
+				srcTps.WriteString(",")
+				dstTps.WriteString(",")
+			}
+			cols.WriteString(arg.String())
+			srcTps.WriteString(arg.GetType().String())
+			dstTps.WriteString(dstTp.String())
+		}
+		if cols.Len() == 0 {
+			continue
+		}
+		reason := fmt.Sprintf("the types[%s] of the columns[%s] in Union[%v]'s child %v differ from the union result types[%s]",
+			srcTps.String(), cols.String(), union.ID(), i, dstTps.String())
+		action := fmt.Sprintf("Proj[%v] casts the columns[%s] to the union result types", proj.ID(), cols.String())
+		opt.appendStepToSubRule(unionCastRuleName, proj.ID(), proj.TP(), reason, action)
+	}
+}
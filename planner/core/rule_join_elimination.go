@@ -15,7 +15,9 @@
 package core
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/parser/ast"
@@ -32,7 +34,7 @@ type outerJoinEliminator struct {
 // 2. outer join elimination with duplicate agnostic aggregate functions: For example left outer join.
 //    If the parent only use the columns from left table with 'distinct' label. The left outer join can
 //    be eliminated.
-func (o *outerJoinEliminator) tryToEliminateOuterJoin(p *LogicalJoin, aggCols []*expression.Column, parentCols []*expression.Column) (LogicalPlan, bool, error) {
+func (o *outerJoinEliminator) tryToEliminateOuterJoin(p *LogicalJoin, aggCols []*expression.Column, parentCols []*expression.Column, opt *logicalOptimizeOp) (LogicalPlan, bool, error) {
 	var innerChildIdx int
 	switch p.JoinType {
 	case LeftOuterJoin:
@@ -56,6 +58,7 @@ func (o *outerJoinEliminator) tryToEliminateOuterJoin(p *LogicalJoin, aggCols []
 	// outer join elimination with duplicate agnostic aggregate functions
 	matched = IsColsAllFromOuterTable(aggCols, outerUniqueIDs)
 	if matched {
+		appendOuterJoinEliminateAggregationTraceStep(p, outerPlan, aggCols, opt)
 		return outerPlan, true, nil
 	}
 	// outer join elimination without duplicate agnostic aggregate functions
@@ -65,6 +68,7 @@ func (o *outerJoinEliminator) tryToEliminateOuterJoin(p *LogicalJoin, aggCols []
 		return p, false, err
 	}
 	if contain {
+		appendOuterJoinEliminateTraceStep(p, outerPlan, parentCols, innerJoinKeys, opt)
 		return outerPlan, true, nil
 	}
 	contain, err = o.isInnerJoinKeysContainIndex(innerPlan, innerJoinKeys)
@@ -72,6 +76,7 @@ func (o *outerJoinEliminator) tryToEliminateOuterJoin(p *LogicalJoin, aggCols []
 		return p, false, err
 	}
 	if contain {
+		appendOuterJoinEliminateTraceStep(p, outerPlan, parentCols, innerJoinKeys, opt)
 		return outerPlan, true, nil
 	}
 
@@ -178,11 +183,11 @@ func GetDupAgnosticAggCols(
 	return true, newAggCols
 }
 
-func (o *outerJoinEliminator) doOptimize(p LogicalPlan, aggCols []*expression.Column, parentCols []*expression.Column) (LogicalPlan, error) {
+func (o *outerJoinEliminator) doOptimize(p LogicalPlan, aggCols []*expression.Column, parentCols []*expression.Column, opt *logicalOptimizeOp) (LogicalPlan, error) {
 	var err error
 	var isEliminated bool
 	for join, isJoin := p.(*LogicalJoin); isJoin; join, isJoin = p.(*LogicalJoin) {
-		p, isEliminated, err = o.tryToEliminateOuterJoin(join, aggCols, parentCols)
+		p, isEliminated, err = o.tryToEliminateOuterJoin(join, aggCols, parentCols, opt)
 		if err != nil {
 			return p, err
 		}
@@ -216,7 +221,7 @@ func (o *outerJoinEliminator) doOptimize(p LogicalPlan, aggCols []*expression.Co
 	}
 
 	for i, child := range p.Children() {
-		newChild, err := o.doOptimize(child, aggCols, parentCols)
+		newChild, err := o.doOptimize(child, aggCols, parentCols, opt)
 		if err != nil {
 			return nil, err
 		}
@@ -226,10 +231,53 @@ func (o *outerJoinEliminator) doOptimize(p LogicalPlan, aggCols []*expression.Co
 }
 
 func (o *outerJoinEliminator) optimize(ctx context.Context, p LogicalPlan, opt *logicalOptimizeOp) (LogicalPlan, error) {
-	p, err := o.doOptimize(p, nil, nil)
+	p, err := o.doOptimize(p, nil, nil, opt)
 	return p, err
 }
 
 func (*outerJoinEliminator) name() string {
 	return "outer_join_eliminate"
 }
+
+// appendOuterJoinEliminateTraceStep records that the outer join is eliminated because its parent only uses the
+// columns of the outer side, and each outer row matches at most one inner row.
+func appendOuterJoinEliminateTraceStep(join *LogicalJoin, outerPlan LogicalPlan, parentCols []*expression.Column, innerJoinKeys *expression.Schema, opt *logicalOptimizeOp) {
+	reason := fmt.Sprintf("the parent of %v_%v only uses the columns%s of its outer side, and the join keys%s of its inner side are unique, so each outer row matches at most one inner row",
+		join.TP(), join.ID(), distinctColumnsString(parentCols), distinctColumnsString(innerJoinKeys.Columns))
+	appendOuterJoinEliminatedStep(join, outerPlan, reason, opt)
+}
+
+// appendOuterJoinEliminateAggregationTraceStep records that the outer join is eliminated because the aggregate
+// functions above it are duplicate agnostic, so the duplicated outer rows produced by the join don't matter.
+func appendOuterJoinEliminateAggregationTraceStep(join *LogicalJoin, outerPlan LogicalPlan, aggCols []*expression.Column, opt *logicalOptimizeOp) {
+	reason := fmt.Sprintf("the aggregate functions above %v_%v only use the columns%s of its outer side and are duplicate agnostic, so the duplicated outer rows don't change the result",
+		join.TP(), join.ID(), distinctColumnsString(aggCols))
+	appendOuterJoinEliminatedStep(join, outerPlan, reason, opt)
+}
+
+func appendOuterJoinEliminatedStep(join *LogicalJoin, outerPlan LogicalPlan, reason string, opt *logicalOptimizeOp) {
+	side := "left"
+	if join.JoinType == RightOuterJoin {
+		side = "right"
+	}
+	action := fmt.Sprintf("%v_%v is eliminated, and its %s child %v_%v is kept", join.TP(), join.ID(), side, outerPlan.TP(), outerPlan.ID())
+	opt.appendStepToCurrent(join.ID(), join.TP(), reason, action)
+}
+
+// distinctColumnsString joins the distinct columns into a string like "[test.t.a,test.t.b]" for the trace steps.
+func distinctColumnsString(cols []*expression.Column) string {
+	buffer := bytes.NewBufferString("[")
+	visited := make(map[int64]struct{}, len(cols))
+	for _, col := range cols {
+		if _, ok := visited[col.UniqueID]; ok {
+			continue
+		}
+		if len(visited) > 0 {
+			buffer.WriteString(",")
+		}
+		visited[col.UniqueID] = struct{}{}
+		buffer.WriteString(col.String())
+	}
+	buffer.WriteString("]")
+	return buffer.String()
+}
@@ -16,6 +16,8 @@ package core
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/sessionctx"
@@ -29,7 +31,7 @@ import (
 // results in a join group {a, b, LeftJoin(c, d)}.
 func extractJoinGroup(p LogicalPlan) (group []LogicalPlan, eqEdges []*expression.ScalarFunction, otherConds []expression.Expression) {
 	join, isJoin := p.(*LogicalJoin)
-	if !isJoin || join.preferJoinType > uint(0) || join.JoinType != InnerJoin || join.StraightJoin {
+	if !isJoin || !isReorderableJoin(join) {
 		return []LogicalPlan{p}, nil, nil
 	}
 
@@ -47,6 +49,11 @@ func extractJoinGroup(p LogicalPlan) (group []LogicalPlan, eqEdges []*expression
 	return group, eqEdges, otherConds
 }
 
+// isReorderableJoin checks whether the join can be a part of a join group.
+func isReorderableJoin(join *LogicalJoin) bool {
+	return join.preferJoinType == uint(0) && join.JoinType == InnerJoin && !join.StraightJoin
+}
+
 type joinReOrderSolver struct {
 }
 
@@ -56,16 +63,21 @@ type jrNode struct {
 }
 
 func (s *joinReOrderSolver) optimize(ctx context.Context, p LogicalPlan, opt *logicalOptimizeOp) (LogicalPlan, error) {
-	return s.optimizeRecursive(p.SCtx(), p)
+	return s.optimizeRecursive(p.SCtx(), p, opt)
 }
 
 // optimizeRecursive recursively collects join groups and applies join reorder algorithm for each group.
-func (s *joinReOrderSolver) optimizeRecursive(ctx sessionctx.Context, p LogicalPlan) (LogicalPlan, error) {
+func (s *joinReOrderSolver) optimizeRecursive(ctx sessionctx.Context, p LogicalPlan, opt *logicalOptimizeOp) (LogicalPlan, error) {
 	var err error
 	curJoinGroup, eqEdges, otherConds := extractJoinGroup(p)
 	if len(curJoinGroup) > 1 {
+		originalJoin := p
+		var originalOrder string
+		if opt != nil && opt.tracer != nil {
+			originalOrder = joinOrderString(p)
+		}
 		for i := range curJoinGroup {
-			curJoinGroup[i], err = s.optimizeRecursive(ctx, curJoinGroup[i])
+			curJoinGroup[i], err = s.optimizeRecursive(ctx, curJoinGroup[i], opt)
 			if err != nil {
 				return nil, err
 			}
@@ -74,8 +86,12 @@ func (s *joinReOrderSolver) optimizeRecursive(ctx sessionctx.Context, p LogicalP
 			ctx:        ctx,
 			otherConds: otherConds,
 		}
+		if opt != nil && opt.tracer != nil {
+			baseGroupSolver.trace = &joinReorderTrace{}
+		}
 		originalSchema := p.Schema()
-		if len(curJoinGroup) > ctx.GetSessionVars().TiDBOptJoinReorderThreshold {
+		useGreedy := len(curJoinGroup) > ctx.GetSessionVars().TiDBOptJoinReorderThreshold
+		if useGreedy {
 			groupSolver := &joinReorderGreedySolver{
 				baseSingleGroupJoinOrderSolver: baseGroupSolver,
 				eqEdges:                        eqEdges,
@@ -91,6 +107,7 @@ func (s *joinReOrderSolver) optimizeRecursive(ctx sessionctx.Context, p LogicalP
 		if err != nil {
 			return nil, err
 		}
+		appendJoinReorderTraceStep(originalJoin, originalOrder, p, len(curJoinGroup), useGreedy, baseGroupSolver.trace, opt)
 		schemaChanged := false
 		if len(p.Schema().Columns) != len(originalSchema.Columns) {
 			schemaChanged = true
@@ -114,7 +131,7 @@ func (s *joinReOrderSolver) optimizeRecursive(ctx sessionctx.Context, p LogicalP
 	}
 	newChildren := make([]LogicalPlan, 0, len(p.Children()))
 	for _, child := range p.Children() {
-		newChild, err := s.optimizeRecursive(ctx, child)
+		newChild, err := s.optimizeRecursive(ctx, child, opt)
 		if err != nil {
 			return nil, err
 		}
@@ -129,6 +146,8 @@ type baseSingleGroupJoinOrderSolver struct {
 	ctx          sessionctx.Context
 	curJoinGroup []*jrNode
 	otherConds   []expression.Expression
+	// trace records the candidate joins built during the reorder, it's nil if the optimizer trace is disabled.
+	trace *joinReorderTrace
 }
 
 // baseNodeCumCost calculate the cumulative cost of the node in the join group.
@@ -188,7 +207,80 @@ func (s *baseSingleGroupJoinOrderSolver) newJoinWithEdges(lChild, rChild Logical
 
 // calcJoinCumCost calculates the cumulative cost of the join node.
 func (s *baseSingleGroupJoinOrderSolver) calcJoinCumCost(join LogicalPlan, lNode, rNode *jrNode) float64 {
-	return join.statsInfo().RowCount + lNode.cumCost + rNode.cumCost
+	cost := join.statsInfo().RowCount + lNode.cumCost + rNode.cumCost
+	s.trace.appendCandidate(join, cost)
+	return cost
+}
+
+// joinReorderTrace records the cumulative cost of each candidate join order estimated during the reorder.
+type joinReorderTrace struct {
+	candidates []string
+	costs      map[string]float64
+}
+
+func (t *joinReorderTrace) appendCandidate(join LogicalPlan, cost float64) {
+	if t == nil {
+		return
+	}
+	order := joinOrderString(join)
+	if t.costs == nil {
+		t.costs = make(map[string]float64)
+	}
+	if _, ok := t.costs[order]; !ok {
+		t.candidates = append(t.candidates, order)
+	}
+	t.costs[order] = cost
+}
+
+func (t *joinReorderTrace) String() string {
+	var buffer strings.Builder
+	buffer.WriteString("[")
+	for i, order := range t.candidates {
+		if i > 0 {
+			buffer.WriteString(", ")
+		}
+		fmt.Fprintf(&buffer, "%v:%v", order, t.costs[order])
+	}
+	buffer.WriteString("]")
+	return buffer.String()
+}
+
+// joinOrderString formats the join order of the join group rooted at p, e.g. "((t1*t2)*t3)".
+func joinOrderString(p LogicalPlan) string {
+	switch x := p.(type) {
+	case *LogicalJoin:
+		if isReorderableJoin(x) {
+			return fmt.Sprintf("(%v*%v)", joinOrderString(x.children[0]), joinOrderString(x.children[1]))
+		}
+	case *DataSource:
+		if x.TableAsName != nil && len(x.TableAsName.L) > 0 {
+			return x.TableAsName.L
+		}
+	}
+	return fmt.Sprintf("%v_%v", p.TP(), p.ID())
+}
+
+func appendJoinReorderTraceStep(originalJoin LogicalPlan, originalOrder string, final LogicalPlan, groupLen int,
+	useGreedy bool, trace *joinReorderTrace, opt *logicalOptimizeOp) {
+	if trace == nil {
+		return
+	}
+	threshold := originalJoin.SCtx().GetSessionVars().TiDBOptJoinReorderThreshold
+	var buffer strings.Builder
+	if useGreedy {
+		fmt.Fprintf(&buffer, "the join group has %v nodes, more than tidb_opt_join_reorder_threshold(%v), so the greedy algorithm is used", groupLen, threshold)
+	} else {
+		fmt.Fprintf(&buffer, "the join group has %v nodes, not more than tidb_opt_join_reorder_threshold(%v), so the dp algorithm is used", groupLen, threshold)
+	}
+	if len(trace.candidates) > 0 {
+		fmt.Fprintf(&buffer, ", and the estimated costs of the candidate join orders are %v", trace)
+	}
+	finalOrder := joinOrderString(final)
+	action := fmt.Sprintf("the join order of %v_%v is changed from %v to %v", originalJoin.TP(), originalJoin.ID(), originalOrder, finalOrder)
+	if finalOrder == originalOrder {
+		action = fmt.Sprintf("the join order of %v_%v is kept as %v", originalJoin.TP(), originalJoin.ID(), originalOrder)
+	}
+	opt.appendStepToCurrent(originalJoin.ID(), originalJoin.TP(), buffer.String(), action)
 }
 
 func (*joinReOrderSolver) name() string {
@@ -142,9 +142,11 @@ func (a *maxMinEliminator) splitAggFuncAndCheckIndices(agg *LogicalAggregation,
 		// We must make sure the args of max/min is a simple single column.
 		col, ok := f.Args[0].(*expression.Column)
 		if !ok {
+			appendSkipMultiMaxMinTraceStep(agg, fmt.Sprintf("the argument of function[%s] in agg[%v] isn't a simple column", f.Name, agg.ID()), opt)
 			return nil, false
 		}
 		if !a.checkColCanUseIndex(agg.children[0], col, make([]expression.Expression, 0)) {
+			appendSkipMultiMaxMinTraceStep(agg, fmt.Sprintf("the column[%s] of function[%s] in agg[%v] can't benefit from any index/primary key", col, f.Name, agg.ID()), opt)
 			return nil, false
 		}
 	}
@@ -154,7 +156,7 @@ func (a *maxMinEliminator) splitAggFuncAndCheckIndices(agg *LogicalAggregation,
 		newAgg := LogicalAggregation{AggFuncs: []*aggregation.AggFuncDesc{f}}.Init(agg.ctx, agg.blockOffset)
 		newAgg.SetChildren(a.cloneSubPlans(agg.children[0]))
 		newAgg.schema = expression.NewSchema(agg.schema.Columns[i])
-		if err := newAgg.PruneColumns([]*expression.Column{newAgg.schema.Columns[0]}); err != nil {
+		if err := newAgg.PruneColumns([]*expression.Column{newAgg.schema.Columns[0]}, nil); err != nil {
 			return nil, false
 		}
 		aggs = append(aggs, newAgg)
@@ -266,6 +268,8 @@ func appendEliminateSingleMaxMinTrace(agg *LogicalAggregation, sel *LogicalSelec
 		buffer := bytes.NewBufferString(fmt.Sprintf("agg[%v] has only one function[%s] without group by", agg.ID(), agg.AggFuncs[0].Name))
 		if sel != nil {
 			buffer.WriteString(fmt.Sprintf(", the columns in agg[%v] shouldn't be NULL and needs NULL to be filtered out", agg.ID()))
+		} else if sort != nil {
+			buffer.WriteString(fmt.Sprintf(", the columns in agg[%v] are NOT NULL so no selection is needed to filter NULL out", agg.ID()))
 		}
 		if sort != nil {
 			buffer.WriteString(fmt.Sprintf(", the columns in agg[%v] should be sorted", agg.ID()))
@@ -307,3 +311,8 @@ func appendEliminateMultiMinMaxTraceStep(originAgg *LogicalAggregation, aggs []*
 	}()
 	opt.appendStepToCurrent(originAgg.ID(), originAgg.TP(), reason, action)
 }
+
+func appendSkipMultiMaxMinTraceStep(agg *LogicalAggregation, reason string, opt *logicalOptimizeOp) {
+	action := fmt.Sprintf("agg[%v] isn't split into single min/max aggs, so its multi min/max functions aren't eliminated", agg.ID())
+	opt.appendStepToCurrent(agg.ID(), agg.TP(), reason, action)
+}
@@ -15,6 +15,7 @@
 package core
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	gomath "math"
@@ -58,18 +59,18 @@ const FullRange = -1
 type partitionProcessor struct{}
 
 func (s *partitionProcessor) optimize(ctx context.Context, lp LogicalPlan, opt *logicalOptimizeOp) (LogicalPlan, error) {
-	p, err := s.rewriteDataSource(lp)
+	p, err := s.rewriteDataSource(lp, opt)
 	return p, err
 }
 
-func (s *partitionProcessor) rewriteDataSource(lp LogicalPlan) (LogicalPlan, error) {
+func (s *partitionProcessor) rewriteDataSource(lp LogicalPlan, opt *logicalOptimizeOp) (LogicalPlan, error) {
 	// Assert there will not be sel -> sel in the ast.
 	switch p := lp.(type) {
 	case *DataSource:
-		return s.prune(p)
+		return s.prune(p, opt)
 	case *LogicalUnionScan:
 		ds := p.Children()[0]
-		ds, err := s.prune(ds.(*DataSource))
+		ds, err := s.prune(ds.(*DataSource), opt)
 		if err != nil {
 			return nil, err
 		}
@@ -94,7 +95,7 @@ func (s *partitionProcessor) rewriteDataSource(lp LogicalPlan) (LogicalPlan, err
 	default:
 		children := lp.Children()
 		for i, child := range children {
-			newChild, err := s.rewriteDataSource(child)
+			newChild, err := s.rewriteDataSource(child, opt)
 			if err != nil {
 				return nil, err
 			}
@@ -324,7 +325,7 @@ func (s *partitionProcessor) reconstructTableColNames(ds *DataSource) ([]*types.
 	return names, nil
 }
 
-func (s *partitionProcessor) processHashPartition(ds *DataSource, pi *model.PartitionInfo) (LogicalPlan, error) {
+func (s *partitionProcessor) processHashPartition(ds *DataSource, pi *model.PartitionInfo, opt *logicalOptimizeOp) (LogicalPlan, error) {
 	names, err := s.reconstructTableColNames(ds)
 	if err != nil {
 		return nil, err
@@ -334,10 +335,11 @@ func (s *partitionProcessor) processHashPartition(ds *DataSource, pi *model.Part
 		return nil, err
 	}
 	if used != nil {
-		return s.makeUnionAllChildren(ds, pi, convertToRangeOr(used, pi))
+		return s.makeUnionAllChildren(ds, pi, convertToRangeOr(used, pi), opt)
 	}
 	tableDual := LogicalTableDual{RowCount: 0}.Init(ds.SCtx(), ds.blockOffset)
 	tableDual.schema = ds.Schema()
+	appendNoPartitionSelectedTraceStep(ds, tableDual, opt)
 	return tableDual, nil
 }
 
@@ -617,7 +619,7 @@ func (s *partitionProcessor) pruneListPartition(ctx sessionctx.Context, tbl tabl
 	return used, nil
 }
 
-func (s *partitionProcessor) prune(ds *DataSource) (LogicalPlan, error) {
+func (s *partitionProcessor) prune(ds *DataSource, opt *logicalOptimizeOp) (LogicalPlan, error) {
 	pi := ds.tableInfo.GetPartitionInfo()
 	if pi == nil {
 		return ds, nil
@@ -631,15 +633,15 @@ func (s *partitionProcessor) prune(ds *DataSource) (LogicalPlan, error) {
 	// Try to locate partition directly for hash partition.
 	switch pi.Type {
 	case model.PartitionTypeRange:
-		return s.processRangePartition(ds, pi)
+		return s.processRangePartition(ds, pi, opt)
 	case model.PartitionTypeHash:
-		return s.processHashPartition(ds, pi)
+		return s.processHashPartition(ds, pi, opt)
 	case model.PartitionTypeList:
-		return s.processListPartition(ds, pi)
+		return s.processListPartition(ds, pi, opt)
 	}
 
 	// We haven't implement partition by list and so on.
-	return s.makeUnionAllChildren(ds, pi, fullRange(len(pi.Definitions)))
+	return s.makeUnionAllChildren(ds, pi, fullRange(len(pi.Definitions)), opt)
 }
 
 // findByName checks whether object name exists in list.
@@ -872,7 +874,7 @@ func (s *partitionProcessor) pruneRangePartition(ctx sessionctx.Context, pi *mod
 	return result, newConds, nil
 }
 
-func (s *partitionProcessor) processRangePartition(ds *DataSource, pi *model.PartitionInfo) (LogicalPlan, error) {
+func (s *partitionProcessor) processRangePartition(ds *DataSource, pi *model.PartitionInfo, opt *logicalOptimizeOp) (LogicalPlan, error) {
 	used, prunedConds, err := s.pruneRangePartition(ds.ctx, pi, ds.table.(table.PartitionedTable), ds.allConds, ds.TblCols, ds.names, &ds.pushedDownConds)
 	if err != nil {
 		return nil, err
@@ -880,19 +882,20 @@ func (s *partitionProcessor) processRangePartition(ds *DataSource, pi *model.Par
 	if prunedConds != nil {
 		ds.pushedDownConds = prunedConds
 	}
-	return s.makeUnionAllChildren(ds, pi, used)
+	return s.makeUnionAllChildren(ds, pi, used, opt)
 }
 
-func (s *partitionProcessor) processListPartition(ds *DataSource, pi *model.PartitionInfo) (LogicalPlan, error) {
+func (s *partitionProcessor) processListPartition(ds *DataSource, pi *model.PartitionInfo, opt *logicalOptimizeOp) (LogicalPlan, error) {
 	used, err := s.pruneListPartition(ds.SCtx(), ds.table, ds.partitionNames, ds.allConds)
 	if err != nil {
 		return nil, err
 	}
 	if used != nil {
-		return s.makeUnionAllChildren(ds, pi, convertToRangeOr(used, pi))
+		return s.makeUnionAllChildren(ds, pi, convertToRangeOr(used, pi), opt)
 	}
 	tableDual := LogicalTableDual{RowCount: 0}.Init(ds.SCtx(), ds.blockOffset)
 	tableDual.schema = ds.Schema()
+	appendNoPartitionSelectedTraceStep(ds, tableDual, opt)
 	return tableDual, nil
 }
 
@@ -1391,7 +1394,7 @@ func (s *partitionProcessor) checkHintsApplicable(ds *DataSource, partitionSet s
 	appendWarnForUnknownPartitions(ds.ctx, HintReadFromStorage, unknownPartitions)
 }
 
-func (s *partitionProcessor) makeUnionAllChildren(ds *DataSource, pi *model.PartitionInfo, or partitionRangeOR) (LogicalPlan, error) {
+func (s *partitionProcessor) makeUnionAllChildren(ds *DataSource, pi *model.PartitionInfo, or partitionRangeOR, opt *logicalOptimizeOp) (LogicalPlan, error) {
 	children := make([]LogicalPlan, 0, len(pi.Definitions))
 	partitionNameSet := make(set.StringSet)
 	for _, r := range or {
@@ -1432,8 +1435,10 @@ func (s *partitionProcessor) makeUnionAllChildren(ds *DataSource, pi *model.Part
 		// No result after table pruning.
 		tableDual := LogicalTableDual{RowCount: 0}.Init(ds.SCtx(), ds.blockOffset)
 		tableDual.schema = ds.Schema()
+		appendNoPartitionSelectedTraceStep(ds, tableDual, opt)
 		return tableDual, nil
 	}
+	appendMakeUnionAllChildrenTraceStep(ds, pi, partitionNameSet, opt)
 	if len(children) == 1 {
 		// No need for the union all.
 		return children[0], nil
@@ -1444,6 +1449,60 @@ func (s *partitionProcessor) makeUnionAllChildren(ds *DataSource, pi *model.Part
 	return unionAll, nil
 }
 
+// partitionPruneReason explains which partitions of ds can be accessed, by the conditions of ds and the partitions
+// specified by the `PARTITION` clause.
+func partitionPruneReason(ds *DataSource) string {
+	var buffer bytes.Buffer
+	if len(ds.allConds) > 0 {
+		fmt.Fprintf(&buffer, "the partitions of %v_%v are pruned by the conditions%s", ds.TP(), ds.ID(), predicatesString(ds.allConds))
+	} else {
+		fmt.Fprintf(&buffer, "%v_%v has no conditions to prune the partitions", ds.TP(), ds.ID())
+	}
+	if len(ds.partitionNames) > 0 {
+		names := make([]string, 0, len(ds.partitionNames))
+		for _, name := range ds.partitionNames {
+			names = append(names, name.O)
+		}
+		fmt.Fprintf(&buffer, ", and only the partitions[%s] are specified by the PARTITION clause", strings.Join(names, ","))
+	}
+	return buffer.String()
+}
+
+// partitionPruneRuleName is the rule name of the trace steps of the partition pruning, which is applied by the
+// partition processor.
+const partitionPruneRuleName = "partition_prune"
+
+// appendMakeUnionAllChildrenTraceStep records the partitions of ds which are pruned and the ones which are kept.
+func appendMakeUnionAllChildrenTraceStep(ds *DataSource, pi *model.PartitionInfo, kept set.StringSet, opt *logicalOptimizeOp) {
+	if opt == nil || opt.tracer == nil {
+		return
+	}
+	keptNames := make([]string, 0, len(kept))
+	prunedNames := make([]string, 0, len(pi.Definitions)-len(kept))
+	for _, def := range pi.Definitions {
+		if kept.Exist(def.Name.L) {
+			keptNames = append(keptNames, def.Name.O)
+		} else {
+			prunedNames = append(prunedNames, def.Name.O)
+		}
+	}
+	var action string
+	if len(prunedNames) == 0 {
+		action = fmt.Sprintf("all the partitions[%s] of %v_%v are kept", strings.Join(keptNames, ","), ds.TP(), ds.ID())
+	} else {
+		action = fmt.Sprintf("the partitions[%s] of %v_%v are pruned, and the partitions[%s] are kept",
+			strings.Join(prunedNames, ","), ds.TP(), ds.ID(), strings.Join(keptNames, ","))
+	}
+	opt.appendStepToSubRule(partitionPruneRuleName, ds.ID(), ds.TP(), partitionPruneReason(ds), action)
+}
+
+// appendNoPartitionSelectedTraceStep records that all the partitions of ds are pruned, so ds is replaced by tableDual.
+func appendNoPartitionSelectedTraceStep(ds *DataSource, tableDual LogicalPlan, opt *logicalOptimizeOp) {
+	action := fmt.Sprintf("all the partitions of %v_%v are pruned, and %v_%v is replaced by %v_%v",
+		ds.TP(), ds.ID(), ds.TP(), ds.ID(), tableDual.TP(), tableDual.ID())
+	opt.appendStepToSubRule(partitionPruneRuleName, ds.ID(), ds.TP(), partitionPruneReason(ds), action)
+}
+
 func (s *partitionProcessor) pruneRangeColumnsPartition(ctx sessionctx.Context, conds []expression.Expression, pi *model.PartitionInfo, pe *tables.PartitionExpr, columns []*expression.Column, names types.NameSlice) (partitionRangeOR, error) {
 	result := fullRange(len(pi.Definitions))
 
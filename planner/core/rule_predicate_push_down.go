@@ -15,11 +15,16 @@
 package core
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
@@ -28,16 +33,16 @@ import (
 type ppdSolver struct{}
 
 func (s *ppdSolver) optimize(ctx context.Context, lp LogicalPlan, opt *logicalOptimizeOp) (LogicalPlan, error) {
-	_, p := lp.PredicatePushDown(nil)
+	_, p := lp.PredicatePushDown(nil, opt)
 	return p, nil
 }
 
-func addSelection(p LogicalPlan, child LogicalPlan, conditions []expression.Expression, chIdx int) {
+func addSelection(p LogicalPlan, child LogicalPlan, conditions []expression.Expression, chIdx int, opt *logicalOptimizeOp) {
 	if len(conditions) == 0 {
 		p.Children()[chIdx] = child
 		return
 	}
-	conditions = expression.PropagateConstant(p.SCtx(), conditions)
+	conditions = propagateConstant(p, conditions, opt)
 	// Return table dual when filter is constant false or null.
 	dual := Conds2TableDual(child, conditions)
 	if dual != nil {
@@ -56,13 +61,13 @@ func addSelection(p LogicalPlan, child LogicalPlan, conditions []expression.Expr
 }
 
 // PredicatePushDown implements LogicalPlan interface.
-func (p *baseLogicalPlan) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
+func (p *baseLogicalPlan) PredicatePushDown(predicates []expression.Expression, opt *logicalOptimizeOp) ([]expression.Expression, LogicalPlan) {
 	if len(p.children) == 0 {
 		return predicates, p.self
 	}
 	child := p.children[0]
-	rest, newChild := child.PredicatePushDown(predicates)
-	addSelection(p.self, newChild, rest, 0)
+	rest, newChild := child.PredicatePushDown(predicates, opt)
+	addSelection(p.self, newChild, rest, 0, opt)
 	return nil, p.self
 }
 
@@ -80,21 +85,21 @@ func splitSetGetVarFunc(filters []expression.Expression) ([]expression.Expressio
 }
 
 // PredicatePushDown implements LogicalPlan PredicatePushDown interface.
-func (p *LogicalSelection) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
+func (p *LogicalSelection) PredicatePushDown(predicates []expression.Expression, opt *logicalOptimizeOp) ([]expression.Expression, LogicalPlan) {
 	predicates = DeleteTrueExprs(p, predicates)
 	p.Conditions = DeleteTrueExprs(p, p.Conditions)
 	var child LogicalPlan
 	var retConditions []expression.Expression
 	if p.buildByHaving {
-		retConditions, child = p.children[0].PredicatePushDown(predicates)
+		retConditions, child = p.children[0].PredicatePushDown(predicates, opt)
 		retConditions = append(retConditions, p.Conditions...)
 	} else {
 		canBePushDown, canNotBePushDown := splitSetGetVarFunc(p.Conditions)
-		retConditions, child = p.children[0].PredicatePushDown(append(canBePushDown, predicates...))
+		retConditions, child = p.children[0].PredicatePushDown(append(canBePushDown, predicates...), opt)
 		retConditions = append(retConditions, canNotBePushDown...)
 	}
 	if len(retConditions) > 0 {
-		p.Conditions = expression.PropagateConstant(p.ctx, retConditions)
+		p.Conditions = propagateConstant(p, retConditions, opt)
 		// Return table dual when filter is constant false or null.
 		dual := Conds2TableDual(p, p.Conditions)
 		if dual != nil {
@@ -106,8 +111,8 @@ func (p *LogicalSelection) PredicatePushDown(predicates []expression.Expression)
 }
 
 // PredicatePushDown implements LogicalPlan PredicatePushDown interface.
-func (p *LogicalUnionScan) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
-	retainedPredicates, _ := p.children[0].PredicatePushDown(predicates)
+func (p *LogicalUnionScan) PredicatePushDown(predicates []expression.Expression, opt *logicalOptimizeOp) ([]expression.Expression, LogicalPlan) {
+	retainedPredicates, _ := p.children[0].PredicatePushDown(predicates, opt)
 	p.conditions = make([]expression.Expression, 0, len(predicates))
 	p.conditions = append(p.conditions, predicates...)
 	// The conditions in UnionScan is only used for added rows, so parent Selection should not be removed.
@@ -115,22 +120,221 @@ func (p *LogicalUnionScan) PredicatePushDown(predicates []expression.Expression)
 }
 
 // PredicatePushDown implements LogicalPlan PredicatePushDown interface.
-func (ds *DataSource) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
-	predicates = expression.PropagateConstant(ds.ctx, predicates)
+func (ds *DataSource) PredicatePushDown(predicates []expression.Expression, opt *logicalOptimizeOp) ([]expression.Expression, LogicalPlan) {
+	predicates = propagateConstant(ds, predicates, opt)
 	predicates = DeleteTrueExprs(ds, predicates)
 	ds.allConds = predicates
 	ds.pushedDownConds, predicates = expression.PushDownExprs(ds.ctx.GetSessionVars().StmtCtx, predicates, ds.ctx.GetClient(), kv.UnSpecified)
+	appendDataSourcePredicatePushDownTraceSteps(ds, predicates, opt)
+	appendIndexHintTraceSteps(ds, opt)
 	return predicates, ds
 }
 
+// appendDataSourcePredicatePushDownTraceSteps records the predicates pushed down into ds, and the ones which are kept
+// in the selection above ds because the storage layer can't evaluate them.
+func appendDataSourcePredicatePushDownTraceSteps(ds *DataSource, remained []expression.Expression, opt *logicalOptimizeOp) {
+	if len(ds.pushedDownConds) > 0 {
+		conds := predicatesString(ds.pushedDownConds)
+		reason := fmt.Sprintf("the predicates%s only refer to the columns of %v_%v and can be evaluated by the storage layer", conds, ds.TP(), ds.ID())
+		action := fmt.Sprintf("the predicates%s are pushed down into %v_%v", conds, ds.TP(), ds.ID())
+		opt.appendStepToCurrent(ds.ID(), ds.TP(), reason, action)
+	}
+	if len(remained) > 0 {
+		conds := predicatesString(remained)
+		reason := fmt.Sprintf("the predicates%s can't be evaluated by the storage layer", conds)
+		action := fmt.Sprintf("the predicates%s are kept in the selection above %v_%v", conds, ds.TP(), ds.ID())
+		opt.appendStepToCurrent(ds.ID(), ds.TP(), reason, action)
+	}
+}
+
+// propagateConstant propagates the constants in the conditions of p, and records the conditions rewritten by it.
+func propagateConstant(p LogicalPlan, conds []expression.Expression, opt *logicalOptimizeOp) []expression.Expression {
+	newConds := expression.PropagateConstant(p.SCtx(), conds)
+	appendConstantPropagationTraceStep(p, conds, newConds, opt)
+	return newConds
+}
+
+// appendConstantPropagationTraceStep records the conditions of p rewritten by the constant propagation, and the
+// ones folded into constants. Nothing is recorded if the conditions are unchanged.
+func appendConstantPropagationTraceStep(p LogicalPlan, conds, newConds []expression.Expression, opt *logicalOptimizeOp) {
+	if opt == nil || opt.tracer == nil {
+		return
+	}
+	newCondsStr := sortedPredicatesString(newConds)
+	if sortedPredicatesString(conds) == newCondsStr {
+		return
+	}
+	// The conditions are rewritten by the propagation only if they differ from the ones folded separately.
+	foldedConds := make([]expression.Expression, 0, len(conds))
+	for _, cond := range conds {
+		for _, item := range expression.SplitCNFItems(cond) {
+			foldedConds = append(foldedConds, expression.FoldConstant(item))
+		}
+	}
+	propagated := sortedPredicatesString(expression.RemoveDupExprs(p.SCtx(), foldedConds)) != newCondsStr
+	constants := make([]expression.Expression, 0, len(newConds))
+	for _, cond := range newConds {
+		if _, ok := cond.(*expression.Constant); ok {
+			constants = append(constants, cond)
+		}
+	}
+	if !propagated && len(constants) == 0 {
+		return
+	}
+	var reason, action string
+	if propagated {
+		reason = fmt.Sprintf("the conditions%s of %v_%v contain equalities to constants or columns, so the constants can be propagated into the other conditions",
+			predicatesString(conds), p.TP(), p.ID())
+		switch len(constants) {
+		case 0:
+			action = fmt.Sprintf("the conditions%s are rewritten to %s", predicatesString(conds), predicatesString(newConds))
+		case len(newConds):
+			action = fmt.Sprintf("the conditions%s are constant after the propagation, so they are folded into %s", predicatesString(conds), predicatesString(newConds))
+		default:
+			action = fmt.Sprintf("the conditions%s are rewritten to %s, and the conditions which are constant after the propagation are folded into %s",
+				predicatesString(conds), predicatesString(newConds), predicatesString(constants))
+		}
+	} else {
+		reason = fmt.Sprintf("some of the conditions%s of %v_%v only depend on constants", predicatesString(conds), p.TP(), p.ID())
+		action = fmt.Sprintf("the conditions%s are folded into %s", predicatesString(conds), predicatesString(newConds))
+	}
+	opt.appendStepToSubRule(constantPropagationRuleName, p.ID(), p.TP(), reason, action)
+}
+
+// sortedPredicatesString is like predicatesString, but ignores the order of the predicates.
+func sortedPredicatesString(conds []expression.Expression) string {
+	strs := make([]string, 0, len(conds))
+	for _, cond := range conds {
+		strs = append(strs, cond.String())
+	}
+	sort.Strings(strs)
+	return "[" + strings.Join(strs, ",") + "]"
+}
+
+// predicatesString joins the predicates into a string like "[gt(test.t.a, 1),lt(test.t.b, 2)]" for the trace steps.
+func predicatesString(conds []expression.Expression) string {
+	buffer := bytes.NewBufferString("[")
+	for i, cond := range conds {
+		if i > 0 {
+			buffer.WriteString(",")
+		}
+		buffer.WriteString(cond.String())
+	}
+	buffer.WriteString("]")
+	return buffer.String()
+}
+
+// appendIndexHintTraceSteps records whether each index in the index hints of ds is honored or ignored, and why.
+func appendIndexHintTraceSteps(ds *DataSource, opt *logicalOptimizeOp) {
+	if opt.tracer == nil {
+		return
+	}
+	tblName := ds.tableInfo.Name
+	if ds.TableAsName != nil && ds.TableAsName.L != "" {
+		tblName = *ds.TableAsName
+	}
+	for _, hint := range ds.astIndexHints {
+		if hint.HintScope != ast.HintForScan {
+			continue
+		}
+		var hintType string
+		switch hint.HintType {
+		case ast.HintUse:
+			hintType = "USE INDEX"
+		case ast.HintIgnore:
+			hintType = "IGNORE INDEX"
+		case ast.HintForce:
+			hintType = "FORCE INDEX"
+		}
+		if hint.IndexNames == nil {
+			appendIndexHintTraceStep(ds, fmt.Sprintf("%s()", hintType), tblName, hint.HintType, nil, opt)
+		}
+		for _, idxName := range hint.IndexNames {
+			appendIndexHintTraceStep(ds, fmt.Sprintf("%s(%s)", hintType, idxName.O), tblName, hint.HintType, &idxName, opt)
+		}
+	}
+	for i := range ds.IndexHints {
+		hint := &ds.IndexHints[i]
+		if hint.dbName.L != ds.DBName.L || hint.tblName.L != tblName.L {
+			continue
+		}
+		for _, idxName := range hint.indexHint.IndexNames {
+			hintStr := fmt.Sprintf("%s(%s.%s, %s)", hint.hintTypeString(), hint.dbName, hint.tblName, idxName.O)
+			appendIndexHintTraceStep(ds, hintStr, tblName, hint.indexHint.HintType, &idxName, opt)
+		}
+	}
+}
+
+// appendIndexHintTraceStep records whether the index idxName in the index hint hintStr is honored by ds.
+// An index hint without index names means that no index should be used.
+func appendIndexHintTraceStep(ds *DataSource, hintStr string, tblName model.CIStr, hintType ast.IndexHintType, idxName *model.CIStr, opt *logicalOptimizeOp) {
+	honored := true
+	var reason string
+	_, isolationReadEnginesHasTiKV := ds.ctx.GetSessionVars().GetIsolationReadEngines()[kv.TiKV]
+	switch {
+	case !isolationReadEnginesHasTiKV:
+		honored = false
+		reason = "TiKV isn't in the isolation read engines, so no index can be used"
+	case idxName == nil:
+		reason = "no index is specified, so only the table can be scanned"
+	default:
+		idxInfo := ds.tableInfo.FindIndexByName(idxName.L)
+		path := getPathByIndexName(ds.possibleAccessPaths, *idxName, ds.tableInfo)
+		exists := idxInfo != nil || (isPrimaryIndex(*idxName) && ds.tableInfo.PKIsHandle)
+		switch {
+		case !exists || (idxInfo != nil && idxInfo.State != model.StatePublic):
+			honored = false
+			reason = fmt.Sprintf("the index[%s] doesn't exist in table[%s]", idxName.O, tblName.O)
+		case idxInfo != nil && idxInfo.Invisible && !ds.ctx.GetSessionVars().OptimizerUseInvisibleIndexes:
+			honored = false
+			reason = fmt.Sprintf("the index[%s] is invisible to the optimizer", idxName.O)
+		case hintType == ast.HintIgnore:
+			reason = fmt.Sprintf("the index[%s] is removed from the access paths", idxName.O)
+		case path == nil:
+			honored = false
+			reason = fmt.Sprintf("the index[%s] is also ignored by an index hint", idxName.O)
+		default:
+			firstCol := ds.tableInfo.GetPkColInfo()
+			if idxInfo != nil {
+				firstCol = ds.tableInfo.Columns[idxInfo.Columns[0].Offset]
+			}
+			if indexColumnIsFiltered(ds, firstCol) {
+				reason = fmt.Sprintf("the index[%s] is available and the pushed down predicates refer to its first column[%s]",
+					idxName.O, firstCol.Name.O)
+			} else {
+				reason = fmt.Sprintf("the index[%s] is available, but no pushed down predicate refers to its first column[%s], so it can only be scanned fully",
+					idxName.O, firstCol.Name.O)
+			}
+		}
+	}
+	action := fmt.Sprintf("the index hint[%s] on table[%s] is honored", hintStr, tblName.O)
+	if !honored {
+		action = fmt.Sprintf("the index hint[%s] on table[%s] is ignored", hintStr, tblName.O)
+	}
+	opt.appendStepToCurrent(ds.ID(), ds.TP(), reason, action)
+}
+
+// indexColumnIsFiltered checks whether the pushed down conditions of ds refer to the column col.
+func indexColumnIsFiltered(ds *DataSource, col *model.ColumnInfo) bool {
+	if col == nil {
+		return false
+	}
+	for _, c := range expression.ExtractColumnsFromExpressions(nil, ds.pushedDownConds, nil) {
+		if c.ID == col.ID {
+			return true
+		}
+	}
+	return false
+}
+
 // PredicatePushDown implements LogicalPlan PredicatePushDown interface.
-func (p *LogicalTableDual) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
+func (p *LogicalTableDual) PredicatePushDown(predicates []expression.Expression, opt *logicalOptimizeOp) ([]expression.Expression, LogicalPlan) {
 	return predicates, p
 }
 
 // PredicatePushDown implements LogicalPlan PredicatePushDown interface.
-func (p *LogicalJoin) PredicatePushDown(predicates []expression.Expression) (ret []expression.Expression, retPlan LogicalPlan) {
-	simplifyOuterJoin(p, predicates)
+func (p *LogicalJoin) PredicatePushDown(predicates []expression.Expression, opt *logicalOptimizeOp) (ret []expression.Expression, retPlan LogicalPlan) {
+	simplifyOuterJoin(p, predicates, opt)
 	var equalCond []*expression.ScalarFunction
 	var leftPushCond, rightPushCond, otherCond, leftCond, rightCond []expression.Expression
 	switch p.JoinType {
@@ -152,6 +356,7 @@ func (p *LogicalJoin) PredicatePushDown(predicates []expression.Expression) (ret
 		p.RightConditions = nil
 		ret = append(expression.ScalarFuncs2Exprs(equalCond), otherCond...)
 		ret = append(ret, rightPushCond...)
+		appendOuterJoinCondKeptTraceStep(p, ret, opt)
 	case RightOuterJoin:
 		predicates = p.outerJoinPropConst(predicates)
 		dual := Conds2TableDual(p, predicates)
@@ -170,6 +375,7 @@ func (p *LogicalJoin) PredicatePushDown(predicates []expression.Expression) (ret
 		p.LeftConditions = nil
 		ret = append(expression.ScalarFuncs2Exprs(equalCond), otherCond...)
 		ret = append(ret, leftPushCond...)
+		appendOuterJoinCondKeptTraceStep(p, ret, opt)
 	case SemiJoin, InnerJoin:
 		tempCond := make([]expression.Expression, 0, len(p.LeftConditions)+len(p.RightConditions)+len(p.EqualConditions)+len(p.OtherConditions)+len(predicates))
 		tempCond = append(tempCond, p.LeftConditions...)
@@ -178,7 +384,7 @@ func (p *LogicalJoin) PredicatePushDown(predicates []expression.Expression) (ret
 		tempCond = append(tempCond, p.OtherConditions...)
 		tempCond = append(tempCond, predicates...)
 		tempCond = expression.ExtractFiltersFromDNFs(p.ctx, tempCond)
-		tempCond = expression.PropagateConstant(p.ctx, tempCond)
+		tempCond = propagateConstant(p, tempCond, opt)
 		// Return table dual when filter is constant false or null.
 		dual := Conds2TableDual(p, tempCond)
 		if dual != nil {
@@ -192,7 +398,7 @@ func (p *LogicalJoin) PredicatePushDown(predicates []expression.Expression) (ret
 		leftCond = leftPushCond
 		rightCond = rightPushCond
 	case AntiSemiJoin:
-		predicates = expression.PropagateConstant(p.ctx, predicates)
+		predicates = propagateConstant(p, predicates, opt)
 		// Return table dual when filter is constant false or null.
 		dual := Conds2TableDual(p, predicates)
 		if dual != nil {
@@ -212,10 +418,10 @@ func (p *LogicalJoin) PredicatePushDown(predicates []expression.Expression) (ret
 	}
 	leftCond = expression.RemoveDupExprs(p.ctx, leftCond)
 	rightCond = expression.RemoveDupExprs(p.ctx, rightCond)
-	leftRet, lCh := p.children[0].PredicatePushDown(leftCond)
-	rightRet, rCh := p.children[1].PredicatePushDown(rightCond)
-	addSelection(p, lCh, leftRet, 0)
-	addSelection(p, rCh, rightRet, 1)
+	leftRet, lCh := p.children[0].PredicatePushDown(leftCond, opt)
+	rightRet, rCh := p.children[1].PredicatePushDown(rightCond, opt)
+	addSelection(p, lCh, leftRet, 0, opt)
+	addSelection(p, rCh, rightRet, 1, opt)
 	p.updateEQCond()
 	buildKeyInfo(p)
 	return ret, p.self
@@ -316,7 +522,7 @@ func (p *LogicalJoin) getProj(idx int) *LogicalProjection {
 }
 
 // simplifyOuterJoin transforms "LeftOuterJoin/RightOuterJoin" to "InnerJoin" if possible.
-func simplifyOuterJoin(p *LogicalJoin, predicates []expression.Expression) {
+func simplifyOuterJoin(p *LogicalJoin, predicates []expression.Expression, opt *logicalOptimizeOp) {
 	if p.JoinType != LeftOuterJoin && p.JoinType != RightOuterJoin && p.JoinType != InnerJoin {
 		return
 	}
@@ -329,17 +535,17 @@ func simplifyOuterJoin(p *LogicalJoin, predicates []expression.Expression) {
 
 	// first simplify embedded outer join.
 	if innerPlan, ok := innerTable.(*LogicalJoin); ok {
-		simplifyOuterJoin(innerPlan, predicates)
+		simplifyOuterJoin(innerPlan, predicates, opt)
 	}
 	if outerPlan, ok := outerTable.(*LogicalJoin); ok {
-		simplifyOuterJoin(outerPlan, predicates)
+		simplifyOuterJoin(outerPlan, predicates, opt)
 	}
 
 	if p.JoinType == InnerJoin {
 		return
 	}
 	// then simplify embedding outer join.
-	canBeSimplified := false
+	var nullRejectedCond expression.Expression
 	for _, expr := range predicates {
 		// avoid the case where the expr only refers to the schema of outerTable
 		if expression.ExprFromSchema(expr, outerTable.Schema()) {
@@ -347,15 +553,45 @@ func simplifyOuterJoin(p *LogicalJoin, predicates []expression.Expression) {
 		}
 		isOk := isNullRejected(p.ctx, innerTable.Schema(), expr)
 		if isOk {
-			canBeSimplified = true
+			nullRejectedCond = expr
 			break
 		}
 	}
-	if canBeSimplified {
+	if nullRejectedCond != nil {
+		appendOuterJoinSimplifiedTraceStep(p, nullRejectedCond, opt)
 		p.JoinType = InnerJoin
 	}
 }
 
+func appendOuterJoinSimplifiedTraceStep(p *LogicalJoin, cond expression.Expression, opt *logicalOptimizeOp) {
+	reason := fmt.Sprintf("the where condition[%s] is null-rejecting on the inner side of %v_%v, so the null-extended rows are filtered out anyway",
+		cond.String(), p.TP(), p.ID())
+	action := fmt.Sprintf("%v_%v is converted from %s to inner join, and the where conditions can be moved into its on clause",
+		p.TP(), p.ID(), p.JoinType.String())
+	opt.appendStepToCurrent(p.ID(), p.TP(), reason, action)
+}
+
+// appendOuterJoinCondKeptTraceStep records the where conditions which refer to the inner side of the outer join p
+// and are kept above p.
+func appendOuterJoinCondKeptTraceStep(p *LogicalJoin, conds []expression.Expression, opt *logicalOptimizeOp) {
+	if len(conds) == 0 || (p.JoinType != LeftOuterJoin && p.JoinType != RightOuterJoin) {
+		return
+	}
+	buffer := bytes.NewBufferString("[")
+	for i, cond := range conds {
+		if i > 0 {
+			buffer.WriteString(",")
+		}
+		buffer.WriteString(cond.String())
+	}
+	buffer.WriteString("]")
+	reason := fmt.Sprintf("the where conditions%s refer to the inner side of %v_%v and are not null-rejecting, so they must be evaluated on the null-extended rows",
+		buffer.String(), p.TP(), p.ID())
+	action := fmt.Sprintf("the where conditions%s are kept above %v_%v instead of being moved into its on clause",
+		buffer.String(), p.TP(), p.ID())
+	opt.appendStepToCurrent(p.ID(), p.TP(), reason, action)
+}
+
 // isNullRejected check whether a condition is null-rejected
 // A condition would be null-rejected in one of following cases:
 // If it is a predicate containing a reference to an inner table that evaluates to UNKNOWN or FALSE when one of its arguments is NULL.
@@ -380,12 +616,12 @@ func isNullRejected(ctx sessionctx.Context, schema *expression.Schema, expr expr
 }
 
 // PredicatePushDown implements LogicalPlan PredicatePushDown interface.
-func (p *LogicalProjection) PredicatePushDown(predicates []expression.Expression) (ret []expression.Expression, retPlan LogicalPlan) {
+func (p *LogicalProjection) PredicatePushDown(predicates []expression.Expression, opt *logicalOptimizeOp) (ret []expression.Expression, retPlan LogicalPlan) {
 	canBePushed := make([]expression.Expression, 0, len(predicates))
 	canNotBePushed := make([]expression.Expression, 0, len(predicates))
 	for _, expr := range p.Exprs {
 		if expression.HasAssignSetVarFunc(expr) {
-			_, child := p.baseLogicalPlan.PredicatePushDown(nil)
+			_, child := p.baseLogicalPlan.PredicatePushDown(nil, opt)
 			return predicates, child
 		}
 	}
@@ -397,24 +633,24 @@ func (p *LogicalProjection) PredicatePushDown(predicates []expression.Expression
 			canNotBePushed = append(canNotBePushed, cond)
 		}
 	}
-	remained, child := p.baseLogicalPlan.PredicatePushDown(canBePushed)
+	remained, child := p.baseLogicalPlan.PredicatePushDown(canBePushed, opt)
 	return append(remained, canNotBePushed...), child
 }
 
 // PredicatePushDown implements LogicalPlan PredicatePushDown interface.
-func (p *LogicalUnionAll) PredicatePushDown(predicates []expression.Expression) (ret []expression.Expression, retPlan LogicalPlan) {
+func (p *LogicalUnionAll) PredicatePushDown(predicates []expression.Expression, opt *logicalOptimizeOp) (ret []expression.Expression, retPlan LogicalPlan) {
 	for i, proj := range p.children {
 		newExprs := make([]expression.Expression, 0, len(predicates))
 		newExprs = append(newExprs, predicates...)
-		retCond, newChild := proj.PredicatePushDown(newExprs)
-		addSelection(p, newChild, retCond, i)
+		retCond, newChild := proj.PredicatePushDown(newExprs, opt)
+		addSelection(p, newChild, retCond, i, opt)
 	}
 	return nil, p
 }
 
 // PredicatePushDown implements LogicalPlan PredicatePushDown interface.
-func (la *LogicalAggregation) PredicatePushDown(predicates []expression.Expression) (ret []expression.Expression, retPlan LogicalPlan) {
-	var condsToPush []expression.Expression
+func (la *LogicalAggregation) PredicatePushDown(predicates []expression.Expression, opt *logicalOptimizeOp) (ret []expression.Expression, retPlan LogicalPlan) {
+	var condsToPush, groupByConds []expression.Expression
 	exprsOriginal := make([]expression.Expression, 0, len(la.AggFuncs))
 	for _, fun := range la.AggFuncs {
 		exprsOriginal = append(exprsOriginal, fun.Args[0])
@@ -440,6 +676,7 @@ func (la *LogicalAggregation) PredicatePushDown(predicates []expression.Expressi
 			if ok {
 				newFunc := expression.ColumnSubstitute(cond, la.Schema(), exprsOriginal)
 				condsToPush = append(condsToPush, newFunc)
+				groupByConds = append(groupByConds, cond)
 			} else {
 				ret = append(ret, cond)
 			}
@@ -447,21 +684,33 @@ func (la *LogicalAggregation) PredicatePushDown(predicates []expression.Expressi
 			ret = append(ret, cond)
 		}
 	}
-	la.baseLogicalPlan.PredicatePushDown(condsToPush)
+	appendAggPredicatePushDownTraceStep(la, groupByConds, opt)
+	la.baseLogicalPlan.PredicatePushDown(condsToPush, opt)
 	return ret, la
 }
 
+// appendAggPredicatePushDownTraceStep records the predicates on the group by columns which are pushed down below la.
+func appendAggPredicatePushDownTraceStep(la *LogicalAggregation, conds []expression.Expression, opt *logicalOptimizeOp) {
+	if len(conds) == 0 {
+		return
+	}
+	reason := fmt.Sprintf("the predicates%s only refer to the group by columns of %v_%v, so filtering the input rows removes the same groups",
+		predicatesString(conds), la.TP(), la.ID())
+	action := fmt.Sprintf("the predicates%s are pushed down below %v_%v", predicatesString(conds), la.TP(), la.ID())
+	opt.appendStepToCurrent(la.ID(), la.TP(), reason, action)
+}
+
 // PredicatePushDown implements LogicalPlan PredicatePushDown interface.
-func (p *LogicalLimit) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
+func (p *LogicalLimit) PredicatePushDown(predicates []expression.Expression, opt *logicalOptimizeOp) ([]expression.Expression, LogicalPlan) {
 	// Limit forbids any condition to push down.
-	p.baseLogicalPlan.PredicatePushDown(nil)
+	p.baseLogicalPlan.PredicatePushDown(nil, opt)
 	return predicates, p
 }
 
 // PredicatePushDown implements LogicalPlan PredicatePushDown interface.
-func (p *LogicalMaxOneRow) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
+func (p *LogicalMaxOneRow) PredicatePushDown(predicates []expression.Expression, opt *logicalOptimizeOp) ([]expression.Expression, LogicalPlan) {
 	// MaxOneRow forbids any condition to push down.
-	p.baseLogicalPlan.PredicatePushDown(nil)
+	p.baseLogicalPlan.PredicatePushDown(nil, opt)
 	return predicates, p
 }
 
@@ -610,7 +859,7 @@ func (p *LogicalWindow) GetPartitionByCols() []*expression.Column {
 }
 
 // PredicatePushDown implements LogicalPlan PredicatePushDown interface.
-func (p *LogicalWindow) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
+func (p *LogicalWindow) PredicatePushDown(predicates []expression.Expression, opt *logicalOptimizeOp) ([]expression.Expression, LogicalPlan) {
 	canBePushed := make([]expression.Expression, 0, len(predicates))
 	canNotBePushed := make([]expression.Expression, 0, len(predicates))
 	partitionCols := expression.NewSchema(p.GetPartitionByCols()...)
@@ -623,18 +872,22 @@ func (p *LogicalWindow) PredicatePushDown(predicates []expression.Expression) ([
 			canNotBePushed = append(canNotBePushed, cond)
 		}
 	}
-	p.baseLogicalPlan.PredicatePushDown(canBePushed)
+	p.baseLogicalPlan.PredicatePushDown(canBePushed, opt)
 	return canNotBePushed, p
 }
 
 // PredicatePushDown implements LogicalPlan PredicatePushDown interface.
-func (p *LogicalMemTable) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
+func (p *LogicalMemTable) PredicatePushDown(predicates []expression.Expression, opt *logicalOptimizeOp) ([]expression.Expression, LogicalPlan) {
 	if p.Extractor != nil {
 		predicates = p.Extractor.Extract(p.ctx, p.schema, p.names, predicates)
 	}
 	return predicates, p.self
 }
 
+// constantPropagationRuleName is the rule name of the trace steps of the constant propagation, which is applied
+// by the predicate push down.
+const constantPropagationRuleName = "constant_propagation"
+
 func (*ppdSolver) name() string {
 	return "predicate_push_down"
 }
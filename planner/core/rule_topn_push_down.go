@@ -15,7 +15,9 @@
 package core
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 
 	"github.com/cznic/mathutil"
 	"github.com/pingcap/tidb/expression"
@@ -27,13 +29,13 @@ type pushDownTopNOptimizer struct {
 }
 
 func (s *pushDownTopNOptimizer) optimize(ctx context.Context, p LogicalPlan, opt *logicalOptimizeOp) (LogicalPlan, error) {
-	return p.pushDownTopN(nil), nil
+	return p.pushDownTopN(nil, opt), nil
 }
 
-func (s *baseLogicalPlan) pushDownTopN(topN *LogicalTopN) LogicalPlan {
+func (s *baseLogicalPlan) pushDownTopN(topN *LogicalTopN, opt *logicalOptimizeOp) LogicalPlan {
 	p := s.self
 	for i, child := range p.Children() {
-		p.Children()[i] = child.pushDownTopN(nil)
+		p.Children()[i] = child.pushDownTopN(nil, opt)
 	}
 	if topN != nil {
 		return topN.setChild(p)
@@ -69,30 +71,40 @@ func (lt *LogicalTopN) setChild(p LogicalPlan) LogicalPlan {
 	return lt
 }
 
-func (ls *LogicalSort) pushDownTopN(topN *LogicalTopN) LogicalPlan {
+func (ls *LogicalSort) pushDownTopN(topN *LogicalTopN, opt *logicalOptimizeOp) LogicalPlan {
 	if topN == nil {
-		return ls.baseLogicalPlan.pushDownTopN(nil)
+		return ls.baseLogicalPlan.pushDownTopN(nil, opt)
 	} else if topN.isLimit() {
 		topN.ByItems = ls.ByItems
-		return ls.children[0].pushDownTopN(topN)
+		appendSortCombinedIntoTopNTraceStep(ls, topN, opt)
+		return ls.children[0].pushDownTopN(topN, opt)
 	}
 	// If a TopN is pushed down, this sort is useless.
-	return ls.children[0].pushDownTopN(topN)
+	appendSortEliminatedByTopNTraceStep(ls, topN, opt)
+	return ls.children[0].pushDownTopN(topN, opt)
 }
 
 func (p *LogicalLimit) convertToTopN() *LogicalTopN {
 	return LogicalTopN{Offset: p.Offset, Count: p.Count, limitHints: p.limitHints}.Init(p.ctx, p.blockOffset)
 }
 
-func (p *LogicalLimit) pushDownTopN(topN *LogicalTopN) LogicalPlan {
-	child := p.children[0].pushDownTopN(p.convertToTopN())
+func (p *LogicalLimit) pushDownTopN(topN *LogicalTopN, opt *logicalOptimizeOp) LogicalPlan {
+	if p.calcFoundRows {
+		// The rows found without the limit are counted by the limit itself, so keep it where it is.
+		p.children[0] = p.children[0].pushDownTopN(nil, opt)
+		if topN != nil {
+			return topN.setChild(p)
+		}
+		return p
+	}
+	child := p.children[0].pushDownTopN(p.convertToTopN(), opt)
 	if topN != nil {
 		return topN.setChild(child)
 	}
 	return child
 }
 
-func (p *LogicalUnionAll) pushDownTopN(topN *LogicalTopN) LogicalPlan {
+func (p *LogicalUnionAll) pushDownTopN(topN *LogicalTopN, opt *logicalOptimizeOp) LogicalPlan {
 	for i, child := range p.children {
 		var newTopN *LogicalTopN
 		if topN != nil {
@@ -100,8 +112,9 @@ func (p *LogicalUnionAll) pushDownTopN(topN *LogicalTopN) LogicalPlan {
 			for _, by := range topN.ByItems {
 				newTopN.ByItems = append(newTopN.ByItems, &util.ByItems{Expr: by.Expr, Desc: by.Desc})
 			}
+			appendTopNPushDownUnionAllTraceStep(p, topN, newTopN, i, opt)
 		}
-		p.children[i] = child.pushDownTopN(newTopN)
+		p.children[i] = child.pushDownTopN(newTopN, opt)
 	}
 	if topN != nil {
 		return topN.setChild(p)
@@ -109,10 +122,10 @@ func (p *LogicalUnionAll) pushDownTopN(topN *LogicalTopN) LogicalPlan {
 	return p
 }
 
-func (p *LogicalProjection) pushDownTopN(topN *LogicalTopN) LogicalPlan {
+func (p *LogicalProjection) pushDownTopN(topN *LogicalTopN, opt *logicalOptimizeOp) LogicalPlan {
 	for _, expr := range p.Exprs {
 		if expression.HasAssignSetVarFunc(expr) {
-			return p.baseLogicalPlan.pushDownTopN(topN)
+			return p.baseLogicalPlan.pushDownTopN(topN, opt)
 		}
 	}
 	if topN != nil {
@@ -127,29 +140,30 @@ func (p *LogicalProjection) pushDownTopN(topN *LogicalTopN) LogicalPlan {
 				topN.ByItems = append(topN.ByItems[:i], topN.ByItems[i+1:]...)
 			}
 		}
+		appendTopNPushDownProjectionTraceStep(p, topN, opt)
 	}
-	p.children[0] = p.children[0].pushDownTopN(topN)
+	p.children[0] = p.children[0].pushDownTopN(topN, opt)
 	return p
 }
 
-func (p *LogicalLock) pushDownTopN(topN *LogicalTopN) LogicalPlan {
+func (p *LogicalLock) pushDownTopN(topN *LogicalTopN, opt *logicalOptimizeOp) LogicalPlan {
 	if topN != nil {
-		p.children[0] = p.children[0].pushDownTopN(topN)
+		p.children[0] = p.children[0].pushDownTopN(topN, opt)
 	}
 	return p.self
 }
 
 // pushDownTopNToChild will push a topN to one child of join. The idx stands for join child index. 0 is for left child.
-func (p *LogicalJoin) pushDownTopNToChild(topN *LogicalTopN, idx int) LogicalPlan {
+func (p *LogicalJoin) pushDownTopNToChild(topN *LogicalTopN, idx int, opt *logicalOptimizeOp) LogicalPlan {
 	if topN == nil {
-		return p.children[idx].pushDownTopN(nil)
+		return p.children[idx].pushDownTopN(nil, opt)
 	}
 
 	for _, by := range topN.ByItems {
 		cols := expression.ExtractColumns(by.Expr)
 		for _, col := range cols {
 			if !p.children[idx].Schema().Contains(col) {
-				return p.children[idx].pushDownTopN(nil)
+				return p.children[idx].pushDownTopN(nil, opt)
 			}
 		}
 	}
@@ -162,19 +176,20 @@ func (p *LogicalJoin) pushDownTopNToChild(topN *LogicalTopN, idx int) LogicalPla
 	for i := range topN.ByItems {
 		newTopN.ByItems[i] = topN.ByItems[i].Clone()
 	}
-	return p.children[idx].pushDownTopN(newTopN)
+	appendTopNPushDownJoinTraceStep(p, topN, newTopN, idx, opt)
+	return p.children[idx].pushDownTopN(newTopN, opt)
 }
 
-func (p *LogicalJoin) pushDownTopN(topN *LogicalTopN) LogicalPlan {
+func (p *LogicalJoin) pushDownTopN(topN *LogicalTopN, opt *logicalOptimizeOp) LogicalPlan {
 	switch p.JoinType {
 	case LeftOuterJoin, LeftOuterSemiJoin, AntiLeftOuterSemiJoin:
-		p.children[0] = p.pushDownTopNToChild(topN, 0)
-		p.children[1] = p.children[1].pushDownTopN(nil)
+		p.children[0] = p.pushDownTopNToChild(topN, 0, opt)
+		p.children[1] = p.children[1].pushDownTopN(nil, opt)
 	case RightOuterJoin:
-		p.children[1] = p.pushDownTopNToChild(topN, 1)
-		p.children[0] = p.children[0].pushDownTopN(nil)
+		p.children[1] = p.pushDownTopNToChild(topN, 1, opt)
+		p.children[0] = p.children[0].pushDownTopN(nil, opt)
 	default:
-		return p.baseLogicalPlan.pushDownTopN(topN)
+		return p.baseLogicalPlan.pushDownTopN(topN, opt)
 	}
 
 	// The LogicalJoin may be also a LogicalApply. So we must use self to set parents.
@@ -187,3 +202,79 @@ func (p *LogicalJoin) pushDownTopN(topN *LogicalTopN) LogicalPlan {
 func (*pushDownTopNOptimizer) name() string {
 	return "topn_push_down"
 }
+
+// appendSortCombinedIntoTopNTraceStep records that ls is combined with the limit above it into a TopN.
+func appendSortCombinedIntoTopNTraceStep(ls *LogicalSort, topN *LogicalTopN, opt *logicalOptimizeOp) {
+	reason := fmt.Sprintf("%v_%v is directly below a limit, so the limit and the sort can be computed by a single topN", ls.TP(), ls.ID())
+	action := fmt.Sprintf("the limit and %v_%v are combined into %v_%v with the ByItems%s", ls.TP(), ls.ID(), topN.TP(), topN.ID(), byItemsString(topN.ByItems))
+	opt.appendStepToCurrent(ls.ID(), ls.TP(), reason, action)
+}
+
+// appendSortEliminatedByTopNTraceStep records that ls is removed because the TopN above it decides the order itself.
+func appendSortEliminatedByTopNTraceStep(ls *LogicalSort, topN *LogicalTopN, opt *logicalOptimizeOp) {
+	reason := fmt.Sprintf("%v_%v above %v_%v sorts the rows by its own ByItems%s", topN.TP(), topN.ID(), ls.TP(), ls.ID(), byItemsString(topN.ByItems))
+	action := fmt.Sprintf("%v_%v is removed", ls.TP(), ls.ID())
+	opt.appendStepToCurrent(ls.ID(), ls.TP(), reason, action)
+}
+
+func appendTopNPushDownProjectionTraceStep(proj *LogicalProjection, topN *LogicalTopN, opt *logicalOptimizeOp) {
+	reason := fmt.Sprintf("%v_%v doesn't change the number of rows, and the ByItems of %v_%v can be substituted by its expressions", proj.TP(), proj.ID(), topN.TP(), topN.ID())
+	if len(topN.ByItems) == 0 {
+		reason = fmt.Sprintf("%v_%v doesn't change the number of rows, and the limit %v_%v has no ByItems to be substituted by its expressions", proj.TP(), proj.ID(), topN.TP(), topN.ID())
+	}
+	action := fmt.Sprintf("%v_%v is pushed down below %v_%v", topN.TP(), topN.ID(), proj.TP(), proj.ID())
+	opt.appendStepToCurrent(topN.ID(), topN.TP(), reason, action)
+}
+
+func appendTopNPushDownJoinTraceStep(join *LogicalJoin, topN, newTopN *LogicalTopN, idx int, opt *logicalOptimizeOp) {
+	side := "left"
+	if idx == 1 {
+		side = "right"
+	}
+	reason := fmt.Sprintf("the ByItems of %v_%v only refer to the columns of the outer side of %v_%v, and every outer row is kept by the join",
+		topN.TP(), topN.ID(), join.TP(), join.ID())
+	if len(topN.ByItems) == 0 {
+		reason = fmt.Sprintf("the limit %v_%v has no ByItems, and every outer row of %v_%v is kept by the join", topN.TP(), topN.ID(), join.TP(), join.ID())
+	}
+	action := fmt.Sprintf("%v_%v is added below %v_%v as the parent of its %s child %v_%v",
+		newTopN.TP(), newTopN.ID(), join.TP(), join.ID(), side, join.children[idx].TP(), join.children[idx].ID())
+	opt.appendStepToCurrent(newTopN.ID(), newTopN.TP(), reason, action)
+}
+
+// limitPushDownRuleName is the rule name of the trace steps of pushing down a limit into the children of a union,
+// which is applied by the topn_push_down rule since the limit is converted to a TopN without ByItems.
+const limitPushDownRuleName = "limit_push_down"
+
+// appendTopNPushDownUnionAllTraceStep records that newTopN is pushed down to the idx-th child of union.
+// The push down is only valid for UNION ALL, the aggregation which removes the duplicated rows of UNION DISTINCT
+// sits above the union and stops the TopN. The push down of a limit is recorded under limit_push_down.
+func appendTopNPushDownUnionAllTraceStep(union *LogicalUnionAll, topN, newTopN *LogicalTopN, idx int, opt *logicalOptimizeOp) {
+	child := union.children[idx]
+	if topN.isLimit() {
+		reason := fmt.Sprintf("%v_%v is a UNION ALL which keeps the duplicated rows, so each child only needs to return its first %v rows, "+
+			"which doesn't hold for a UNION DISTINCT since the duplicated rows are removed",
+			union.TP(), union.ID(), newTopN.Count)
+		action := fmt.Sprintf("the limit %v is duplicated as %v_%v below %v_%v as the parent of its child %v_%v",
+			newTopN.Count, newTopN.TP(), newTopN.ID(), union.TP(), union.ID(), child.TP(), child.ID())
+		opt.appendStepToSubRule(limitPushDownRuleName, newTopN.ID(), newTopN.TP(), reason, action)
+		return
+	}
+	reason := fmt.Sprintf("%v_%v is a UNION ALL which keeps the duplicated rows, so its first %v rows must come from the first %v rows of each child",
+		union.TP(), union.ID(), newTopN.Count, newTopN.Count)
+	action := fmt.Sprintf("%v_%v is added below %v_%v as the parent of its child %v_%v",
+		newTopN.TP(), newTopN.ID(), union.TP(), union.ID(), child.TP(), child.ID())
+	opt.appendStepToCurrent(newTopN.ID(), newTopN.TP(), reason, action)
+}
+
+// byItemsString joins the ByItems into a string like "[test.t.a,test.t.b true]" for the trace steps.
+func byItemsString(byItems []*util.ByItems) string {
+	buffer := bytes.NewBufferString("[")
+	for i, item := range byItems {
+		if i > 0 {
+			buffer.WriteString(",")
+		}
+		buffer.WriteString(item.String())
+	}
+	buffer.WriteString("]")
+	return buffer.String()
+}
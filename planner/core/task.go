@@ -1126,7 +1126,11 @@ func (t *rootTask) plan() PhysicalPlan {
 func (p *PhysicalLimit) attach2Task(tasks ...task) task {
 	t := tasks[0].copy()
 	sunk := false
-	if cop, ok := t.(*copTask); ok {
+	if p.CalcFoundRows {
+		// The limit needs all the rows of its child to count the found rows, so it's neither
+		// pushed down nor sunk into the IndexLookUp.
+		t = t.convertToRootTask(p.ctx)
+	} else if cop, ok := t.(*copTask); ok {
 		// For double read which requires order being kept, the limit cannot be pushed down to the table side,
 		// because handles would be reordered before being sent to table scan.
 		if (!cop.keepOrder || !cop.indexPlanFinished || cop.indexPlan == nil) && len(cop.rootTaskConds) == 0 {
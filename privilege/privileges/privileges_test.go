@@ -3019,6 +3019,7 @@ func TestIssue29823(t *testing.T) {
 	require.True(t, tk2.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "%"}, nil, nil))
 	tk2.MustExec("set role all")
 	tk2.MustQuery("select current_role()").Check(testkit.Rows("`r1`@`%`"))
+	tk2.MustQuery("select current_role_json()").Check(testkit.Rows("[\"`r1`@`%`\"]"))
 	tk2.MustQuery("select * from test.t1").Check(testkit.Rows())
 	tk2.MustQuery("show databases like 'test'").Check(testkit.Rows("test"))
 	tk2.MustQuery("show tables from test").Check(testkit.Rows("t1"))
@@ -99,6 +99,9 @@ type StatementContext struct {
 
 		affectedRows uint64
 		foundRows    uint64
+		// foundRowsWithoutLimit is the number of rows found without the limit for SQL_CALC_FOUND_ROWS.
+		foundRowsWithoutLimit uint64
+		calcFoundRows         bool
 
 		/*
 			following variables are ported from 'COPY_INFO' struct of MySQL server source,
@@ -193,10 +196,19 @@ type StatementContext struct {
 	// InVerboseExplain indicates the statement is "explain format='verbose' ...".
 	InVerboseExplain bool
 
+	// TraceID is the id of the distributed trace propagated for the statement, it's empty if the statement is not traced.
+	TraceID string
 	// EnableOptimizeTrace indicates whether enable optimizer trace by 'trace plan statement'
 	EnableOptimizeTrace bool
 	// LogicalOptimizeTrace indicates the trace for optimize
 	LogicalOptimizeTrace *tracing.LogicalOptimizeTracer
+	// PhysicalOptimizeTrace indicates the trace for physical optimize
+	PhysicalOptimizeTrace *tracing.PhysicalOptimizeTracer
+	// PlanCacheParamTrace indicates the trace for the parameters of the executed prepared statement
+	PlanCacheParamTrace *tracing.PlanCacheParamTracer
+	// ScalarSubqueryTraceSteps indicates how the scalar subqueries are evaluated, which is decided when building
+	// the plan and reported by the decorrelate rule of the logical optimize trace
+	ScalarSubqueryTraceSteps []tracing.LogicalRuleOptimizeTraceStep
 	// EnableOptimizerCETrace indicate if cardinality estimation internal process needs to be traced.
 	// CE Trace is currently a submodule of the optimizer trace and is controlled by a separated option.
 	EnableOptimizerCETrace bool
@@ -243,6 +255,8 @@ const (
 	StmtNowTsCacheKey StmtCacheKey = iota
 	// StmtSafeTSCacheKey is a variable for safeTS calculation/cache of one stmt.
 	StmtSafeTSCacheKey
+	// StmtSQLDigestTextCacheKey is a variable for the statement texts retrieved by tidb_decode_sql_digests of one stmt.
+	StmtSQLDigestTextCacheKey
 )
 
 // GetOrStoreStmtCache gets the cached value of the given key if it exists, otherwise stores the value.
@@ -377,6 +391,23 @@ func (sc *StatementContext) AddFoundRows(rows uint64) {
 	sc.mu.foundRows += rows
 }
 
+// SetFoundRowsWithoutLimit sets the number of rows found without the limit,
+// it's used by the statements which carry SQL_CALC_FOUND_ROWS.
+func (sc *StatementContext) SetFoundRowsWithoutLimit(rows uint64) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.mu.foundRowsWithoutLimit = rows
+	sc.mu.calcFoundRows = true
+}
+
+// FoundRowsWithoutLimit gets the number of rows found without the limit,
+// the second return value indicates whether it has been set.
+func (sc *StatementContext) FoundRowsWithoutLimit() (uint64, bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.mu.foundRowsWithoutLimit, sc.mu.calcFoundRows
+}
+
 // RecordRows is used to generate info message
 func (sc *StatementContext) RecordRows() uint64 {
 	sc.mu.Lock()
@@ -574,6 +605,8 @@ func (sc *StatementContext) resetMuForRetry() {
 	defer sc.mu.Unlock()
 	sc.mu.affectedRows = 0
 	sc.mu.foundRows = 0
+	sc.mu.foundRowsWithoutLimit = 0
+	sc.mu.calcFoundRows = false
 	sc.mu.records = 0
 	sc.mu.updated = 0
 	sc.mu.copied = 0
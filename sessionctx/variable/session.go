@@ -939,6 +939,9 @@ type SessionVars struct {
 	// RegardNULLAsPoint if regard NULL as Point
 	RegardNULLAsPoint bool
 
+	// BenchmarkWarnThreshold is the loop count of BENCHMARK() above which a warning is appended, 0 disables the warning.
+	BenchmarkWarnThreshold int64
+
 	// LocalTemporaryTables is *infoschema.LocalTemporaryTables, use interface to avoid circle dependency.
 	// It's nil if there is no local temporary table.
 	LocalTemporaryTables interface{}
@@ -1196,6 +1199,7 @@ func NewSessionVars() *SessionVars {
 		AllowFallbackToTiKV:         make(map[kv.StoreType]struct{}),
 		CTEMaxRecursionDepth:        DefCTEMaxRecursionDepth,
 		TMPTableSize:                DefTiDBTmpTableMaxSize,
+		BenchmarkWarnThreshold:      DefTiDBBenchmarkWarnThreshold,
 		MPPStoreLastFailTime:        make(map[string]time.Time),
 		MPPStoreFailTTL:             DefTiDBMPPStoreFailTTL,
 		EnablePlacementChecks:       DefEnablePlacementCheck,
@@ -1452,8 +1456,14 @@ func (s *SessionVars) IsAutocommit() bool {
 
 // IsIsolation if true it means the transaction is at that isolation level.
 func (s *SessionVars) IsIsolation(isolation string) bool {
+	return s.GetIsolation() == isolation
+}
+
+// GetIsolation returns the isolation level of the transaction. The level set by "set transaction isolation level ..."
+// takes effect for the next transaction only, otherwise the level of the session is used.
+func (s *SessionVars) GetIsolation() string {
 	if s.TxnCtx.Isolation != "" {
-		return s.TxnCtx.Isolation == isolation
+		return s.TxnCtx.Isolation
 	}
 	if s.txnIsolationLevelOneShot.state == oneShotUse {
 		s.TxnCtx.Isolation = s.txnIsolationLevelOneShot.value
@@ -1461,7 +1471,7 @@ func (s *SessionVars) IsIsolation(isolation string) bool {
 	if s.TxnCtx.Isolation == "" {
 		s.TxnCtx.Isolation, _ = s.GetSystemVar(TxnIsolation)
 	}
-	return s.TxnCtx.Isolation == isolation
+	return s.TxnCtx.Isolation
 }
 
 // SetTxnIsolationLevelOneShotStateForNextTxn sets the txnIsolationLevelOneShot.state for next transaction.
@@ -1579,6 +1589,13 @@ func (s *SessionVars) ClearStmtVars() {
 	s.stmtVars = make(map[string]string)
 }
 
+// SwapStmtVars replaces the temporarily system variables with vars, and returns the replaced ones.
+func (s *SessionVars) SwapStmtVars(vars map[string]string) map[string]string {
+	old := s.stmtVars
+	s.stmtVars = vars
+	return old
+}
+
 // SetSystemVar sets the value of a system variable for session scope.
 // Validation is expected to be performed before calling this function,
 // and the values should been normalized.
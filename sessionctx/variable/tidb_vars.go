@@ -603,6 +603,9 @@ const (
 
 	// TiDBTmpTableMaxSize indicates the max memory size of temporary tables.
 	TiDBTmpTableMaxSize = "tidb_tmp_table_max_size"
+
+	// TiDBBenchmarkWarnThreshold indicates the loop count of BENCHMARK() above which a warning is appended, 0 disables the warning.
+	TiDBBenchmarkWarnThreshold = "tidb_benchmark_warn_threshold"
 )
 
 // TiDB vars that have only global scope
@@ -775,6 +778,7 @@ const (
 	DefTiDBEnableOrderedResultMode        = false
 	DefTiDBEnablePseudoForOutdatedStats   = true
 	DefTiDBRegardNULLAsPoint              = true
+	DefTiDBBenchmarkWarnThreshold         = 100000000
 	DefEnablePlacementCheck               = true
 	DefTimestamp                          = "0"
 )
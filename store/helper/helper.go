@@ -95,13 +95,18 @@ func NewHelper(store Storage) *Helper {
 
 // GetMvccByEncodedKey get the MVCC value by the specific encoded key.
 func (h *Helper) GetMvccByEncodedKey(encodedKey kv.Key) (*kvrpcpb.MvccGetByKeyResponse, error) {
-	keyLocation, err := h.RegionCache.LocateKey(tikv.NewBackofferWithVars(context.Background(), 500, nil), encodedKey)
+	return h.GetMvccByEncodedKeyWithContext(context.Background(), encodedKey)
+}
+
+// GetMvccByEncodedKeyWithContext get the MVCC value by the specific encoded key, it stops retrying when ctx is done.
+func (h *Helper) GetMvccByEncodedKeyWithContext(ctx context.Context, encodedKey kv.Key) (*kvrpcpb.MvccGetByKeyResponse, error) {
+	keyLocation, err := h.RegionCache.LocateKey(tikv.NewBackofferWithVars(ctx, 500, nil), encodedKey)
 	if err != nil {
 		return nil, derr.ToTiDBErr(err)
 	}
 
 	tikvReq := tikvrpc.NewRequest(tikvrpc.CmdMvccGetByKey, &kvrpcpb.MvccGetByKeyRequest{Key: encodedKey})
-	kvResp, err := h.Store.SendReq(tikv.NewBackofferWithVars(context.Background(), 500, nil), tikvReq, keyLocation.Region, time.Minute)
+	kvResp, err := h.Store.SendReq(tikv.NewBackofferWithVars(ctx, 500, nil), tikvReq, keyLocation.Region, time.Minute)
 	if err != nil {
 		logutil.BgLogger().Info("get MVCC by encoded key failed",
 			zap.Stringer("encodeKey", encodedKey),
@@ -1611,7 +1611,80 @@ func (t *TableCommon) GetSequenceNextVal(ctx interface{}, dbName, seqName string
 	}
 	seq.mu.Lock()
 	defer seq.mu.Unlock()
+	nextVal, err = t.seekSequenceVal(ctx, dbName, seqName, 1)
+	if err != nil {
+		return 0, err
+	}
+	seq.base = nextVal
+	return nextVal, nil
+}
+
+// GetSequenceNextValN implements util.SequenceTable GetSequenceNextValN interface.
+// The n values are reserved from a single cache batch, so they are consecutive in the sequence even if the
+// sequence is shared by several TiDB instances. If the rest of the cache is not enough, a batch covering the n
+// values is allocated from storage. The block never cycles, and n must be positive.
+func (t *TableCommon) GetSequenceNextValN(ctx interface{}, dbName, seqName string, n int64) (first, last int64, err error) {
+	seq := t.sequence
+	if seq == nil {
+		// TODO: refine the error.
+		return 0, 0, errors.New("sequenceCommon is nil")
+	}
+	seq.mu.Lock()
+	defer seq.mu.Unlock()
+	first, err = t.seekSequenceVal(ctx, dbName, seqName, n)
+	if err != nil {
+		return 0, 0, err
+	}
+	last, ok := seekSequenceBlockEnd(first, seq.end, seq.meta.Increment, n)
+	if !ok {
+		// The rest of the cache is not enough, drop it and reserve the block from a new batch. Check the values
+		// after the cache first, so the cache is not dropped when the sequence would run out or cycle.
+		bound := seq.meta.MaxValue
+		if seq.meta.Increment < 0 {
+			bound = seq.meta.MinValue
+		}
+		if _, ok = seekSequenceBlockEnd(seq.end, bound, seq.meta.Increment, n+1); !ok {
+			return 0, 0, table.ErrSequenceHasRunOut.GenWithStackByArgs(dbName, seqName)
+		}
+		seq.base = seq.end
+		first, err = t.seekSequenceVal(ctx, dbName, seqName, n)
+		if err != nil {
+			return 0, 0, err
+		}
+		// The new batch may be short if the sequence is allocated by other TiDB instances at the same time.
+		if last, ok = seekSequenceBlockEnd(first, seq.end, seq.meta.Increment, n); !ok {
+			return 0, 0, table.ErrSequenceHasRunOut.GenWithStackByArgs(dbName, seqName)
+		}
+	}
+	seq.base = last
+	return first, last, nil
+}
 
+// seekSequenceBlockEnd returns the last one of the n consecutive sequence values starting from first,
+// the bool indicates whether the last value doesn't exceed the bound.
+func seekSequenceBlockEnd(first, bound, increment, n int64) (int64, bool) {
+	var distance, step uint64
+	if increment > 0 {
+		if first > bound {
+			return 0, false
+		}
+		distance, step = uint64(bound)-uint64(first), uint64(increment)
+	} else {
+		if first < bound {
+			return 0, false
+		}
+		distance, step = uint64(first)-uint64(bound), uint64(-increment)
+	}
+	if distance/step < uint64(n-1) {
+		return 0, false
+	}
+	return first + (n-1)*increment, true
+}
+
+// seekSequenceVal seeks the next value of the sequence without consuming it, the cache is updated from storage if
+// there is no valid value in it, and the new batch covers at least n values. seq.mu must be locked by the caller.
+func (t *TableCommon) seekSequenceVal(ctx interface{}, dbName, seqName string, n int64) (nextVal int64, err error) {
+	seq := t.sequence
 	err = func() error {
 		// Check if need to update the cache batch from storage.
 		// Because seq.base is not always the last allocated value (may be set by setval()).
@@ -1645,7 +1718,11 @@ func (t *TableCommon) GetSequenceNextVal(ctx interface{}, dbName, seqName string
 			return err1
 		}
 		var base, end, round int64
-		base, end, round, err1 = sequenceAlloc.AllocSeqCache()
+		if n > 1 {
+			base, end, round, err1 = sequenceAlloc.AllocSeqCacheN(n)
+		} else {
+			base, end, round, err1 = sequenceAlloc.AllocSeqCache()
+		}
 		if err1 != nil {
 			return err1
 		}
@@ -1680,7 +1757,6 @@ func (t *TableCommon) GetSequenceNextVal(ctx interface{}, dbName, seqName string
 		}
 		return 0, err
 	}
-	seq.base = nextVal
 	return nextVal, nil
 }
 
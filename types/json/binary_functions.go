@@ -1245,3 +1245,205 @@ func (bj BinaryJSON) Walk(walkFn BinaryJSONWalkFunc, pathExprList ...PathExpress
 	}
 	return nil
 }
+
+// ValidateBinarySchema for TIDB_JSON_VALID_SCHEMA
+// Checks whether the JSON document matches the JSON Schema, only a subset of the keywords defined by
+// [https://json-schema.org/draft/2020-12/json-schema-validation.html] is supported:
+// type, enum, required, properties, items, minimum, maximum, minLength, maxLength, minItems and maxItems.
+// Other keywords are ignored. An error is returned if the schema itself is malformed.
+func ValidateBinarySchema(schema, doc BinaryJSON) (bool, error) {
+	if err := checkSchema(schema); err != nil {
+		return false, err
+	}
+	return matchSchema(schema, doc), nil
+}
+
+var jsonSchemaTypes = map[string]struct{}{
+	"object":  {},
+	"array":   {},
+	"string":  {},
+	"number":  {},
+	"integer": {},
+	"boolean": {},
+	"null":    {},
+}
+
+func errInvalidJSONSchema(format string, args ...interface{}) error {
+	return ErrInvalidJSONText.GenWithStackByArgs("invalid JSON schema, " + fmt.Sprintf(format, args...))
+}
+
+// checkSchema checks the supported keywords of the schema are well-formed.
+func checkSchema(schema BinaryJSON) error {
+	if schema.TypeCode != TypeCodeObject {
+		return errInvalidJSONSchema("schema must be an object")
+	}
+	elemCount := schema.GetElemCount()
+	for i := 0; i < elemCount; i++ {
+		key, val := string(schema.objectGetKey(i)), schema.objectGetVal(i)
+		switch key {
+		case "type":
+			if err := checkSchemaType(val); err != nil {
+				return err
+			}
+		case "enum":
+			if val.TypeCode != TypeCodeArray || val.GetElemCount() == 0 {
+				return errInvalidJSONSchema("'enum' must be a non-empty array")
+			}
+		case "required":
+			if val.TypeCode != TypeCodeArray {
+				return errInvalidJSONSchema("'required' must be an array of strings")
+			}
+			for j := 0; j < val.GetElemCount(); j++ {
+				if val.arrayGetElem(j).TypeCode != TypeCodeString {
+					return errInvalidJSONSchema("'required' must be an array of strings")
+				}
+			}
+		case "properties":
+			if val.TypeCode != TypeCodeObject {
+				return errInvalidJSONSchema("'properties' must be an object")
+			}
+			for j := 0; j < val.GetElemCount(); j++ {
+				if err := checkSchema(val.objectGetVal(j)); err != nil {
+					return err
+				}
+			}
+		case "items":
+			if err := checkSchema(val); err != nil {
+				return err
+			}
+		case "minimum", "maximum":
+			if _, ok := schemaNumber(val); !ok {
+				return errInvalidJSONSchema("'%s' must be a number", key)
+			}
+		case "minLength", "maxLength", "minItems", "maxItems":
+			if n, ok := schemaNumber(val); !ok || n < 0 || n != math.Trunc(n) {
+				return errInvalidJSONSchema("'%s' must be a non-negative integer", key)
+			}
+		}
+	}
+	return nil
+}
+
+func checkSchemaType(val BinaryJSON) error {
+	switch val.TypeCode {
+	case TypeCodeString:
+		if _, ok := jsonSchemaTypes[string(val.GetString())]; ok {
+			return nil
+		}
+		return errInvalidJSONSchema("unknown type '%s'", val.GetString())
+	case TypeCodeArray:
+		for i := 0; i < val.GetElemCount(); i++ {
+			if err := checkSchemaType(val.arrayGetElem(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return errInvalidJSONSchema("'type' must be a string or an array of strings")
+}
+
+// matchSchema matches the document against a schema which has already passed checkSchema.
+func matchSchema(schema, doc BinaryJSON) bool {
+	elemCount := schema.GetElemCount()
+	for i := 0; i < elemCount; i++ {
+		key, val := string(schema.objectGetKey(i)), schema.objectGetVal(i)
+		matched := true
+		switch key {
+		case "type":
+			matched = matchSchemaType(val, doc)
+		case "enum":
+			matched = false
+			for j := 0; j < val.GetElemCount() && !matched; j++ {
+				matched = CompareBinary(val.arrayGetElem(j), doc) == 0
+			}
+		case "required":
+			if doc.TypeCode == TypeCodeObject {
+				for j := 0; j < val.GetElemCount() && matched; j++ {
+					_, matched = doc.objectSearchKey(val.arrayGetElem(j).GetString())
+				}
+			}
+		case "properties":
+			if doc.TypeCode == TypeCodeObject {
+				for j := 0; j < val.GetElemCount() && matched; j++ {
+					if prop, exists := doc.objectSearchKey(val.objectGetKey(j)); exists {
+						matched = matchSchema(val.objectGetVal(j), prop)
+					}
+				}
+			}
+		case "items":
+			if doc.TypeCode == TypeCodeArray {
+				for j := 0; j < doc.GetElemCount() && matched; j++ {
+					matched = matchSchema(val, doc.arrayGetElem(j))
+				}
+			}
+		case "minimum", "maximum":
+			if n, ok := schemaNumber(doc); ok {
+				bound, _ := schemaNumber(val)
+				matched = (key == "minimum" && n >= bound) || (key == "maximum" && n <= bound)
+			}
+		case "minLength", "maxLength":
+			if doc.TypeCode == TypeCodeString {
+				matched = matchSchemaBound(key == "minLength", utf8.RuneCount(doc.GetString()), val)
+			}
+		case "minItems", "maxItems":
+			if doc.TypeCode == TypeCodeArray {
+				matched = matchSchemaBound(key == "minItems", doc.GetElemCount(), val)
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func matchSchemaType(tp, doc BinaryJSON) bool {
+	if tp.TypeCode == TypeCodeArray {
+		for i := 0; i < tp.GetElemCount(); i++ {
+			if matchSchemaType(tp.arrayGetElem(i), doc) {
+				return true
+			}
+		}
+		return false
+	}
+	switch string(tp.GetString()) {
+	case "object":
+		return doc.TypeCode == TypeCodeObject
+	case "array":
+		return doc.TypeCode == TypeCodeArray
+	case "string":
+		return doc.TypeCode == TypeCodeString
+	case "number":
+		_, ok := schemaNumber(doc)
+		return ok
+	case "integer":
+		n, ok := schemaNumber(doc)
+		return ok && n == math.Trunc(n)
+	case "boolean":
+		return doc.TypeCode == TypeCodeLiteral && doc.Value[0] != LiteralNil
+	case "null":
+		return doc.TypeCode == TypeCodeLiteral && doc.Value[0] == LiteralNil
+	}
+	return false
+}
+
+func matchSchemaBound(isMin bool, n int, bound BinaryJSON) bool {
+	b, _ := schemaNumber(bound)
+	if isMin {
+		return float64(n) >= b
+	}
+	return float64(n) <= b
+}
+
+// schemaNumber returns the numeric value of the JSON scalar, ok is false if it's not a number.
+func schemaNumber(bj BinaryJSON) (n float64, ok bool) {
+	switch bj.TypeCode {
+	case TypeCodeInt64:
+		return float64(bj.GetInt64()), true
+	case TypeCodeUint64:
+		return float64(bj.GetUint64()), true
+	case TypeCodeFloat64:
+		return bj.GetFloat64(), true
+	}
+	return 0, false
+}
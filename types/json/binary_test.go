@@ -400,6 +400,61 @@ func TestBinaryJSONContains(t *testing.T) {
 	}
 }
 
+func TestValidateBinarySchema(t *testing.T) {
+	t.Parallel()
+
+	schema := `{"type": "object", "required": ["id", "name"], "properties": {
+		"id": {"type": "integer", "minimum": 1},
+		"name": {"type": "string", "minLength": 1, "maxLength": 8},
+		"tags": {"type": "array", "items": {"enum": ["a", "b"]}, "maxItems": 2},
+		"score": {"type": ["number", "null"], "maximum": 100}}}`
+	var tests = []struct {
+		doc      string
+		expected bool
+	}{
+		{`{"id": 1, "name": "tidb"}`, true},
+		{`{"id": 2.0, "name": "tidb", "tags": ["a", "b"], "score": null}`, true},
+		{`{"id": 3, "name": "tidb", "score": 99.5, "other": [1, 2, 3]}`, true},
+		{`{"id": 1}`, false},
+		{`{"id": 0, "name": "tidb"}`, false},
+		{`{"id": 1.5, "name": "tidb"}`, false},
+		{`{"id": "1", "name": "tidb"}`, false},
+		{`{"id": 1, "name": ""}`, false},
+		{`{"id": 1, "name": "distributed"}`, false},
+		{`{"id": 1, "name": "tidb", "tags": ["a", "c"]}`, false},
+		{`{"id": 1, "name": "tidb", "tags": ["a", "b", "a"]}`, false},
+		{`{"id": 1, "name": "tidb", "score": 101}`, false},
+		{`[{"id": 1, "name": "tidb"}]`, false},
+	}
+	for _, test := range tests {
+		ok, err := ValidateBinarySchema(mustParseBinaryFromString(t, schema), mustParseBinaryFromString(t, test.doc))
+		require.NoError(t, err)
+		require.Equal(t, test.expected, ok, test.doc)
+	}
+
+	// Keywords only apply to the documents of their own types.
+	ok, err := ValidateBinarySchema(mustParseBinaryFromString(t, `{"minimum": 5, "minLength": 2}`), mustParseBinaryFromString(t, `"abc"`))
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	invalidSchemas := []string{
+		`[]`,
+		`{"type": "int"}`,
+		`{"type": 1}`,
+		`{"required": "id"}`,
+		`{"enum": []}`,
+		`{"minimum": "1"}`,
+		`{"maxLength": -1}`,
+		`{"properties": {"id": {"type": "text"}}}`,
+		`{"items": 1}`,
+	}
+	for _, schema := range invalidSchemas {
+		_, err := ValidateBinarySchema(mustParseBinaryFromString(t, schema), mustParseBinaryFromString(t, `{}`))
+		require.Error(t, err, schema)
+		require.True(t, ErrInvalidJSONText.Equal(err))
+	}
+}
+
 func TestBinaryJSONCopy(t *testing.T) {
 	t.Parallel()
 
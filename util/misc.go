@@ -437,12 +437,19 @@ func init() {
 // GetSequenceByName could be used in expression package without import cycle problem.
 var GetSequenceByName func(is interface{}, schema, sequence model.CIStr) (SequenceTable, error)
 
+// GetTableInfoByName could be used in expression package without import cycle problem.
+var GetTableInfoByName func(is interface{}, schema, table model.CIStr) (*model.TableInfo, error)
+
+// GetTableNameByID could be used in expression package without import cycle problem.
+var GetTableNameByID func(is interface{}, tableID int64) (string, bool)
+
 // SequenceTable is implemented by tableCommon,
 // and it is specialised in handling sequence operation.
 // Otherwise calling table will cause import cycle problem.
 type SequenceTable interface {
 	GetSequenceID() int64
 	GetSequenceNextVal(ctx interface{}, dbName, seqName string) (int64, error)
+	GetSequenceNextValN(ctx interface{}, dbName, seqName string, n int64) (int64, int64, error)
 	SetSequenceVal(ctx interface{}, newVal int64, dbName, seqName string) (int64, bool, error)
 }
 
@@ -102,10 +102,17 @@ func (c *Context) ExecuteInternal(ctx context.Context, sql string, args ...inter
 	return nil, errors.Errorf("Not Supported.")
 }
 
+// mockDDLOwnerID is the owner ID reported by the mocked DDL owner checker, which is always the owner.
+const mockDDLOwnerID = "mock_ddl_owner"
+
 type mockDDLOwnerChecker struct{}
 
 func (c *mockDDLOwnerChecker) IsOwner() bool { return true }
 
+func (c *mockDDLOwnerChecker) GetOwnerID(ctx context.Context) (string, error) {
+	return mockDDLOwnerID, nil
+}
+
 // DDLOwnerChecker returns owner.DDLOwnerChecker.
 func (c *Context) DDLOwnerChecker() owner.DDLOwnerChecker {
 	return &mockDDLOwnerChecker{}
@@ -70,6 +70,21 @@ func GetTiDBInfo() string {
 		config.CheckTableBeforeDrop)
 }
 
+// GetTiDBInfoMap returns the same information as GetTiDBInfo, keyed by the snake case names.
+func GetTiDBInfoMap() map[string]interface{} {
+	return map[string]interface{}{
+		"release_version":         mysql.TiDBReleaseVersion,
+		"edition":                 versioninfo.TiDBEdition,
+		"git_hash":                versioninfo.TiDBGitHash,
+		"git_branch":              versioninfo.TiDBGitBranch,
+		"build_time":              versioninfo.TiDBBuildTS,
+		"go_version":              buildVersion,
+		"race_enabled":            israce.RaceEnabled,
+		"tikv_min_version":        versioninfo.TiKVMinVersion,
+		"check_table_before_drop": config.CheckTableBeforeDrop,
+	}
+}
+
 // checkValidity checks whether cols and every data have the same length.
 func checkValidity(cols []string, datas [][]string) bool {
 	colLen := len(cols)
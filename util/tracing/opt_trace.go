@@ -30,6 +30,8 @@ type LogicalOptimizeTracer struct {
 	Steps            []*LogicalRuleOptimizeTracer `json:"steps"`
 	// curRuleTracer indicates the current rule Tracer during optimize by rule
 	curRuleTracer *LogicalRuleOptimizeTracer
+	// curSubRuleTracers indicates the Tracers of the sub rules applied by the current rule
+	curSubRuleTracers map[string]*LogicalRuleOptimizeTracer
 }
 
 // AppendRuleTracerBeforeRuleOptimize add plan tracer before optimize
@@ -37,6 +39,7 @@ func (tracer *LogicalOptimizeTracer) AppendRuleTracerBeforeRuleOptimize(index in
 	ruleTracer := buildLogicalRuleOptimizeTracerBeforeOptimize(index, name, before)
 	tracer.Steps = append(tracer.Steps, ruleTracer)
 	tracer.curRuleTracer = ruleTracer
+	tracer.curSubRuleTracers = nil
 }
 
 // AppendRuleTracerStepToCurrent add rule optimize step to current
@@ -51,6 +54,28 @@ func (tracer *LogicalOptimizeTracer) AppendRuleTracerStepToCurrent(id int, tp, r
 	})
 }
 
+// AppendRuleTracerStepToSubRule add rule optimize step to the sub rule named name, which is applied by the current
+// rule, e.g. the constant propagation applied by the predicate push down. The sub rule shares the index and the
+// plan before optimize with the current rule.
+func (tracer *LogicalOptimizeTracer) AppendRuleTracerStepToSubRule(name string, id int, tp, reason, action string) {
+	subTracer, ok := tracer.curSubRuleTracers[name]
+	if !ok {
+		subTracer = buildLogicalRuleOptimizeTracerBeforeOptimize(tracer.curRuleTracer.Index, name, tracer.curRuleTracer.Before)
+		tracer.Steps = append(tracer.Steps, subTracer)
+		if tracer.curSubRuleTracers == nil {
+			tracer.curSubRuleTracers = make(map[string]*LogicalRuleOptimizeTracer)
+		}
+		tracer.curSubRuleTracers[name] = subTracer
+	}
+	subTracer.Steps = append(subTracer.Steps, LogicalRuleOptimizeTraceStep{
+		ID:     id,
+		TP:     tp,
+		Reason: reason,
+		Action: action,
+		Index:  len(subTracer.Steps),
+	})
+}
+
 // RecordFinalLogicalPlan add plan trace after logical optimize
 func (tracer *LogicalOptimizeTracer) RecordFinalLogicalPlan(final *LogicalPlanTrace) {
 	tracer.FinalLogicalPlan = final
@@ -85,6 +110,57 @@ type LogicalRuleOptimizeTraceStep struct {
 	Index  int    `json:"index"`
 }
 
+// PhysicalOptimizeTracer indicates the trace for the whole physicalOptimize processing
+type PhysicalOptimizeTracer struct {
+	Steps []PhysicalOptimizeTraceStep `json:"steps"`
+}
+
+// AppendStep add physical optimize step to the tracer
+func (tracer *PhysicalOptimizeTracer) AppendStep(id int, tp, reason, action string) {
+	tracer.Steps = append(tracer.Steps, PhysicalOptimizeTraceStep{
+		ID:     id,
+		TP:     tp,
+		Reason: reason,
+		Action: action,
+		Index:  len(tracer.Steps),
+	})
+}
+
+// PhysicalOptimizeTraceStep indicates the trace for the detailed decision made during
+// physical optimize
+type PhysicalOptimizeTraceStep struct {
+	Action string `json:"action"`
+	Reason string `json:"reason"`
+	ID     int    `json:"id"`
+	TP     string `json:"type"`
+	Index  int    `json:"index"`
+}
+
+// PlanCacheParamTracer indicates the trace for the parameters bound to a prepared statement by execute
+type PlanCacheParamTracer struct {
+	UseCache bool                  `json:"use_plan_cache"`
+	Params   []PlanCacheParamTrace `json:"params"`
+}
+
+// AppendParam add a bound parameter to the tracer
+func (tracer *PlanCacheParamTracer) AppendParam(order, offset int, tp, value string) {
+	tracer.Params = append(tracer.Params, PlanCacheParamTrace{
+		Order:  order,
+		Offset: offset,
+		Type:   tp,
+		Value:  value,
+	})
+}
+
+// PlanCacheParamTrace indicates a parameter marker of the prepared statement and the value bound to it,
+// the type of the value is a part of the plan cache key
+type PlanCacheParamTrace struct {
+	Order  int    `json:"order"`
+	Offset int    `json:"offset"`
+	Type   string `json:"type"`
+	Value  string `json:"value"`
+}
+
 // CETraceRecord records an expression and related cardinality estimation result.
 type CETraceRecord struct {
 	TableID   int64  `json:"-"`
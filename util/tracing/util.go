@@ -16,6 +16,7 @@ package tracing
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/opentracing/basictracer-go"
 	"github.com/opentracing/opentracing-go"
@@ -55,6 +56,19 @@ func SpanFromContext(ctx context.Context) (sp opentracing.Span) {
 	return sp
 }
 
+// TraceIDFromContext returns the trace id of the span obtained from the context as a hex string.
+// An empty string is returned if there is no span or the span is not recorded by a basictracer.
+func TraceIDFromContext(ctx context.Context) string {
+	sp := opentracing.SpanFromContext(ctx)
+	if sp == nil {
+		return ""
+	}
+	if spCtx, ok := sp.Context().(basictracer.SpanContext); ok && spCtx.TraceID != 0 {
+		return fmt.Sprintf("%016x", spCtx.TraceID)
+	}
+	return ""
+}
+
 // ChildSpanFromContxt return a non-nil span. If span can be got from ctx, then returned span is
 // a child of such span. Otherwise, returned span is a noop span.
 func ChildSpanFromContxt(ctx context.Context, opName string) (opentracing.Span, context.Context) {
@@ -16,6 +16,7 @@ package tracing_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	basictracer "github.com/opentracing/basictracer-go"
@@ -131,3 +132,16 @@ func TestTreeRelationship(t *testing.T) {
 		require.Equal(t, collectedSpans[1].Context.SpanID, collectedSpans[2].ParentSpanID)
 	}
 }
+
+func TestTraceIDFromContext(t *testing.T) {
+	ctx := context.TODO()
+	require.Equal(t, "", tracing.TraceIDFromContext(ctx))
+
+	noopSp := opentracing.NoopTracer{}.StartSpan("noop")
+	require.Equal(t, "", tracing.TraceIDFromContext(opentracing.ContextWithSpan(ctx, noopSp)))
+
+	sp := basictracer.New(basictracer.NewInMemoryRecorder()).StartSpan("test")
+	defer sp.Finish()
+	traceID := sp.Context().(basictracer.SpanContext).TraceID
+	require.Equal(t, fmt.Sprintf("%016x", traceID), tracing.TraceIDFromContext(opentracing.ContextWithSpan(ctx, sp)))
+}